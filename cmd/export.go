@@ -0,0 +1,65 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/export"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/server"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+	exportFilter model.GeospatialFilter
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export regions to a GeoPackage or FlatGeobuf file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !export.IsSupported(exportFormat) {
+			return fmt.Errorf("--format must be one of %s", strings.Join(export.Formats, ", "))
+		}
+
+		output := exportOutput
+		if output == "" {
+			output = "geospatial." + exportFormat
+		}
+
+		logger.InitLogger()
+
+		geospatialRepo := server.InitGeospatialRepository()
+		exportService := service.NewExportService(geospatialRepo, 1)
+
+		rows, err := exportService.Export(context.Background(), exportFilter, exportFormat, output)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Exported %d regions to %s\n", rows, output)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", fmt.Sprintf("output format (%s)", strings.Join(export.Formats, "|")))
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file (default is geospatial.<format> in current directory)")
+	exportCmd.Flags().StringVar(&exportFilter.Name, "name", "", "only export regions whose name contains this value")
+	exportCmd.Flags().UintSliceVar(&exportFilter.Levels, "levels", nil, "only export regions of these levels")
+	exportCmd.Flags().StringSliceVar(&exportFilter.Types, "types", nil, "only export regions of these types")
+	exportCmd.Flags().UintSliceVar(&exportFilter.ParentIds, "parentIds", nil, "only export direct children of these region ids")
+	exportCmd.MarkFlagRequired("format")
+}
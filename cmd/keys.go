@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/server"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/spf13/cobra"
+)
+
+var (
+	keyName       string
+	keyRole       string
+	keyRate       float64
+	keyBurst      int
+	keyDailyQuota int
+)
+
+// keyLimit returns the rate limit fields whose flags were given.
+func keyLimit(cmd *cobra.Command) model.RateLimit {
+	var limit model.RateLimit
+	if cmd.Flags().Changed("rate") {
+		limit.Rate = &keyRate
+	}
+	if cmd.Flags().Changed("burst") {
+		limit.Burst = &keyBurst
+	}
+	if cmd.Flags().Changed("daily-quota") {
+		limit.DailyQuota = &keyDailyQuota
+	}
+	return limit
+}
+
+func addLimitFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(&keyRate, "rate", 0, "tokens the key gets back per second, 0 is unlimited (default from RateLimit.Rate)")
+	cmd.Flags().IntVar(&keyBurst, "burst", 0, "tokens the key can save up (default from RateLimit.Burst)")
+	cmd.Flags().IntVar(&keyDailyQuota, "daily-quota", 0, "tokens the key can spend per UTC day, 0 is unlimited (default from RateLimit.DailyQuota)")
+}
+
+// newAuthService returns the service managing the keys of the configured database.
+func newAuthService() (service.AuthService, error) {
+	if config.Config.Db.Driver == constant.DbDriverMemory {
+		return nil, errors.New("the memory backend does not keep api keys, use a database or JWTs")
+	}
+
+	logger.InitLogger()
+	repos := server.InitRepositories()
+	return service.NewAuthService(repos.APIKey, config.Config.Auth.JWTSecret, config.Config.Auth.JWTIssuer), nil
+}
+
+// keysCmd represents the keys command
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the API keys clients authenticate with",
+}
+
+var keysCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key, the key is only shown once",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		authService, err := newAuthService()
+		if err != nil {
+			return err
+		}
+
+		key, err := authService.CreateKey(context.Background(), strings.TrimSpace(keyName), keyRole, keyLimit(cmd))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Created key %d %q with the %s role, it is not shown again:\n", key.ID, key.Name, key.Role)
+		fmt.Println(key.Key)
+		return nil
+	},
+}
+
+var keysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the API keys",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		authService, err := newAuthService()
+		if err != nil {
+			return err
+		}
+
+		keys, err := authService.ListKeys(context.Background())
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tROLE\tRATE\tBURST\tDAILY QUOTA\tCREATED\tREVOKED")
+		for _, key := range keys {
+			revoked := ""
+			if key.RevokedAt != nil {
+				revoked = key.RevokedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix, key.Role,
+				formatLimit(key.Limit.Rate), formatLimit(key.Limit.Burst), formatLimit(key.Limit.DailyQuota),
+				key.CreatedAt.Format(time.RFC3339), revoked)
+		}
+		return w.Flush()
+	},
+}
+
+var keysRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return errors.New("id must be an integer")
+		}
+
+		authService, err := newAuthService()
+		if err != nil {
+			return err
+		}
+
+		if err := authService.RevokeKey(context.Background(), uint(id)); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Revoked key %d\n", id)
+		return nil
+	},
+}
+
+var keysLimitCmd = &cobra.Command{
+	Use:   "limit <id>",
+	Short: "Replace the rate limit of an API key, the flags left out go back to the default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return errors.New("id must be an integer")
+		}
+
+		authService, err := newAuthService()
+		if err != nil {
+			return err
+		}
+
+		key, err := authService.SetKeyLimit(context.Background(), uint(id), keyLimit(cmd))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Limited key %d to rate %s, burst %s and daily quota %s\n", key.ID,
+			formatLimit(key.Limit.Rate), formatLimit(key.Limit.Burst), formatLimit(key.Limit.DailyQuota))
+		return nil
+	},
+}
+
+// formatLimit prints a rate limit field, "default" when it is not set.
+func formatLimit[T float64 | int](v *T) string {
+	if v == nil {
+		return "default"
+	}
+	return fmt.Sprint(*v)
+}
+
+func init() {
+	rootCmd.AddCommand(keysCmd)
+	keysCmd.AddCommand(keysCreateCmd, keysListCmd, keysRevokeCmd, keysLimitCmd)
+
+	keysCreateCmd.Flags().StringVar(&keyName, "name", "", "what the key is for, e.g. the client using it")
+	keysCreateCmd.Flags().StringVar(&keyRole, "role", "", fmt.Sprintf("role of the key (%s)", strings.Join(constant.Roles, "|")))
+	keysCreateCmd.MarkFlagRequired("name")
+	keysCreateCmd.MarkFlagRequired("role")
+	addLimitFlags(keysCreateCmd)
+	addLimitFlags(keysLimitCmd)
+}
@@ -7,26 +7,48 @@ var Config *Cfg
 var TimeLocation *time.Location
 
 type Cfg struct {
-	App  AppConfig
-	Db   DB
-	Data Data
+	App       AppConfig
+	Db        DB
+	Data      Data
+	Export    Export
+	Webhook   Webhook
+	Auth      Auth
+	RateLimit RateLimit
 }
 
 type AppConfig struct {
 	Name     string
 	Url      string
 	Port     int
+	GRPCPort int
 	Env      string
 	Debug    bool
 	Timezone string
 }
 
 type DB struct {
+	Driver     string
 	Host       string
 	Port       int
 	Username   string
 	Password   string
 	Name       string
+	SSLMode    string
+	Path       string
+	Connection DbConn
+	// Replicas serve the reads, fields left empty fall back to the primary's.
+	Replicas []DbReplica
+	// HealthCheck is the interval in milliseconds between replica health checks, default 5000.
+	HealthCheck int
+}
+
+type DbReplica struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Name       string
+	SSLMode    string
 	Connection DbConn
 }
 
@@ -39,3 +61,46 @@ type DbConn struct {
 type Data struct {
 	MaxRows uint
 }
+
+type Export struct {
+	Dir string
+	// TTL is the time in milliseconds a finished job and its file are kept, default 86400000 (24 hours).
+	TTL int
+	// Concurrency is how many jobs run at once, the others wait pending, default 2.
+	Concurrency int
+}
+
+type Webhook struct {
+	// Interval is the time in milliseconds between two dispatches of the outbox, default 5000.
+	Interval int
+	// Timeout is the time in milliseconds a receiver has to answer, default 10000.
+	Timeout int
+	// MaxAttempts is how often a delivery is tried before it fails, default 8.
+	MaxAttempts int
+}
+
+type Auth struct {
+	// JWTSecret verifies the HS256 signature of bearer JWTs, JWTs are rejected when it is empty.
+	JWTSecret string
+	// JWTIssuer is the iss claim JWTs must have, any issuer is accepted when it is empty.
+	JWTIssuer string
+	// ProtectReads requires the reader role for the read routes too, they are public otherwise.
+	ProtectReads bool
+}
+
+type RateLimit struct {
+	// Rate is how many tokens a client gets back per second, requests are not limited when it
+	// is 0. Clients are API keys, JWT subjects or, without credentials, IP addresses.
+	Rate float64
+	// Burst is how many tokens a client can save up, default 10 seconds of Rate.
+	Burst int
+	// DailyQuota is how many tokens a client can spend per UTC day, unlimited when it is 0.
+	DailyQuota int
+	// Costs overrides the tokens of a route, keyed by method and path as registered, like
+	// "POST /v1/geometry/union", or by gRPC method, like
+	// "/geospatial.v1.GeospatialService/BatchReverse". Keys are matched case-insensitively.
+	Costs map[string]int
+	// TrustedProxies are the addresses or CIDRs whose X-Forwarded-For header names the client,
+	// without them the client is the address connecting.
+	TrustedProxies []string
+}
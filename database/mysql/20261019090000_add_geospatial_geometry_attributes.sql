@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE geospatial
+    ADD COLUMN `centroid_lat` DOUBLE NULL AFTER `geometry`,
+    ADD COLUMN `centroid_lng` DOUBLE NULL AFTER `centroid_lat`,
+    ADD COLUMN `label_lat` DOUBLE NULL AFTER `centroid_lng`,
+    ADD COLUMN `label_lng` DOUBLE NULL AFTER `label_lat`,
+    ADD COLUMN `area_km2` DOUBLE NULL AFTER `label_lng`,
+    ADD COLUMN `perimeter_km` DOUBLE NULL AFTER `area_km2`,
+    ADD COLUMN `bbox_min_lng` DOUBLE NULL AFTER `perimeter_km`,
+    ADD COLUMN `bbox_min_lat` DOUBLE NULL AFTER `bbox_min_lng`,
+    ADD COLUMN `bbox_max_lng` DOUBLE NULL AFTER `bbox_min_lat`,
+    ADD COLUMN `bbox_max_lat` DOUBLE NULL AFTER `bbox_max_lng`,
+    ADD KEY `idx_area_km2` (`area_km2`);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE geospatial
+    DROP KEY `idx_area_km2`,
+    DROP COLUMN `centroid_lat`,
+    DROP COLUMN `centroid_lng`,
+    DROP COLUMN `label_lat`,
+    DROP COLUMN `label_lng`,
+    DROP COLUMN `area_km2`,
+    DROP COLUMN `perimeter_km`,
+    DROP COLUMN `bbox_min_lng`,
+    DROP COLUMN `bbox_min_lat`,
+    DROP COLUMN `bbox_max_lng`,
+    DROP COLUMN `bbox_max_lat`;
+
+-- +goose StatementEnd
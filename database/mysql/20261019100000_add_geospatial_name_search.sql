@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE geospatial
+    ADD COLUMN `search_name` VARCHAR(255) NOT NULL DEFAULT '' AFTER `name`,
+    ADD KEY `idx_search_name` (`search_name`);
+
+-- Approximation until the next import, which folds diacritics and fills geospatial_trigram.
+UPDATE geospatial SET `search_name` = LOWER(`name`);
+
+CREATE TABLE geospatial_trigram (
+    `geospatial_id` INT NOT NULL,
+    `trigram` VARCHAR(3) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+    PRIMARY KEY (`trigram`, `geospatial_id`),
+    KEY `idx_geospatial_id` (`geospatial_id`)
+) ENGINE = InnoDB;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geospatial_trigram;
+
+ALTER TABLE geospatial
+    DROP KEY `idx_search_name`,
+    DROP COLUMN `search_name`;
+
+-- +goose StatementEnd
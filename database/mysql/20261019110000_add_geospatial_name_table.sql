@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE geospatial_name (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `geospatial_id` INT NOT NULL,
+    `name` VARCHAR(255) NOT NULL,
+    `search_name` VARCHAR(255) NOT NULL,
+    `language` VARCHAR(35) NOT NULL DEFAULT '',
+    `kind` VARCHAR(20) NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_geospatial_id` (`geospatial_id`),
+    KEY `idx_search_name` (`search_name`),
+    KEY `idx_language` (`language`)
+) ENGINE = InnoDB;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geospatial_name;
+
+-- +goose StatementEnd
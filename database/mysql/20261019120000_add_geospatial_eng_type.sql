@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE geospatial
+    ADD COLUMN `eng_type` VARCHAR(20) NOT NULL DEFAULT 'OTHER' AFTER `type`,
+    ADD KEY `idx_eng_type_level` (`eng_type`, `level`);
+
+-- The other levels are typed by the next import, which reads ENGTYPE_n.
+UPDATE geospatial SET `eng_type` = 'COUNTRY' WHERE `level` = 1;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE geospatial
+    DROP KEY `idx_eng_type_level`,
+    DROP COLUMN `eng_type`;
+
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The parent and ancestor filters walk the hierarchy through parent_gadm_id.
+ALTER TABLE geospatial ADD KEY `idx_parent_gadm_id` (`parent_gadm_id`);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE geospatial DROP KEY `idx_parent_gadm_id`;
+
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE geospatial_neighbor (
+    `geospatial_id` INT NOT NULL,
+    `neighbor_id` INT NOT NULL,
+    `border_km` DOUBLE NOT NULL,
+    PRIMARY KEY (`geospatial_id`, `neighbor_id`),
+    KEY `idx_neighbor_id` (`neighbor_id`)
+) ENGINE = InnoDB;
+
+-- Every region is indexed by the next import.
+ALTER TABLE geospatial
+    ADD COLUMN `neighbors_stale` TINYINT(1) NOT NULL DEFAULT 1,
+    ADD KEY `idx_neighbors_stale` (`neighbors_stale`);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE geospatial
+    DROP KEY `idx_neighbors_stale`,
+    DROP COLUMN `neighbors_stale`;
+
+DROP TABLE geospatial_neighbor;
+
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE layer (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `name` VARCHAR(255) NOT NULL UNIQUE,
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`)
+) ENGINE = InnoDB;
+
+CREATE TABLE layer_feature (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `layer_id` INT NOT NULL,
+    -- The GeoJSON id, features without one are added by every import.
+    `feature_id` VARCHAR(255) NULL,
+    `name` VARCHAR(255) NOT NULL DEFAULT '',
+    `properties` JSON NOT NULL,
+    `geometry` MULTIPOLYGON NOT NULL,
+    `area_km2` DOUBLE NULL,
+    `bbox_min_lng` DOUBLE NULL,
+    `bbox_min_lat` DOUBLE NULL,
+    `bbox_max_lng` DOUBLE NULL,
+    `bbox_max_lat` DOUBLE NULL,
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_layer_feature_id` (`layer_id`, `feature_id`),
+    SPATIAL INDEX (`geometry`)
+) ENGINE = InnoDB;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE layer_feature;
+DROP TABLE layer;
+
+-- +goose StatementEnd
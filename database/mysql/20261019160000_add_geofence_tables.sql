@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- MySQL only indexes NOT NULL geometries and region fences have none, polygon fences are
+-- few enough to be checked without a spatial index.
+CREATE TABLE geofence (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `name` VARCHAR(255) NOT NULL,
+    -- A fence is either a region or its own polygon.
+    `geospatial_id` INT NULL,
+    `geometry` MULTIPOLYGON NULL,
+    `bbox_min_lng` DOUBLE NULL,
+    `bbox_min_lat` DOUBLE NULL,
+    `bbox_max_lng` DOUBLE NULL,
+    `bbox_max_lat` DOUBLE NULL,
+    `dwell_seconds` INT UNSIGNED NOT NULL DEFAULT 0,
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_geospatial_id` (`geospatial_id`)
+) ENGINE = InnoDB;
+
+-- The last position of every tracked object.
+CREATE TABLE geofence_object (
+    `object_id` VARCHAR(255) NOT NULL,
+    `lat` DOUBLE NOT NULL,
+    `lng` DOUBLE NOT NULL,
+    `seen_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`object_id`)
+) ENGINE = InnoDB;
+
+-- The fences a tracked object is in, since when and whether its dwell event was sent.
+CREATE TABLE geofence_state (
+    `object_id` VARCHAR(255) NOT NULL,
+    `geofence_id` INT NOT NULL,
+    `entered_at` datetime(3) NOT NULL,
+    `dwelled` TINYINT(1) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`object_id`, `geofence_id`),
+    KEY `idx_geofence_id` (`geofence_id`)
+) ENGINE = InnoDB;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geofence_state;
+DROP TABLE geofence_object;
+DROP TABLE geofence;
+
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Events are written here in the transaction of the change they describe, the dispatcher
+-- creates their deliveries and sets dispatched_at.
+CREATE TABLE outbox_event (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `type` VARCHAR(64) NOT NULL,
+    `payload` JSON NOT NULL,
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `dispatched_at` datetime NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_dispatched_at` (`dispatched_at`, `id`)
+) ENGINE = InnoDB;
+
+CREATE TABLE webhook (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `url` VARCHAR(2048) NOT NULL,
+    `secret` VARCHAR(128) NOT NULL,
+    -- Comma separated event types, empty for every type.
+    `events` VARCHAR(1024) NOT NULL DEFAULT '',
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`)
+) ENGINE = InnoDB;
+
+-- One delivery of an event to a webhook, with the outcome of its last attempt.
+CREATE TABLE webhook_delivery (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `webhook_id` INT NOT NULL,
+    `event_id` INT NOT NULL,
+    `event_type` VARCHAR(64) NOT NULL,
+    `status` VARCHAR(16) NOT NULL,
+    `attempts` INT UNSIGNED NOT NULL DEFAULT 0,
+    `next_attempt_at` datetime(3) NOT NULL,
+    `status_code` INT NULL,
+    `error` VARCHAR(1024) NOT NULL DEFAULT '',
+    `delivered_at` datetime(3) NULL,
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_webhook_event` (`webhook_id`, `event_id`),
+    KEY `idx_status` (`status`, `next_attempt_at`)
+) ENGINE = InnoDB;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_delivery;
+DROP TABLE webhook;
+DROP TABLE outbox_event;
+
+-- +goose StatementEnd
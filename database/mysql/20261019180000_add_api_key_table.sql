@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys clients authenticate with, only the SHA-256 of a key is stored.
+CREATE TABLE api_key (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `name` VARCHAR(255) NOT NULL,
+    `prefix` VARCHAR(16) NOT NULL,
+    `hash` CHAR(64) NOT NULL,
+    `role` VARCHAR(16) NOT NULL,
+    `revoked_at` datetime NULL,
+    `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_hash` (`hash`)
+) ENGINE = InnoDB;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_key;
+
+-- +goose StatementEnd
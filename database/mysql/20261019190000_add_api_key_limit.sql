@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Rate limits of a key, NULL keeps the default of the service.
+ALTER TABLE api_key
+    ADD COLUMN `limit_rate` DOUBLE NULL AFTER `revoked_at`,
+    ADD COLUMN `limit_burst` INT NULL AFTER `limit_rate`,
+    ADD COLUMN `limit_daily_quota` INT NULL AFTER `limit_burst`;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE api_key
+    DROP COLUMN `limit_rate`,
+    DROP COLUMN `limit_burst`,
+    DROP COLUMN `limit_daily_quota`;
+
+-- +goose StatementEnd
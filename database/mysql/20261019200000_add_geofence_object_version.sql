@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Counts the saves of a tracked object, a save fails when another one happened since it was read.
+ALTER TABLE geofence_object
+    ADD COLUMN `version` INT UNSIGNED NOT NULL DEFAULT 1 AFTER `seen_at`;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE geofence_object
+    DROP COLUMN `version`;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+CREATE TABLE geospatial (
+    id SERIAL NOT NULL,
+    gadm_id VARCHAR(255) NOT NULL UNIQUE,
+    parent_gadm_id VARCHAR(255) NULL,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(255) NOT NULL,
+    level SMALLINT NOT NULL CHECK (
+        level BETWEEN 1
+        AND 5
+    ),
+    geometry geometry(MultiPolygon, 4326) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_geospatial_geometry ON geospatial USING GIST (geometry);
+CREATE INDEX idx_geospatial_parent_gadm_id ON geospatial (parent_gadm_id);
+CREATE INDEX idx_geospatial_created_at ON geospatial (created_at);
+CREATE INDEX idx_geospatial_updated_at ON geospatial (updated_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geospatial;
+
+-- +goose StatementEnd
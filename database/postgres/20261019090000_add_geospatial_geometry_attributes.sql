@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE geospatial
+    ADD COLUMN centroid_lat DOUBLE PRECISION NULL,
+    ADD COLUMN centroid_lng DOUBLE PRECISION NULL,
+    ADD COLUMN label_lat DOUBLE PRECISION NULL,
+    ADD COLUMN label_lng DOUBLE PRECISION NULL,
+    ADD COLUMN area_km2 DOUBLE PRECISION NULL,
+    ADD COLUMN perimeter_km DOUBLE PRECISION NULL,
+    ADD COLUMN bbox_min_lng DOUBLE PRECISION NULL,
+    ADD COLUMN bbox_min_lat DOUBLE PRECISION NULL,
+    ADD COLUMN bbox_max_lng DOUBLE PRECISION NULL,
+    ADD COLUMN bbox_max_lat DOUBLE PRECISION NULL;
+
+CREATE INDEX idx_geospatial_area_km2 ON geospatial (area_km2);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_geospatial_area_km2;
+
+ALTER TABLE geospatial
+    DROP COLUMN centroid_lat,
+    DROP COLUMN centroid_lng,
+    DROP COLUMN label_lat,
+    DROP COLUMN label_lng,
+    DROP COLUMN area_km2,
+    DROP COLUMN perimeter_km,
+    DROP COLUMN bbox_min_lng,
+    DROP COLUMN bbox_min_lat,
+    DROP COLUMN bbox_max_lng,
+    DROP COLUMN bbox_max_lat;
+
+-- +goose StatementEnd
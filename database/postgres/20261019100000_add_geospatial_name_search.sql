@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE geospatial ADD COLUMN search_name VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Approximation until the next import, which folds diacritics and fills geospatial_trigram.
+UPDATE geospatial SET search_name = LOWER(name);
+
+CREATE INDEX idx_geospatial_search_name ON geospatial (search_name varchar_pattern_ops);
+
+CREATE TABLE geospatial_trigram (
+    geospatial_id INTEGER NOT NULL,
+    trigram VARCHAR(3) NOT NULL,
+    PRIMARY KEY (trigram, geospatial_id)
+);
+
+CREATE INDEX idx_geospatial_trigram_geospatial_id ON geospatial_trigram (geospatial_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geospatial_trigram;
+
+DROP INDEX idx_geospatial_search_name;
+
+ALTER TABLE geospatial DROP COLUMN search_name;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE geospatial_name (
+    id SERIAL NOT NULL,
+    geospatial_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    search_name VARCHAR(255) NOT NULL,
+    language VARCHAR(35) NOT NULL DEFAULT '',
+    kind VARCHAR(20) NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_geospatial_name_geospatial_id ON geospatial_name (geospatial_id);
+CREATE INDEX idx_geospatial_name_search_name ON geospatial_name (search_name varchar_pattern_ops);
+CREATE INDEX idx_geospatial_name_language ON geospatial_name (language);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geospatial_name;
+
+-- +goose StatementEnd
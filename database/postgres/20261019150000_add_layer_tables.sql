@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE layer (
+    id SERIAL NOT NULL,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE layer_feature (
+    id SERIAL NOT NULL,
+    layer_id INTEGER NOT NULL,
+    -- The GeoJSON id, features without one are added by every import.
+    feature_id VARCHAR(255) NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    properties JSONB NOT NULL DEFAULT '{}',
+    geometry geometry(MultiPolygon, 4326) NOT NULL,
+    area_km2 DOUBLE PRECISION NULL,
+    bbox_min_lng DOUBLE PRECISION NULL,
+    bbox_min_lat DOUBLE PRECISION NULL,
+    bbox_max_lng DOUBLE PRECISION NULL,
+    bbox_max_lat DOUBLE PRECISION NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (layer_id, feature_id)
+);
+
+CREATE INDEX idx_layer_feature_geometry ON layer_feature USING GIST (geometry);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE layer_feature;
+DROP TABLE layer;
+
+-- +goose StatementEnd
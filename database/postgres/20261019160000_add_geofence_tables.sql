@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE geofence (
+    id SERIAL NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    -- A fence is either a region or its own polygon.
+    geospatial_id INTEGER NULL,
+    geometry geometry(MultiPolygon, 4326) NULL,
+    bbox_min_lng DOUBLE PRECISION NULL,
+    bbox_min_lat DOUBLE PRECISION NULL,
+    bbox_max_lng DOUBLE PRECISION NULL,
+    bbox_max_lat DOUBLE PRECISION NULL,
+    dwell_seconds INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_geofence_geometry ON geofence USING GIST (geometry);
+CREATE INDEX idx_geofence_geospatial_id ON geofence (geospatial_id);
+
+-- The last position of every tracked object.
+CREATE TABLE geofence_object (
+    object_id VARCHAR(255) NOT NULL,
+    lat DOUBLE PRECISION NOT NULL,
+    lng DOUBLE PRECISION NOT NULL,
+    seen_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (object_id)
+);
+
+-- The fences a tracked object is in, since when and whether its dwell event was sent.
+CREATE TABLE geofence_state (
+    object_id VARCHAR(255) NOT NULL,
+    geofence_id INTEGER NOT NULL,
+    entered_at TIMESTAMP NOT NULL,
+    dwelled BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (object_id, geofence_id)
+);
+
+CREATE INDEX idx_geofence_state_geofence_id ON geofence_state (geofence_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geofence_state;
+DROP TABLE geofence_object;
+DROP TABLE geofence;
+
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Events are written here in the transaction of the change they describe, the dispatcher
+-- creates their deliveries and sets dispatched_at.
+CREATE TABLE outbox_event (
+    id SERIAL NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_outbox_event_dispatched_at ON outbox_event (dispatched_at, id);
+
+CREATE TABLE webhook (
+    id SERIAL NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    -- Comma separated event types, empty for every type.
+    events VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+-- One delivery of an event to a webhook, with the outcome of its last attempt.
+CREATE TABLE webhook_delivery (
+    id SERIAL NOT NULL,
+    webhook_id INTEGER NOT NULL,
+    event_id INTEGER NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    status_code INTEGER NULL,
+    error VARCHAR(1024) NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX idx_webhook_delivery_status ON webhook_delivery (status, next_attempt_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_delivery;
+DROP TABLE webhook;
+DROP TABLE outbox_event;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys clients authenticate with, only the SHA-256 of a key is stored.
+CREATE TABLE api_key (
+    id SERIAL NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (hash)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_key;
+
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Rate limits of a key, NULL keeps the default of the service.
+ALTER TABLE api_key
+    ADD COLUMN limit_rate DOUBLE PRECISION NULL,
+    ADD COLUMN limit_burst INTEGER NULL,
+    ADD COLUMN limit_daily_quota INTEGER NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE api_key
+    DROP COLUMN limit_rate,
+    DROP COLUMN limit_burst,
+    DROP COLUMN limit_daily_quota;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE geospatial (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    gadm_id VARCHAR(255) NOT NULL UNIQUE,
+    parent_gadm_id VARCHAR(255) NULL,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(255) NOT NULL,
+    level INTEGER NOT NULL CHECK (
+        level BETWEEN 1
+        AND 5
+    ),
+    -- WKT, the ST_* functions are registered by the application
+    geometry TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_geospatial_parent_gadm_id ON geospatial (parent_gadm_id);
+CREATE INDEX idx_geospatial_created_at ON geospatial (created_at);
+CREATE INDEX idx_geospatial_updated_at ON geospatial (updated_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geospatial;
+
+-- +goose StatementEnd
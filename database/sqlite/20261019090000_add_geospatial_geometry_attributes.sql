@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE geospatial ADD COLUMN centroid_lat DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN centroid_lng DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN label_lat DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN label_lng DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN area_km2 DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN perimeter_km DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN bbox_min_lng DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN bbox_min_lat DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN bbox_max_lng DOUBLE NULL;
+ALTER TABLE geospatial ADD COLUMN bbox_max_lat DOUBLE NULL;
+
+CREATE INDEX idx_geospatial_area_km2 ON geospatial (area_km2);
+
+-- SQLite has no spatial index, point and bbox filters use this R-tree of the bbox columns.
+CREATE VIRTUAL TABLE geospatial_rtree USING rtree(id, min_lng, max_lng, min_lat, max_lat);
+
+CREATE TRIGGER geospatial_rtree_insert AFTER INSERT ON geospatial
+WHEN NEW.bbox_min_lng IS NOT NULL
+BEGIN
+    INSERT INTO geospatial_rtree VALUES (NEW.id, NEW.bbox_min_lng, NEW.bbox_max_lng, NEW.bbox_min_lat, NEW.bbox_max_lat);
+END;
+
+CREATE TRIGGER geospatial_rtree_update AFTER UPDATE OF bbox_min_lng, bbox_min_lat, bbox_max_lng, bbox_max_lat ON geospatial
+BEGIN
+    DELETE FROM geospatial_rtree WHERE id = OLD.id;
+    INSERT INTO geospatial_rtree SELECT NEW.id, NEW.bbox_min_lng, NEW.bbox_max_lng, NEW.bbox_min_lat, NEW.bbox_max_lat
+    WHERE NEW.bbox_min_lng IS NOT NULL;
+END;
+
+CREATE TRIGGER geospatial_rtree_delete AFTER DELETE ON geospatial
+BEGIN
+    DELETE FROM geospatial_rtree WHERE id = OLD.id;
+END;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER geospatial_rtree_delete;
+DROP TRIGGER geospatial_rtree_update;
+DROP TRIGGER geospatial_rtree_insert;
+DROP TABLE geospatial_rtree;
+DROP INDEX idx_geospatial_area_km2;
+
+ALTER TABLE geospatial DROP COLUMN centroid_lat;
+ALTER TABLE geospatial DROP COLUMN centroid_lng;
+ALTER TABLE geospatial DROP COLUMN label_lat;
+ALTER TABLE geospatial DROP COLUMN label_lng;
+ALTER TABLE geospatial DROP COLUMN area_km2;
+ALTER TABLE geospatial DROP COLUMN perimeter_km;
+ALTER TABLE geospatial DROP COLUMN bbox_min_lng;
+ALTER TABLE geospatial DROP COLUMN bbox_min_lat;
+ALTER TABLE geospatial DROP COLUMN bbox_max_lng;
+ALTER TABLE geospatial DROP COLUMN bbox_max_lat;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE geospatial ADD COLUMN search_name VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Approximation until the next import, which folds diacritics and fills geospatial_trigram.
+UPDATE geospatial SET search_name = LOWER(name);
+
+-- LIKE is case-insensitive in SQLite, it only uses an index with the same collation.
+CREATE INDEX idx_geospatial_search_name ON geospatial (search_name COLLATE NOCASE);
+
+CREATE TABLE geospatial_trigram (
+    geospatial_id INTEGER NOT NULL,
+    trigram VARCHAR(3) NOT NULL,
+    PRIMARY KEY (trigram, geospatial_id)
+) WITHOUT ROWID;
+
+CREATE INDEX idx_geospatial_trigram_geospatial_id ON geospatial_trigram (geospatial_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geospatial_trigram;
+
+DROP INDEX idx_geospatial_search_name;
+
+ALTER TABLE geospatial DROP COLUMN search_name;
+
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE geospatial ADD COLUMN eng_type VARCHAR(20) NOT NULL DEFAULT 'OTHER';
+
+-- The other levels are typed by the next import, which reads ENGTYPE_n.
+UPDATE geospatial SET eng_type = 'COUNTRY' WHERE level = 1;
+
+CREATE INDEX idx_geospatial_eng_type_level ON geospatial (eng_type, level);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_geospatial_eng_type_level;
+
+ALTER TABLE geospatial DROP COLUMN eng_type;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE geospatial_neighbor (
+    geospatial_id INTEGER NOT NULL,
+    neighbor_id INTEGER NOT NULL,
+    border_km DOUBLE NOT NULL,
+    PRIMARY KEY (geospatial_id, neighbor_id)
+);
+
+CREATE INDEX idx_geospatial_neighbor_neighbor_id ON geospatial_neighbor (neighbor_id);
+
+-- Every region is indexed by the next import.
+ALTER TABLE geospatial ADD COLUMN neighbors_stale BOOLEAN NOT NULL DEFAULT 1;
+
+CREATE INDEX idx_geospatial_neighbors_stale ON geospatial (neighbors_stale);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_geospatial_neighbors_stale;
+
+ALTER TABLE geospatial DROP COLUMN neighbors_stale;
+
+DROP TABLE geospatial_neighbor;
+
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE layer (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE layer_feature (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    layer_id INTEGER NOT NULL,
+    -- The GeoJSON id, features without one are added by every import.
+    feature_id VARCHAR(255) NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    -- JSON object
+    properties TEXT NOT NULL DEFAULT '{}',
+    -- WKT, the ST_* functions are registered by the application
+    geometry TEXT NOT NULL,
+    area_km2 DOUBLE NULL,
+    bbox_min_lng DOUBLE NULL,
+    bbox_min_lat DOUBLE NULL,
+    bbox_max_lng DOUBLE NULL,
+    bbox_max_lat DOUBLE NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (layer_id, feature_id)
+);
+
+-- Point, bbox and intersects filters use this R-tree like they use geospatial_rtree.
+CREATE VIRTUAL TABLE layer_feature_rtree USING rtree(id, min_lng, max_lng, min_lat, max_lat);
+
+CREATE TRIGGER layer_feature_rtree_insert AFTER INSERT ON layer_feature
+WHEN NEW.bbox_min_lng IS NOT NULL
+BEGIN
+    INSERT INTO layer_feature_rtree VALUES (NEW.id, NEW.bbox_min_lng, NEW.bbox_max_lng, NEW.bbox_min_lat, NEW.bbox_max_lat);
+END;
+
+CREATE TRIGGER layer_feature_rtree_update AFTER UPDATE OF bbox_min_lng, bbox_min_lat, bbox_max_lng, bbox_max_lat ON layer_feature
+BEGIN
+    DELETE FROM layer_feature_rtree WHERE id = OLD.id;
+    INSERT INTO layer_feature_rtree SELECT NEW.id, NEW.bbox_min_lng, NEW.bbox_max_lng, NEW.bbox_min_lat, NEW.bbox_max_lat
+    WHERE NEW.bbox_min_lng IS NOT NULL;
+END;
+
+CREATE TRIGGER layer_feature_rtree_delete AFTER DELETE ON layer_feature
+BEGIN
+    DELETE FROM layer_feature_rtree WHERE id = OLD.id;
+END;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER layer_feature_rtree_delete;
+DROP TRIGGER layer_feature_rtree_update;
+DROP TRIGGER layer_feature_rtree_insert;
+DROP TABLE layer_feature_rtree;
+DROP TABLE layer_feature;
+DROP TABLE layer;
+
+-- +goose StatementEnd
@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE geofence (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    -- A fence is either a region or its own polygon.
+    geospatial_id INTEGER NULL,
+    -- WKT, the ST_* functions are registered by the application
+    geometry TEXT NULL,
+    bbox_min_lng DOUBLE NULL,
+    bbox_min_lat DOUBLE NULL,
+    bbox_max_lng DOUBLE NULL,
+    bbox_max_lat DOUBLE NULL,
+    dwell_seconds INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_geofence_geospatial_id ON geofence (geospatial_id);
+
+-- Point filters of polygon fences use this R-tree like they use geospatial_rtree.
+CREATE VIRTUAL TABLE geofence_rtree USING rtree(id, min_lng, max_lng, min_lat, max_lat);
+
+CREATE TRIGGER geofence_rtree_insert AFTER INSERT ON geofence
+WHEN NEW.bbox_min_lng IS NOT NULL
+BEGIN
+    INSERT INTO geofence_rtree VALUES (NEW.id, NEW.bbox_min_lng, NEW.bbox_max_lng, NEW.bbox_min_lat, NEW.bbox_max_lat);
+END;
+
+CREATE TRIGGER geofence_rtree_delete AFTER DELETE ON geofence
+BEGIN
+    DELETE FROM geofence_rtree WHERE id = OLD.id;
+END;
+
+-- The last position of every tracked object.
+CREATE TABLE geofence_object (
+    object_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    lat DOUBLE NOT NULL,
+    lng DOUBLE NOT NULL,
+    seen_at DATETIME NOT NULL
+);
+
+-- The fences a tracked object is in, since when and whether its dwell event was sent.
+CREATE TABLE geofence_state (
+    object_id VARCHAR(255) NOT NULL,
+    geofence_id INTEGER NOT NULL,
+    entered_at DATETIME NOT NULL,
+    dwelled BOOLEAN NOT NULL DEFAULT 0,
+    PRIMARY KEY (object_id, geofence_id)
+);
+
+CREATE INDEX idx_geofence_state_geofence_id ON geofence_state (geofence_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geofence_state;
+DROP TABLE geofence_object;
+DROP TRIGGER geofence_rtree_delete;
+DROP TRIGGER geofence_rtree_insert;
+DROP TABLE geofence_rtree;
+DROP TABLE geofence;
+
+-- +goose StatementEnd
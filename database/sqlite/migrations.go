@@ -0,0 +1,7 @@
+// Package sqlite embeds the SQLite migrations so a single binary can create its own database.
+package sqlite
+
+import "embed"
+
+//go:embed *.sql
+var Migrations embed.FS
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// APIKey is a key clients authenticate with. Only the hash of the key is stored, Key is set
+// once, when the key is created.
+type APIKey struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name string `gorm:"<-" json:"name"`
+	// Prefix is the start of the key, to tell keys apart without storing them.
+	Prefix    string     `gorm:"<-" json:"prefix"`
+	Hash      string     `gorm:"<-:create;unique" json:"-"`
+	Role      string     `gorm:"<-" json:"role"`
+	RevokedAt *time.Time `gorm:"<-" json:"revoked_at,omitempty"`
+	Limit     RateLimit  `gorm:"embedded;embeddedPrefix:limit_" json:"limit"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Key       string     `gorm:"-:all" json:"key,omitempty"`
+}
+
+// RateLimit overrides the default rate limit of the requests made with an API key, the fields
+// left empty keep the default. A rate or quota of 0 is unlimited.
+type RateLimit struct {
+	// Rate is how many tokens the key gets back per second.
+	Rate *float64 `gorm:"<-" json:"rate,omitempty"`
+	// Burst is how many tokens the key can save up.
+	Burst *int `gorm:"<-" json:"burst,omitempty"`
+	// DailyQuota is how many tokens the key can spend per UTC day.
+	DailyQuota *int `gorm:"<-" json:"daily_quota,omitempty"`
+}
+
+// APIKeyRequest is the body creating an API key.
+type APIKeyRequest struct {
+	Name  string    `json:"name"`
+	Role  string    `json:"role"`
+	Limit RateLimit `json:"limit"`
+}
+
+// Principal is who made a request: an API key or the subject of a JWT, their role and the
+// rate limit of the key.
+type Principal struct {
+	// UserID is "key:<id>" for an API key and "jwt:<sub>" for a JWT, so that neither can pass
+	// for the other.
+	UserID    string
+	Role      string
+	RateLimit RateLimit
+}
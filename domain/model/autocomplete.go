@@ -0,0 +1,41 @@
+package model
+
+// Suggestion is an autocomplete result, Label names the region followed by its ancestors
+// below the country, e.g. "Kebayoran Baru, Jakarta Selatan, Jakarta Raya".
+type Suggestion struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Level      uint       `json:"level"`
+	Label      string     `json:"label"`
+	Score      float64    `json:"score"`
+	Match      string     `json:"match"`
+	LabelPoint *LatLng    `json:"label_point,omitempty"`
+	Ancestors  []Ancestor `json:"ancestors"`
+}
+
+// Ancestor is a region above a suggestion, ordered from the parent up to the country.
+type Ancestor struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Level uint   `json:"level"`
+}
+
+// AutocompleteOptions biases suggestions toward a point or below a region without excluding
+// the others, and picks the language of the names.
+type AutocompleteOptions struct {
+	// Point favours the suggestions near it.
+	Point    *LatLng
+	ParentID uint
+	Lang     string
+}
+
+type AutocompleteParams struct {
+	Q        string `query:"q" form:"q"`
+	Levels   string `query:"levels" form:"levels"`
+	Limit    uint   `query:"limit" form:"limit"`
+	LatLng   string `query:"latlng" form:"latlng"`
+	ParentID uint   `query:"parentId" form:"parentId"`
+	Lang     string `query:"lang" form:"lang"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type ExportJob struct {
+	ID         string           `json:"id"`
+	Format     string           `json:"format"`
+	Status     string           `json:"status"`
+	Filter     GeospatialFilter `json:"filter"`
+	Rows       int              `json:"rows"`
+	Error      string           `json:"error,omitempty"`
+	FilePath   string           `json:"-"`
+	CreatedAt  time.Time        `json:"created_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+}
+
+type ExportParams struct {
+	Format string `query:"format" form:"format"`
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Geofence is an area tracked objects enter and exit, a region or a polygon of its own. An
+// object staying DwellSeconds in it also dwells there, unless DwellSeconds is 0.
+type Geofence struct {
+	ID           uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string          `gorm:"<-" json:"name"`
+	GeospatialID *uint           `gorm:"<-" json:"region_id,omitempty"`
+	Geometry     string          `gorm:"type:geometry" json:"-"`
+	BboxMinLng   *float64        `gorm:"<-" json:"-"`
+	BboxMinLat   *float64        `gorm:"<-" json:"-"`
+	BboxMaxLng   *float64        `gorm:"<-" json:"-"`
+	BboxMaxLat   *float64        `gorm:"<-" json:"-"`
+	DwellSeconds uint            `gorm:"<-" json:"dwell_seconds"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	Shape        json.RawMessage `gorm:"-:all" json:"geometry,omitempty"`
+}
+
+// GeofenceObject is the last known position of a tracked object.
+type GeofenceObject struct {
+	ObjectID string    `gorm:"primaryKey" json:"object_id"`
+	Lat      float64   `json:"lat"`
+	Lng      float64   `json:"lng"`
+	SeenAt   time.Time `json:"seen_at"`
+	// Version counts the saves of the object, it detects concurrent updates.
+	Version uint `json:"-"`
+}
+
+// GeofenceState is a fence a tracked object is in. Dwelled is set once its dwell event is
+// sent, there is one per stay.
+type GeofenceState struct {
+	ObjectID   string    `gorm:"primaryKey" json:"-"`
+	GeofenceID uint      `gorm:"primaryKey" json:"geofence_id"`
+	EnteredAt  time.Time `json:"entered_at"`
+	Dwelled    bool      `json:"dwelled"`
+}
+
+// GeofenceEvent is an object entering, exiting or dwelling in a fence, at the time of the
+// position it was detected from.
+type GeofenceEvent struct {
+	Type         string    `json:"type"`
+	ObjectID     string    `json:"object_id"`
+	GeofenceID   uint      `json:"geofence_id"`
+	GeofenceName string    `json:"geofence_name"`
+	Lat          float64   `json:"lat"`
+	Lng          float64   `json:"lng"`
+	At           time.Time `json:"at"`
+}
+
+// GeofencePosition is a position of a tracked object, At defaults to the time it is received.
+// Lat and Lng are nil when they are missing, 0 is a valid coordinate.
+type GeofencePosition struct {
+	ObjectID string     `json:"objectId"`
+	Lat      *float64   `json:"lat"`
+	Lng      *float64   `json:"lng"`
+	At       *time.Time `json:"at"`
+}
+
+// GeofenceRequest registers a fence, by RegionID or by Geometry, a GeoJSON Polygon or
+// MultiPolygon.
+type GeofenceRequest struct {
+	Name         string          `json:"name"`
+	RegionID     uint            `json:"regionId"`
+	Geometry     json.RawMessage `json:"geometry"`
+	DwellSeconds uint            `json:"dwellSeconds"`
+}
+
+// GeofencePositionsRequest posts positions of tracked objects. Emit also hands the events
+// detected to the emitter of the service.
+type GeofencePositionsRequest struct {
+	Positions []GeofencePosition `json:"positions"`
+	Emit      bool               `json:"emit"`
+}
+
+// GeofenceTracking is what the positions of a request changed: the events detected and how
+// many positions were older than the last one of their object, which are ignored.
+type GeofenceTracking struct {
+	Events  []GeofenceEvent `json:"events"`
+	Ignored int             `json:"ignored"`
+}
+
+// GeofenceObjectStatus is the last known position of an object and the fences it is in.
+type GeofenceObjectStatus struct {
+	GeofenceObject
+	Fences []GeofenceState `json:"fences"`
+}
@@ -6,50 +6,261 @@ import (
 )
 
 type Geospatial struct {
-	ID           uint        `gorm:"primaryKey;autoIncrement" json:"id"`
-	GadmID       string      `gorm:"<-:create;unique" json:"-"`
-	ParentGadmID string      `gorm:"<-" json:"-"`
-	Name         string      `gorm:"<-" json:"name"`
-	Type         string      `gorm:"<-" json:"type"`
-	Level        uint        `gorm:"<-" json:"level"`
-	Geometry     string      `gorm:"type:geometry" json:"-"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	Child        *Geospatial `gorm:"-:all" json:"-"`
-	ChildJSON    *Geospatial `gorm:"-:all" json:"child,omitempty"`
+	ID           uint             `gorm:"primaryKey;autoIncrement" json:"id"`
+	GadmID       string           `gorm:"<-:create;unique" json:"-"`
+	ParentGadmID string           `gorm:"<-" json:"-"`
+	Name         string           `gorm:"<-" json:"name"`
+	SearchName   string           `gorm:"<-" json:"-"`
+	Type         string           `gorm:"<-" json:"type"`
+	EngType      string           `gorm:"<-" json:"eng_type"`
+	Level        uint             `gorm:"<-" json:"level"`
+	Geometry     string           `gorm:"type:geometry" json:"-"`
+	CentroidLat  *float64         `gorm:"<-" json:"-"`
+	CentroidLng  *float64         `gorm:"<-" json:"-"`
+	LabelLat     *float64         `gorm:"<-" json:"-"`
+	LabelLng     *float64         `gorm:"<-" json:"-"`
+	AreaKm2      *float64         `gorm:"<-" json:"area_km2,omitempty"`
+	PerimeterKm  *float64         `gorm:"<-" json:"perimeter_km,omitempty"`
+	DistanceKm   *float64         `gorm:"->" json:"distance_km,omitempty"`
+	BboxMinLng   *float64         `gorm:"<-" json:"-"`
+	BboxMinLat   *float64         `gorm:"<-" json:"-"`
+	BboxMaxLng   *float64         `gorm:"<-" json:"-"`
+	BboxMaxLat   *float64         `gorm:"<-" json:"-"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+	Names        []GeospatialName `gorm:"-:all" json:"-"`
+	OfficialName string           `gorm:"-:all" json:"official_name,omitempty"`
+	Score        *float64         `gorm:"-:all" json:"score,omitempty"`
+	Match        string           `gorm:"-:all" json:"match,omitempty"`
+	MatchedName  string           `gorm:"-:all" json:"matched_name,omitempty"`
+	Child        *Geospatial      `gorm:"-:all" json:"-"`
+	ChildJSON    *Geospatial      `gorm:"-:all" json:"child,omitempty"`
+	OverlapKm2   *float64         `gorm:"-:all" json:"overlap_km2,omitempty"`
+	OverlapKm    *float64         `gorm:"-:all" json:"overlap_km,omitempty"`
+	OverlapPct   *float64         `gorm:"-:all" json:"overlap_pct,omitempty"`
+	BorderKm     *float64         `gorm:"-:all" json:"border_km,omitempty"`
+	Shape        json.RawMessage  `gorm:"-:all" json:"geometry,omitempty"`
+	Parent       *GeospatialRef   `gorm:"-:all" json:"parent,omitempty"`
+	Children     []GeospatialRef  `gorm:"-:all" json:"children,omitempty"`
+}
+
+// GeospatialRef is the short form of a region, used for the parent and the children of
+// another.
+type GeospatialRef struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	EngType string `json:"eng_type"`
+	Level   uint   `json:"level"`
+}
+
+func (g *Geospatial) Ref() GeospatialRef {
+	return GeospatialRef{ID: g.ID, Name: g.Name, Type: g.Type, EngType: g.EngType, Level: g.Level}
+}
+
+type LatLng struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+func newLatLng(lat, lng *float64) *LatLng {
+	if lat == nil || lng == nil {
+		return nil
+	}
+	return &LatLng{Lat: *lat, Lng: *lng}
+}
+
+// Centroid is the area-weighted center, it may fall outside concave regions.
+func (g *Geospatial) Centroid() *LatLng {
+	return newLatLng(g.CentroidLat, g.CentroidLng)
+}
+
+// LabelPoint is a point that always lies inside the region.
+func (g *Geospatial) LabelPoint() *LatLng {
+	return newLatLng(g.LabelLat, g.LabelLng)
+}
+
+// CountryCode is the GID_0 code of the country the region belongs to, the first three
+// characters of every GADM id.
+func (g *Geospatial) CountryCode() string {
+	if len(g.GadmID) < 3 {
+		return g.GadmID
+	}
+	return g.GadmID[:3]
+}
+
+// Bbox is the bounding box as [min lng, min lat, max lng, max lat], like GeoJSON.
+func (g *Geospatial) Bbox() []float64 {
+	if g.BboxMinLng == nil || g.BboxMinLat == nil || g.BboxMaxLng == nil || g.BboxMaxLat == nil {
+		return nil
+	}
+	return []float64{*g.BboxMinLng, *g.BboxMinLat, *g.BboxMaxLng, *g.BboxMaxLat}
 }
 
 func (g *Geospatial) MarshalJSON() ([]byte, error) {
 	type Alias Geospatial
 	return json.Marshal(&struct {
 		*Alias
-		ChildJSON interface{} `json:"child,omitempty"`
+		Centroid   *LatLng     `json:"centroid,omitempty"`
+		LabelPoint *LatLng     `json:"label_point,omitempty"`
+		Bbox       []float64   `json:"bbox,omitempty"`
+		ChildJSON  interface{} `json:"child,omitempty"`
 	}{
-		Alias:     (*Alias)(g),
-		ChildJSON: g.Child,
+		Alias:      (*Alias)(g),
+		Centroid:   g.Centroid(),
+		LabelPoint: g.LabelPoint(),
+		Bbox:       g.Bbox(),
+		ChildJSON:  g.Child,
 	})
 }
 
 type GeospatialFilter struct {
-	Name        string   `json:"name"`
-	Levels      []uint   `json:"levels"`
-	Types       []string `json:"types"`
-	ExcludedIds []uint   `json:"excludedIds"`
-	ParentIds   []uint   `json:"parentIds"`
-	Lat         float64  `json:"lat"`
-	Lng         float64  `json:"lng"`
-	Nested      bool     `json:"nested"`
+	IDs         []uint    `json:"ids"`
+	Name        string    `json:"name"`
+	Levels      []uint    `json:"levels"`
+	Types       []string  `json:"types"`
+	ExcludedIds []uint    `json:"excludedIds"`
+	ParentIds   []uint    `json:"parentIds"`
+	AncestorID  uint      `json:"ancestorId"`
+	Country     string    `json:"country"`
+	Lat         *float64  `json:"lat"`
+	Lng         *float64  `json:"lng"`
+	MinAreaKm2  float64   `json:"minArea"`
+	MaxAreaKm2  float64   `json:"maxArea"`
+	Bbox        []float64 `json:"bbox"`
+	Nested      bool      `json:"nested"`
+	// Near is the point distance_km is measured from, it does not filter.
+	Near *LatLng `json:"near"`
+	// Intersects is a geometry, as WKT, regions must share a point with.
+	Intersects string `json:"-"`
+	// Columns are the columns read, all but the geometry when empty. The geometry is only
+	// read, as WKT, WithGeometry.
+	Columns      []string `json:"-"`
+	WithGeometry bool     `json:"-"`
+}
+
+// Point is the point regions must contain, nil unless both Lat and Lng are given.
+func (f GeospatialFilter) Point() *LatLng {
+	return newLatLng(f.Lat, f.Lng)
+}
+
+// GeospatialFields are the fields a sparse fieldset may pick, mapped to the columns they are
+// read from. distance_km is computed from a near point.
+var GeospatialFields = map[string][]string{
+	"id":           {"id"},
+	"name":         {"name"},
+	"type":         {"type"},
+	"eng_type":     {"eng_type"},
+	"level":        {"level"},
+	"area_km2":     {"area_km2"},
+	"perimeter_km": {"perimeter_km"},
+	"distance_km":  {},
+	"centroid":     {"centroid_lat", "centroid_lng"},
+	"label_point":  {"label_lat", "label_lng"},
+	"bbox":         {"bbox_min_lng", "bbox_min_lat", "bbox_max_lng", "bbox_max_lat"},
+	"created_at":   {"created_at"},
+	"updated_at":   {"updated_at"},
+}
+
+// GeospatialKeyColumns are read whatever the fields, regions are identified, named, nested
+// and localized by them.
+var GeospatialKeyColumns = []string{"id", "gadm_id", "parent_gadm_id", "name", "level"}
+
+// Includes add related data to regions.
+const (
+	IncludeGeometry = "geometry"
+	IncludeParent   = "parent"
+	IncludeChildren = "children"
+)
+
+var GeospatialIncludes = []string{IncludeGeometry, IncludeParent, IncludeChildren}
+
+// GeospatialSortFields are the fields regions can be sorted by, and their aliases, mapped to
+// the field they sort by. distance_km needs a near point.
+var GeospatialSortFields = map[string]string{
+	"id":           "id",
+	"name":         "name",
+	"type":         "type",
+	"eng_type":     "eng_type",
+	"level":        "level",
+	"area_km2":     "area_km2",
+	"area":         "area_km2",
+	"perimeter_km": "perimeter_km",
+	"perimeter":    "perimeter_km",
+	"distance_km":  "distance_km",
+	"distance":     "distance_km",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
 }
 
 type GeospatialFilterParams struct {
-	Name        string            `query:"name" form:"name"`
-	Levels      string            `query:"levels" form:"levels"`
-	Types       string            `query:"types" form:"types"`
-	LatLng      string            `query:"latlng" form:"latlng"`
-	ExcludedIds string            `query:"excludedIds" form:"excludedIds"`
-	ParentIds   string            `query:"parentIds" form:"parentIds"`
-	Limit       uint              `query:"limit" form:"limit"`
-	Page        uint              `query:"page" form:"page"`
-	Sort        map[string]string `query:"sort" form:"sort"`
-	Nested      bool              `query:"nested" form:"nested"`
+	Name        string  `query:"name" form:"name"`
+	Q           string  `query:"q" form:"q"`
+	Lang        string  `query:"lang" form:"lang"`
+	Levels      string  `query:"levels" form:"levels"`
+	Types       string  `query:"types" form:"types"`
+	LatLng      string  `query:"latlng" form:"latlng"`
+	ExcludedIds string  `query:"excludedIds" form:"excludedIds"`
+	ParentIds   string  `query:"parentIds" form:"parentIds"`
+	MinArea     float64 `query:"minArea" form:"minArea"`
+	MaxArea     float64 `query:"maxArea" form:"maxArea"`
+	Bbox        string  `query:"bbox" form:"bbox"`
+	Limit       uint    `query:"limit" form:"limit"`
+	Page        uint    `query:"page" form:"page"`
+	Cursor      string  `query:"cursor" form:"cursor"`
+	WithTotal   *bool   `query:"withTotal" form:"withTotal"`
+	Sort        string  `query:"sort" form:"sort"`
+	Near        string  `query:"near" form:"near"`
+	Fields      string  `query:"fields" form:"fields"`
+	Include     string  `query:"include" form:"include"`
+	Nested      bool    `query:"nested" form:"nested"`
+}
+
+// GeospatialIntersectsRequest is the body of an intersection query. Geometry is a GeoJSON
+// Polygon, MultiPolygon or LineString, the filter fields narrow the regions like the query
+// parameters of the list do.
+type GeospatialIntersectsRequest struct {
+	GeospatialFilter
+	Geometry json.RawMessage `json:"geometry"`
+	// Overlap adds how much of the geometry lies in each region.
+	Overlap bool   `json:"overlap"`
+	Limit   uint   `json:"limit"`
+	Page    uint   `json:"page"`
+	Sort    string `json:"sort"`
+	Fields  string `json:"fields"`
+	Include string `json:"include"`
+	Lang    string `json:"lang"`
+}
+
+// GeospatialUnionRequest is the body of a union, the regions to dissolve are picked by ids or
+// by the filter fields like the query parameters of the list pick them.
+type GeospatialUnionRequest struct {
+	GeospatialFilter
+	// SaveAs saves the union as a custom area of that name, replacing the one of the same name.
+	SaveAs string `json:"saveAs"`
+}
+
+// GeospatialUnion is the geometry of regions dissolved into one area.
+type GeospatialUnion struct {
+	Geometry    json.RawMessage `json:"geometry"`
+	AreaKm2     float64         `json:"area_km2"`
+	PerimeterKm float64         `json:"perimeter_km"`
+	Bbox        []float64       `json:"bbox"`
+	RegionIDs   []uint          `json:"region_ids"`
+	// Saved is the custom area the union was saved as.
+	Saved *Geospatial `json:"saved,omitempty"`
+}
+
+// GeospatialDetailParams shape the region returned by the detail endpoint.
+type GeospatialDetailParams struct {
+	Lang    string `query:"lang" form:"lang"`
+	Fields  string `query:"fields" form:"fields"`
+	Include string `query:"include" form:"include"`
+}
+
+// GeospatialScopeParams narrow the metadata endpoints to a country or to the regions below
+// a parent.
+type GeospatialScopeParams struct {
+	Country  string `query:"country" form:"country"`
+	ParentID uint   `query:"parentId" form:"parentId"`
 }
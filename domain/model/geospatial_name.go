@@ -0,0 +1,12 @@
+package model
+
+// GeospatialName is an alternate name of a region, a variant spelling, a former name or the
+// name in another language or script.
+type GeospatialName struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement" json:"-"`
+	GeospatialID uint   `gorm:"<-" json:"-"`
+	Name         string `gorm:"<-" json:"name"`
+	SearchName   string `gorm:"<-" json:"-"`
+	Language     string `gorm:"<-" json:"language,omitempty"`
+	Kind         string `gorm:"<-" json:"kind"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// GeospatialNeighbor is a region of the same level touching a region, and the length of the
+// border they share. Every pair is stored in both directions.
+type GeospatialNeighbor struct {
+	GeospatialID uint    `gorm:"<-" json:"-"`
+	NeighborID   uint    `gorm:"<-" json:"neighbor_id"`
+	BorderKm     float64 `gorm:"<-" json:"border_km"`
+}
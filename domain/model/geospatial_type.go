@@ -0,0 +1,57 @@
+package model
+
+// GeospatialTypeCount is how many regions of a country and a level have a local and a
+// normalized type.
+type GeospatialTypeCount struct {
+	Country string
+	EngType string
+	Type    string
+	Level   uint
+	Count   int64
+}
+
+// GeospatialCountry is a country, by its GID_0 code, that metadata applies to.
+type GeospatialCountry struct {
+	Code string `json:"code"`
+	Name string `json:"name,omitempty"`
+}
+
+// GeospatialType is a normalized region type of the catalogue served by /v1/types.
+type GeospatialType struct {
+	EngType   string                `json:"eng_type"`
+	Count     int64                 `json:"count"`
+	Countries []GeospatialCountry   `json:"countries"`
+	Levels    []GeospatialTypeLevel `json:"levels"`
+}
+
+// GeospatialTypeLevel counts the regions of a normalized type at one level, with the local
+// types they were imported with.
+type GeospatialTypeLevel struct {
+	Level      uint     `json:"level"`
+	Count      int64    `json:"count"`
+	LocalTypes []string `json:"local_types"`
+}
+
+// GeospatialLevel describes a level of the hierarchy served by /v1/levels.
+type GeospatialLevel struct {
+	Level     uint                  `json:"level"`
+	Count     int64                 `json:"count"`
+	Countries []GeospatialCountry   `json:"countries"`
+	Types     []GeospatialLevelType `json:"types"`
+	Examples  []GeospatialExample   `json:"examples"`
+}
+
+// GeospatialLevelType counts the regions of a level by their local and normalized type.
+type GeospatialLevelType struct {
+	Type    string `json:"type"`
+	EngType string `json:"eng_type"`
+	Count   int64  `json:"count"`
+}
+
+// GeospatialExample is a region shown to illustrate a level, the largest ones are picked.
+type GeospatialExample struct {
+	ID      uint     `json:"id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	AreaKm2 *float64 `json:"area_km2,omitempty"`
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Layer is a named set of user defined features, like delivery zones or flood areas, kept
+// apart from the administrative regions.
+type Layer struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string    `gorm:"<-:create;unique" json:"name"`
+	FeatureCount int64     `gorm:"->" json:"feature_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// LayerFeature is a polygon of a layer with the properties it was imported with. FeatureID is
+// the GeoJSON id, an import replaces the feature of the layer with the same one.
+type LayerFeature struct {
+	ID         uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	LayerID    uint            `gorm:"<-" json:"-"`
+	FeatureID  *string         `gorm:"<-" json:"feature_id,omitempty"`
+	Name       string          `gorm:"<-" json:"name"`
+	Properties string          `gorm:"<-" json:"-"`
+	Geometry   string          `gorm:"type:geometry" json:"-"`
+	AreaKm2    *float64        `gorm:"<-" json:"area_km2,omitempty"`
+	BboxMinLng *float64        `gorm:"<-" json:"-"`
+	BboxMinLat *float64        `gorm:"<-" json:"-"`
+	BboxMaxLng *float64        `gorm:"<-" json:"-"`
+	BboxMaxLat *float64        `gorm:"<-" json:"-"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	Shape      json.RawMessage `gorm:"-:all" json:"geometry,omitempty"`
+}
+
+// Bbox is the bounding box as [min lng, min lat, max lng, max lat], like GeoJSON.
+func (f *LayerFeature) Bbox() []float64 {
+	if f.BboxMinLng == nil || f.BboxMinLat == nil || f.BboxMaxLng == nil || f.BboxMaxLat == nil {
+		return nil
+	}
+	return []float64{*f.BboxMinLng, *f.BboxMinLat, *f.BboxMaxLng, *f.BboxMaxLat}
+}
+
+func (f *LayerFeature) MarshalJSON() ([]byte, error) {
+	properties := json.RawMessage(f.Properties)
+	if len(properties) == 0 {
+		properties = json.RawMessage("{}")
+	}
+
+	type Alias LayerFeature
+	return json.Marshal(&struct {
+		*Alias
+		Properties json.RawMessage `json:"properties"`
+		Bbox       []float64       `json:"bbox,omitempty"`
+	}{
+		Alias:      (*Alias)(f),
+		Properties: properties,
+		Bbox:       f.Bbox(),
+	})
+}
+
+// LayerFeatureFilter narrows the features of a layer with the spatial filters regions have.
+type LayerFeatureFilter struct {
+	LayerID uint
+	// Point is the point features must contain.
+	Point *LatLng
+	Bbox  []float64
+	// Intersects is a geometry, as WKT, features must share a point with.
+	Intersects string
+	// WithGeometry reads the geometry, as WKT.
+	WithGeometry bool
+}
+
+type LayerFeatureParams struct {
+	LatLng    string `query:"latlng" form:"latlng"`
+	Bbox      string `query:"bbox" form:"bbox"`
+	Limit     uint   `query:"limit" form:"limit"`
+	Page      uint   `query:"page" form:"page"`
+	WithTotal *bool  `query:"withTotal" form:"withTotal"`
+	Include   string `query:"include" form:"include"`
+}
+
+// LayerIntersectsRequest is the body of an intersection query of a layer, Geometry is read
+// like the one of GeospatialIntersectsRequest.
+type LayerIntersectsRequest struct {
+	Geometry json.RawMessage `json:"geometry"`
+	Bbox     []float64       `json:"bbox"`
+	Limit    uint            `json:"limit"`
+	Page     uint            `json:"page"`
+	Include  string          `json:"include"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is a domain event, written with the change it describes. DispatchedAt is set
+// once its deliveries to the webhooks are created.
+type OutboxEvent struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type         string     `gorm:"<-" json:"type"`
+	Payload      string     `gorm:"<-" json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DispatchedAt *time.Time `gorm:"<-" json:"-"`
+}
+
+// RegionEvent is the payload of the region events, regions are known by their GADM id.
+type RegionEvent struct {
+	GadmID       string `json:"gadm_id"`
+	ParentGadmID string `json:"parent_gadm_id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	EngType      string `json:"eng_type"`
+	Level        uint   `json:"level"`
+}
+
+// ImportEvent is the payload of the import events.
+type ImportEvent struct {
+	Features int    `json:"features"`
+	Created  int    `json:"created"`
+	Updated  int    `json:"updated"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Webhook is a URL the events are posted to. Events are the event types it subscribed to,
+// all of them when empty. The secret signing the deliveries is only returned on creation.
+type Webhook struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL        string    `gorm:"<-" json:"url"`
+	Secret     string    `gorm:"<-" json:"secret,omitempty"`
+	Events     string    `gorm:"<-" json:"-"`
+	EventTypes []string  `gorm:"-:all" json:"events"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery is an event to be posted to a webhook and the outcome of its last attempt.
+type WebhookDelivery struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	WebhookID     uint       `gorm:"<-" json:"webhook_id"`
+	EventID       uint       `gorm:"<-" json:"event_id"`
+	EventType     string     `gorm:"<-" json:"event_type"`
+	Status        string     `gorm:"<-" json:"status"`
+	Attempts      uint       `gorm:"<-" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"<-" json:"next_attempt_at"`
+	StatusCode    *int       `gorm:"<-" json:"status_code,omitempty"`
+	Error         string     `gorm:"<-" json:"error,omitempty"`
+	DeliveredAt   *time.Time `gorm:"<-" json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// WebhookPayload is the body of a delivery.
+type WebhookPayload struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookRequest registers a webhook for the given event types, all of them when empty.
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// WebhookDeliveryParams pages through the delivery log of a webhook.
+type WebhookDeliveryParams struct {
+	Limit uint `query:"limit" form:"limit"`
+	Page  uint `query:"page" form:"page"`
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(context.Context, *model.APIKey) error
+	GetAPIKeys(context.Context, []uint) ([]model.APIKey, error)
+	GetAPIKeyByHash(context.Context, string) (*model.APIKey, error)
+	RevokeAPIKey(context.Context, uint, time.Time) error
+	UpdateAPIKeyLimit(context.Context, uint, model.RateLimit) error
+}
+
+type apiKeyImpl struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository returns the repository of the API keys stored next to the regions in the
+// database db is connected to.
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyImpl{db: db}
+}
+
+// CreateAPIKey writes key and sets its id.
+func (r *apiKeyImpl) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	return r.db.Clauses(dbresolver.Write).Create(key).Error
+}
+
+// GetAPIKeys returns the keys with the given ids, or every key when there are none.
+func (r *apiKeyImpl) GetAPIKeys(ctx context.Context, ids []uint) ([]model.APIKey, error) {
+	chain := r.db.Model(&model.APIKey{})
+	if len(ids) > 0 {
+		chain = chain.Where("id IN (?)", ids)
+	}
+
+	var keys []model.APIKey
+	if err := chain.Order("id ASC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetAPIKeyByHash returns the key with the given hash, or nil when there is none. It reads
+// from the primary, a key revoked a moment ago must not be accepted by a lagging replica.
+func (r *apiKeyImpl) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	var keys []model.APIKey
+	if err := r.db.Clauses(dbresolver.Write).Where("hash = ?", hash).Limit(1).Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	return &keys[0], nil
+}
+
+// RevokeAPIKey revokes the key with the given id at at, a revoked key is kept but rejected.
+func (r *apiKeyImpl) RevokeAPIKey(ctx context.Context, id uint, at time.Time) error {
+	return r.db.Clauses(dbresolver.Write).Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at.UTC()).Error
+}
+
+// UpdateAPIKeyLimit replaces the rate limit of the key with the given id, empty fields are
+// cleared back to the default.
+func (r *apiKeyImpl) UpdateAPIKeyLimit(ctx context.Context, id uint, limit model.RateLimit) error {
+	return r.db.Clauses(dbresolver.Write).Model(&model.APIKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"limit_rate":        limit.Rate,
+			"limit_burst":       limit.Burst,
+			"limit_daily_quota": limit.DailyQuota,
+			"updated_at":        time.Now(),
+		}).Error
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+)
+
+type apiKeyMemoryImpl struct {
+	mu     sync.RWMutex
+	keys   map[uint]model.APIKey
+	nextID uint
+}
+
+// NewAPIKeyMemoryRepository returns an empty repository of API keys kept in memory, for the
+// memory storage backend. Nothing is persisted, keys are lost on restart.
+func NewAPIKeyMemoryRepository() APIKeyRepository {
+	return &apiKeyMemoryImpl{
+		keys:   make(map[uint]model.APIKey),
+		nextID: 1,
+	}
+}
+
+func (r *apiKeyMemoryImpl) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.keys {
+		if stored.Hash == key.Hash {
+			return errors.New("an api key with this hash already exists")
+		}
+	}
+
+	now := time.Now()
+	key.ID = r.nextID
+	key.CreatedAt = now
+	key.UpdatedAt = now
+	r.nextID++
+
+	stored := *key
+	stored.Key = ""
+	r.keys[key.ID] = stored
+
+	return nil
+}
+
+func (r *apiKeyMemoryImpl) GetAPIKeys(ctx context.Context, ids []uint) ([]model.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []model.APIKey
+	for _, key := range r.keys {
+		if len(ids) > 0 && !containsUint(ids, key.ID) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+func (r *apiKeyMemoryImpl) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *apiKeyMemoryImpl) RevokeAPIKey(ctx context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok || key.RevokedAt != nil {
+		return nil
+	}
+	key.RevokedAt = &at
+	key.UpdatedAt = at
+	r.keys[id] = key
+
+	return nil
+}
+
+func (r *apiKeyMemoryImpl) UpdateAPIKeyLimit(ctx context.Context, id uint, limit model.RateLimit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil
+	}
+	key.Limit = limit
+	key.UpdatedAt = time.Now()
+	r.keys[id] = key
+
+	return nil
+}
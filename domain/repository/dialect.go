@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+)
+
+// sqlDialect holds the SQL fragments that differ between the supported spatial databases,
+// everything else in the gorm based repositories is shared.
+type sqlDialect struct {
+	// like is the case-insensitive pattern match operator.
+	like string
+	// geomFromText converts a WKT placeholder into a value of the geometry column.
+	geomFromText string
+	// containsPoint matches rows whose geometry contains the point given as the named
+	// arguments @point (WKT), @lng and @lat.
+	containsPoint string
+	// intersectsBbox matches rows whose geometry envelope intersects the box given as the
+	// named arguments @envelope (WKT polygon), @minLng, @minLat, @maxLng and @maxLat.
+	intersectsBbox string
+	// intersects matches rows whose geometry shares a point with the geometry given as the
+	// named arguments @geometry (WKT), @minLng, @minLat, @maxLng and @maxLat, its bounds.
+	intersects string
+	// least is the function returning the smallest of its arguments.
+	least string
+	// upsert returns the clause appended to a bulk insert that updates rows whose key, one
+	// or more comma separated columns, already exists.
+	upsert func(key string, columns []string) string
+}
+
+var mysqlDialect = sqlDialect{
+	like:           "LIKE",
+	geomFromText:   "ST_GeomFromText(?)",
+	containsPoint:  "ST_Contains(geometry, ST_GeomFromText(@point))",
+	intersectsBbox: "MBRIntersects(geometry, ST_GeomFromText(@envelope))",
+	intersects:     "ST_Intersects(geometry, ST_GeomFromText(@geometry))",
+	least:          "LEAST",
+	upsert: func(key string, columns []string) string {
+		var sets []string
+		for _, c := range append(strings.Split(key, ", "), columns...) {
+			sets = append(sets, fmt.Sprintf("%s=VALUES(%s)", c, c))
+		}
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	},
+}
+
+var postgisDialect = sqlDialect{
+	like:           "ILIKE",
+	geomFromText:   "ST_Multi(ST_GeomFromText(?, 4326))",
+	containsPoint:  "ST_Contains(geometry, ST_GeomFromText(@point, 4326))",
+	intersectsBbox: "geometry && ST_GeomFromText(@envelope, 4326)",
+	intersects:     "ST_Intersects(geometry, ST_GeomFromText(@geometry, 4326))",
+	least:          "LEAST",
+	upsert:         onConflictUpsert,
+}
+
+// sqliteDialect relies on the ST_* functions registered by pkg/gorm and on the
+// geospatial_rtree table, which triggers keep in sync with the bbox columns.
+var sqliteDialect = sqlDialect{
+	like:           "LIKE",
+	geomFromText:   "ST_GeomFromText(?)",
+	containsPoint:  "id IN (SELECT id FROM geospatial_rtree WHERE min_lng <= @lng AND max_lng >= @lng AND min_lat <= @lat AND max_lat >= @lat) AND ST_Contains(geometry, @point)",
+	intersectsBbox: "id IN (SELECT id FROM geospatial_rtree WHERE max_lng >= @minLng AND min_lng <= @maxLng AND max_lat >= @minLat AND min_lat <= @maxLat)",
+	intersects:     "id IN (SELECT id FROM geospatial_rtree WHERE max_lng >= @minLng AND min_lng <= @maxLng AND max_lat >= @minLat AND min_lat <= @maxLat) AND ST_Intersects(geometry, @geometry)",
+	least:          "MIN",
+	upsert:         onConflictUpsert,
+}
+
+// distanceKm returns the haversine distance in km between the label point and p, like
+// geo.Haversine computes it. The coordinates are formatted into the expression, which is
+// also an ORDER BY term, they are numbers parsed by the handler.
+func (d sqlDialect) distanceKm(p model.LatLng) string {
+	lat := "(" + strconv.FormatFloat(p.Lat, 'g', -1, 64) + ")"
+	lng := "(" + strconv.FormatFloat(p.Lng, 'g', -1, 64) + ")"
+	return fmt.Sprintf("(2 * %s * ASIN(%s(1, SQRT(POWER(SIN(RADIANS(label_lat - %s) / 2), 2) + COS(RADIANS(%s)) * COS(RADIANS(label_lat)) * POWER(SIN(RADIANS(label_lng - %s) / 2), 2)))))",
+		strconv.FormatFloat(geo.EarthRadius/1000, 'g', -1, 64), d.least, lat, lat, lng)
+}
+
+// on returns the dialect for the spatial filters of table, which has a geometry column like
+// geospatial and, on SQLite, an R-tree named after it.
+func (d sqlDialect) on(table string) sqlDialect {
+	rtree := strings.NewReplacer("geospatial_rtree", table+"_rtree")
+	d.containsPoint = rtree.Replace(d.containsPoint)
+	d.intersectsBbox = rtree.Replace(d.intersectsBbox)
+	d.intersects = rtree.Replace(d.intersects)
+	return d
+}
+
+func onConflictUpsert(key string, columns []string) string {
+	var sets []string
+	for _, c := range columns {
+		sets = append(sets, fmt.Sprintf("%s=EXCLUDED.%s", c, c))
+	}
+	// Neither PostgreSQL nor SQLite have ON UPDATE CURRENT_TIMESTAMP, so the timestamp is bumped here.
+	sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", key, strings.Join(sets, ", "))
+}
+
+func dialectOf(name string) sqlDialect {
+	switch name {
+	case constant.DbDriverPostgres:
+		return postgisDialect
+	case constant.DbDriverSqlite:
+		return sqliteDialect
+	}
+	return mysqlDialect
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+type EventRepository interface {
+	AddEvents(context.Context, []model.OutboxEvent) error
+	GetEvents(context.Context, []uint) ([]model.OutboxEvent, error)
+	FanOut(context.Context, time.Time, int) (int, error)
+	CreateWebhook(context.Context, *model.Webhook) error
+	GetWebhooks(context.Context, []uint) ([]model.Webhook, error)
+	DeleteWebhook(context.Context, uint) error
+	ClaimDeliveries(context.Context, time.Time, time.Time, int) ([]model.WebhookDelivery, error)
+	SaveDelivery(context.Context, model.WebhookDelivery) error
+	GetDeliveriesPaginate(context.Context, uint, pagination.Param) ([]model.WebhookDelivery, *pagination.Param, error)
+}
+
+type eventImpl struct {
+	db *gorm.DB
+}
+
+// NewEventRepository returns the outbox of domain events and the webhooks they are delivered
+// to, stored next to the regions in the database db is connected to.
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventImpl{db: db}
+}
+
+// addEvents writes events to the outbox in tx, the transaction of the change they describe.
+func addEvents(tx *gorm.DB, events []model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Omit("dispatched_at").Create(&events).Error
+}
+
+// AddEvents writes events that are not part of a change, e.g. a failure.
+func (r *eventImpl) AddEvents(ctx context.Context, events []model.OutboxEvent) error {
+	return addEvents(r.db.Clauses(dbresolver.Write), events)
+}
+
+func (r *eventImpl) GetEvents(ctx context.Context, ids []uint) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	if err := r.db.Clauses(dbresolver.Write).Where("id IN (?)", ids).Order("id ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// FanOut creates the deliveries of up to limit events not dispatched yet, one per webhook
+// subscribed to their type, due at now. It returns the number of events dispatched.
+func (r *eventImpl) FanOut(ctx context.Context, now time.Time, limit int) (int, error) {
+	var dispatched int
+	err := r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		var events []model.OutboxEvent
+		if err := tx.Select("id, type").Where("dispatched_at IS NULL").Order("id ASC").Limit(limit).Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		var webhooks []model.Webhook
+		if err := tx.Select("id, events").Find(&webhooks).Error; err != nil {
+			return err
+		}
+
+		now = now.UTC()
+		ids := make([]uint, 0, len(events))
+		var deliveries []model.WebhookDelivery
+		for _, e := range events {
+			ids = append(ids, e.ID)
+			for _, w := range webhooks {
+				if subscribed(w, e.Type) {
+					deliveries = append(deliveries, model.WebhookDelivery{
+						WebhookID:     w.ID,
+						EventID:       e.ID,
+						EventType:     e.Type,
+						Status:        constant.WebhookDeliveryPending,
+						NextAttemptAt: now,
+					})
+				}
+			}
+		}
+
+		// Another dispatcher may have fanned out the same events, their deliveries are kept.
+		if len(deliveries) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&deliveries, 500).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&model.OutboxEvent{}).Where("id IN (?)", ids).Update("dispatched_at", now).Error; err != nil {
+			return err
+		}
+
+		dispatched = len(events)
+		return nil
+	})
+
+	return dispatched, err
+}
+
+// subscribed reports whether webhook w takes events of type eventType.
+func subscribed(w model.Webhook, eventType string) bool {
+	if w.Events == "" {
+		return true
+	}
+	for _, t := range strings.Split(w.Events, ",") {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *eventImpl) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	return r.db.Clauses(dbresolver.Write).Create(webhook).Error
+}
+
+// GetWebhooks returns the webhooks with the given ids, or every webhook when there are none.
+func (r *eventImpl) GetWebhooks(ctx context.Context, ids []uint) ([]model.Webhook, error) {
+	chain := r.db.Clauses(dbresolver.Write).Model(&model.Webhook{})
+	if len(ids) > 0 {
+		chain = chain.Where("id IN (?)", ids)
+	}
+
+	var webhooks []model.Webhook
+	if err := chain.Order("id ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes the webhook with the given id and its deliveries.
+func (r *eventImpl) DeleteWebhook(ctx context.Context, id uint) error {
+	return r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM webhook_delivery WHERE webhook_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM webhook WHERE id = ?", id).Error
+	})
+}
+
+// ClaimDeliveries returns up to limit pending deliveries due at now and moves their next
+// attempt to until, so that other dispatchers leave them alone while they are sent.
+func (r *eventImpl) ClaimDeliveries(ctx context.Context, now, until time.Time, limit int) ([]model.WebhookDelivery, error) {
+	// A new session per query, the claims would otherwise pile up their conditions.
+	db := r.db.Clauses(dbresolver.Write).Session(&gorm.Session{})
+	now, until = now.UTC(), until.UTC()
+
+	var due []model.WebhookDelivery
+	if err := db.Where("status = ? AND next_attempt_at <= ?", constant.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").Limit(limit).Find(&due).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := due[:0]
+	for _, d := range due {
+		result := db.Model(&model.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at <= ?", d.ID, constant.WebhookDeliveryPending, now).
+			Update("next_attempt_at", until)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			d.NextAttemptAt = until
+			claimed = append(claimed, d)
+		}
+	}
+
+	return claimed, nil
+}
+
+// SaveDelivery writes the outcome of an attempt of delivery.
+func (r *eventImpl) SaveDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	delivery.NextAttemptAt = delivery.NextAttemptAt.UTC()
+	return r.db.Clauses(dbresolver.Write).Model(&delivery).
+		Select("status", "attempts", "next_attempt_at", "status_code", "error", "delivered_at", "updated_at").
+		Updates(&delivery).Error
+}
+
+// GetDeliveriesPaginate returns the deliveries of a webhook, the latest first.
+func (r *eventImpl) GetDeliveriesPaginate(ctx context.Context, webhookID uint, param pagination.Param) ([]model.WebhookDelivery, *pagination.Param, error) {
+	filtered := func() *gorm.DB {
+		return r.db.Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	}
+
+	var deliveries []model.WebhookDelivery
+	if err := filtered().
+		Scopes(pagination.Paginate(model.WebhookDelivery{}, &param, filtered())).
+		Order("id DESC").
+		Find(&deliveries).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return deliveries, &param, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+type eventMemoryImpl struct {
+	mu         sync.Mutex
+	events     []model.OutboxEvent
+	webhooks   map[uint]model.Webhook
+	deliveries []model.WebhookDelivery
+
+	lastEventID    uint
+	lastWebhookID  uint
+	lastDeliveryID uint
+}
+
+// NewEventMemoryRepository returns an empty outbox and webhook registry kept in memory, for
+// the memory storage backend. Nothing is persisted.
+func NewEventMemoryRepository() EventRepository {
+	return &eventMemoryImpl{webhooks: make(map[uint]model.Webhook)}
+}
+
+func (r *eventMemoryImpl) AddEvents(ctx context.Context, events []model.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := range events {
+		r.lastEventID++
+		events[i].ID = r.lastEventID
+		events[i].CreatedAt = now
+		r.events = append(r.events, events[i])
+	}
+
+	return nil
+}
+
+func (r *eventMemoryImpl) GetEvents(ctx context.Context, ids []uint) ([]model.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []model.OutboxEvent
+	for _, e := range r.events {
+		if containsUint(ids, e.ID) {
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+func (r *eventMemoryImpl) FanOut(ctx context.Context, now time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhooks := r.sortedWebhooks(nil)
+
+	var dispatched int
+	for i := range r.events {
+		e := &r.events[i]
+		if e.DispatchedAt != nil {
+			continue
+		}
+		if dispatched == limit {
+			break
+		}
+
+		for _, w := range webhooks {
+			if !subscribed(w, e.Type) {
+				continue
+			}
+			r.lastDeliveryID++
+			r.deliveries = append(r.deliveries, model.WebhookDelivery{
+				ID:            r.lastDeliveryID,
+				WebhookID:     w.ID,
+				EventID:       e.ID,
+				EventType:     e.Type,
+				Status:        constant.WebhookDeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			})
+		}
+
+		dispatchedAt := now
+		e.DispatchedAt = &dispatchedAt
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+func (r *eventMemoryImpl) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.lastWebhookID++
+	webhook.ID = r.lastWebhookID
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	r.webhooks[webhook.ID] = *webhook
+
+	return nil
+}
+
+func (r *eventMemoryImpl) GetWebhooks(ctx context.Context, ids []uint) ([]model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.sortedWebhooks(ids), nil
+}
+
+func (r *eventMemoryImpl) sortedWebhooks(ids []uint) []model.Webhook {
+	var webhooks []model.Webhook
+	for _, w := range r.webhooks {
+		if len(ids) == 0 || containsUint(ids, w.ID) {
+			webhooks = append(webhooks, w)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+
+	return webhooks
+}
+
+func (r *eventMemoryImpl) DeleteWebhook(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.webhooks, id)
+	kept := r.deliveries[:0]
+	for _, d := range r.deliveries {
+		if d.WebhookID != id {
+			kept = append(kept, d)
+		}
+	}
+	r.deliveries = kept
+
+	return nil
+}
+
+func (r *eventMemoryImpl) ClaimDeliveries(ctx context.Context, now, until time.Time, limit int) ([]model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*model.WebhookDelivery
+	for i := range r.deliveries {
+		d := &r.deliveries[i]
+		if d.Status == constant.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]model.WebhookDelivery, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = until
+		claimed = append(claimed, *d)
+	}
+
+	return claimed, nil
+}
+
+func (r *eventMemoryImpl) SaveDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == delivery.ID {
+			delivery.UpdatedAt = time.Now()
+			r.deliveries[i] = delivery
+		}
+	}
+
+	return nil
+}
+
+func (r *eventMemoryImpl) GetDeliveriesPaginate(ctx context.Context, webhookID uint, param pagination.Param) ([]model.WebhookDelivery, *pagination.Param, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matches []model.WebhookDelivery
+	for i := len(r.deliveries) - 1; i >= 0; i-- {
+		if r.deliveries[i].WebhookID == webhookID {
+			matches = append(matches, r.deliveries[i])
+		}
+	}
+
+	if !param.SkipTotal {
+		param.SetTotal(int64(len(matches)))
+	}
+
+	start := int(param.GetOffset())
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := start + int(param.GetLimit())
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	return append([]model.WebhookDelivery{}, matches[start:end]...), &param, nil
+}
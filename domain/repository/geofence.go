@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+// ErrGeofenceObjectChanged is returned by SaveObject when the object was saved by someone else
+// since it was read.
+var ErrGeofenceObjectChanged = errors.New("tracked object was changed concurrently")
+
+type GeofenceRepository interface {
+	CreateGeofence(context.Context, *model.Geofence) error
+	GetGeofences(context.Context, []uint) ([]model.Geofence, error)
+	DeleteGeofence(context.Context, uint) error
+	GetContaining(context.Context, float64, float64, []uint) ([]model.Geofence, error)
+	GetObject(context.Context, string) (*model.GeofenceObject, []model.GeofenceState, error)
+	SaveObject(context.Context, model.GeofenceObject, []model.GeofenceState) error
+}
+
+type geofenceImpl struct {
+	db      *gorm.DB
+	dialect sqlDialect
+}
+
+// NewGeofenceRepository returns the repository of the fences and tracked objects stored next
+// to the regions in the database db is connected to.
+func NewGeofenceRepository(db *gorm.DB) GeofenceRepository {
+	return &geofenceImpl{
+		db:      db,
+		dialect: dialectOf(db.Dialector.Name()).on("geofence"),
+	}
+}
+
+// geofenceColumns are the columns of a fence, the geometry of a region fence is empty.
+const geofenceColumns = "id, name, geospatial_id, COALESCE(ST_AsText(geometry), '') AS geometry, " +
+	"bbox_min_lng, bbox_min_lat, bbox_max_lng, bbox_max_lat, dwell_seconds, created_at, updated_at"
+
+// CreateGeofence writes geofence and sets its id.
+func (r *geofenceImpl) CreateGeofence(ctx context.Context, geofence *model.Geofence) error {
+	return r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("geometry").Create(geofence).Error; err != nil {
+			return err
+		}
+		if geofence.Geometry == "" {
+			return nil
+		}
+		return tx.Exec("UPDATE geofence SET geometry = "+r.dialect.geomFromText+" WHERE id = ?", geofence.Geometry, geofence.ID).Error
+	})
+}
+
+// GetGeofences returns the fences with the given ids, or every fence when there are none.
+func (r *geofenceImpl) GetGeofences(ctx context.Context, ids []uint) ([]model.Geofence, error) {
+	chain := r.db.Model(&model.Geofence{}).Select(geofenceColumns)
+	if len(ids) > 0 {
+		chain.Where("id IN (?)", ids)
+	}
+
+	var geofences []model.Geofence
+	if err := chain.Order("id ASC").Find(&geofences).Error; err != nil {
+		return nil, err
+	}
+
+	return geofences, nil
+}
+
+// DeleteGeofence deletes the fence with the given id and the objects' state in it.
+func (r *geofenceImpl) DeleteGeofence(ctx context.Context, id uint) error {
+	return r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM geofence_state WHERE geofence_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM geofence WHERE id = ?", id).Error
+	})
+}
+
+// GetContaining returns the fences the point lat, lng is in: the fences of the regions with
+// the given ids, which contain it, and the polygon fences containing it.
+func (r *geofenceImpl) GetContaining(ctx context.Context, lat, lng float64, geospatialIds []uint) ([]model.Geofence, error) {
+	polygons := r.db.Where("geospatial_id IS NULL")
+	wherePoint(polygons, r.dialect, lat, lng)
+
+	chain := r.db.Model(&model.Geofence{}).
+		Select("id, name, geospatial_id, dwell_seconds, created_at, updated_at").
+		Where(polygons)
+	if len(geospatialIds) > 0 {
+		chain.Or("geospatial_id IN (?)", geospatialIds)
+	}
+
+	var geofences []model.Geofence
+	if err := chain.Order("id ASC").Find(&geofences).Error; err != nil {
+		return nil, err
+	}
+
+	return geofences, nil
+}
+
+// GetObject returns the last position of the object with the given id and the fences it is
+// in, or nil when it was never seen. It reads from the primary, a replica may lag behind the
+// last SaveObject.
+func (r *geofenceImpl) GetObject(ctx context.Context, objectID string) (*model.GeofenceObject, []model.GeofenceState, error) {
+	var object *model.GeofenceObject
+	var states []model.GeofenceState
+	err := r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		var objects []model.GeofenceObject
+		if err := tx.Where("object_id = ?", objectID).Limit(1).Find(&objects).Error; err != nil {
+			return err
+		}
+		if len(objects) == 0 {
+			return nil
+		}
+		object = &objects[0]
+
+		return tx.Where("object_id = ?", objectID).Order("geofence_id ASC").Find(&states).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return object, states, nil
+}
+
+// SaveObject writes the last position of object and replaces the fences it is in. The
+// object's Version is the one it was read with, 0 for a new object, it fails with
+// ErrGeofenceObjectChanged when the stored object has another version.
+func (r *geofenceImpl) SaveObject(ctx context.Context, object model.GeofenceObject, states []model.GeofenceState) error {
+	return r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		var saved *gorm.DB
+		if object.Version == 0 {
+			object.Version = 1
+			saved = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&object)
+		} else {
+			saved = tx.Exec("UPDATE geofence_object SET lat = ?, lng = ?, seen_at = ?, version = version + 1 WHERE object_id = ? AND version = ?",
+				object.Lat, object.Lng, object.SeenAt, object.ObjectID, object.Version)
+		}
+		if saved.Error != nil {
+			return saved.Error
+		}
+		if saved.RowsAffected == 0 {
+			return ErrGeofenceObjectChanged
+		}
+
+		if err := tx.Exec("DELETE FROM geofence_state WHERE object_id = ?", object.ObjectID).Error; err != nil {
+			return err
+		}
+		if len(states) == 0 {
+			return nil
+		}
+		return tx.Create(&states).Error
+	})
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/wkt"
+)
+
+type memoryGeofence struct {
+	geofence model.Geofence
+	// geometry is nil for a region fence.
+	geometry *geom.MultiPolygon
+}
+
+type geofenceMemoryImpl struct {
+	mu        sync.RWMutex
+	geofences map[uint]*memoryGeofence
+	objects   map[string]model.GeofenceObject
+	states    map[string][]model.GeofenceState
+	nextID    uint
+}
+
+// NewGeofenceMemoryRepository returns an empty repository of fences and tracked objects kept
+// in memory, for the memory storage backend. Nothing is persisted.
+func NewGeofenceMemoryRepository() GeofenceRepository {
+	return &geofenceMemoryImpl{
+		geofences: make(map[uint]*memoryGeofence),
+		objects:   make(map[string]model.GeofenceObject),
+		states:    make(map[string][]model.GeofenceState),
+		nextID:    1,
+	}
+}
+
+func (r *geofenceMemoryImpl) CreateGeofence(ctx context.Context, geofence *model.Geofence) error {
+	item := &memoryGeofence{}
+	if geofence.Geometry != "" {
+		t, err := wkt.Unmarshal(geofence.Geometry)
+		if err != nil {
+			return fmt.Errorf("failed to decode geometry of fence %q: %w", geofence.Name, err)
+		}
+		if item.geometry = geo.ToMultiPolygon(t); item.geometry == nil {
+			return fmt.Errorf("unexpected geometry type %T of fence %q", t, geofence.Name)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	geofence.ID = r.nextID
+	geofence.CreatedAt = now
+	geofence.UpdatedAt = now
+	r.nextID++
+
+	item.geofence = *geofence
+	r.geofences[geofence.ID] = item
+
+	return nil
+}
+
+func (r *geofenceMemoryImpl) GetGeofences(ctx context.Context, ids []uint) ([]model.Geofence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var geofences []model.Geofence
+	for _, item := range r.geofences {
+		if len(ids) > 0 && !containsUint(ids, item.geofence.ID) {
+			continue
+		}
+		geofences = append(geofences, item.geofence)
+	}
+	sort.Slice(geofences, func(i, j int) bool { return geofences[i].ID < geofences[j].ID })
+
+	return geofences, nil
+}
+
+func (r *geofenceMemoryImpl) DeleteGeofence(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.geofences, id)
+	for objectID, states := range r.states {
+		kept := states[:0]
+		for _, s := range states {
+			if s.GeofenceID != id {
+				kept = append(kept, s)
+			}
+		}
+		r.states[objectID] = kept
+	}
+
+	return nil
+}
+
+// GetContaining checks every polygon fence, fences are few next to the regions.
+func (r *geofenceMemoryImpl) GetContaining(ctx context.Context, lat, lng float64, geospatialIds []uint) ([]model.Geofence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var geofences []model.Geofence
+	for _, item := range r.geofences {
+		g := item.geofence
+		if item.geometry != nil && geo.ContainsPoint(item.geometry, lng, lat) ||
+			g.GeospatialID != nil && containsUint(geospatialIds, *g.GeospatialID) {
+			g.Geometry = ""
+			geofences = append(geofences, g)
+		}
+	}
+	sort.Slice(geofences, func(i, j int) bool { return geofences[i].ID < geofences[j].ID })
+
+	return geofences, nil
+}
+
+func (r *geofenceMemoryImpl) GetObject(ctx context.Context, objectID string) (*model.GeofenceObject, []model.GeofenceState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	object, ok := r.objects[objectID]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	states := append([]model.GeofenceState(nil), r.states[objectID]...)
+	sort.Slice(states, func(i, j int) bool { return states[i].GeofenceID < states[j].GeofenceID })
+
+	return &object, states, nil
+}
+
+func (r *geofenceMemoryImpl) SaveObject(ctx context.Context, object model.GeofenceObject, states []model.GeofenceState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.objects[object.ObjectID].Version != object.Version {
+		return ErrGeofenceObjectChanged
+	}
+	object.Version++
+	r.objects[object.ObjectID] = object
+	r.states[object.ObjectID] = append([]model.GeofenceState(nil), states...)
+
+	return nil
+}
@@ -6,37 +6,86 @@ import (
 	"strings"
 
 	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/search"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
 	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
 	"github.com/twpayne/go-geom"
 	"github.com/twpayne/go-geom/encoding/wkt"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
 type GeospatialRepository interface {
-	FilteredDb(model.GeospatialFilter) *gorm.DB
-
 	Get(context.Context, model.GeospatialFilter) ([]model.Geospatial, error)
+	GetWithGeometry(context.Context, model.GeospatialFilter) ([]model.Geospatial, error)
+	GetByGadmIds(context.Context, []string) ([]model.Geospatial, error)
+	GetNames(context.Context, []uint) ([]model.GeospatialName, error)
 	GetPaginate(context.Context, model.GeospatialFilter, pagination.Param) ([]model.Geospatial, *pagination.Param, error)
-	GetTypes(context.Context) ([]string, error)
-	GetLevels(context.Context) ([]uint, error)
-	UpsertBulk(context.Context, []model.Geospatial) error
+	GetTypes(context.Context, model.GeospatialFilter) ([]model.GeospatialTypeCount, error)
+	GetExamples(context.Context, model.GeospatialFilter, int) ([]model.Geospatial, error)
+	Search(context.Context, string, model.GeospatialFilter, int) ([]model.Geospatial, error)
+	UpsertBulk(context.Context, []model.Geospatial, ...model.OutboxEvent) error
+	Delete(context.Context, []uint, ...model.OutboxEvent) error
+	GetNeighbors(context.Context, uint) ([]model.GeospatialNeighbor, error)
+	IndexNeighbors(context.Context) error
 }
 
 type geospatialImpl struct {
-	db *gorm.DB
+	db      *gorm.DB
+	dialect sqlDialect
 }
 
+// NewGeospatialRepository returns the repository for the database db is connected to,
+// MySQL, PostgreSQL with PostGIS or SQLite.
 func NewGeospatialRepository(db *gorm.DB) GeospatialRepository {
 	return &geospatialImpl{
-		db: db,
+		db:      db,
+		dialect: dialectOf(db.Dialector.Name()),
+	}
+}
+
+// descendantsQuery selects the GADM ids of every region below the region with the given id.
+const descendantsQuery = `WITH RECURSIVE descendant (gadm_id) AS (
+	SELECT gadm_id FROM geospatial WHERE parent_gadm_id = (SELECT gadm_id FROM geospatial WHERE id = ?)
+	UNION ALL
+	SELECT g.gadm_id FROM geospatial g JOIN descendant d ON g.parent_gadm_id = d.gadm_id
+) SELECT gadm_id FROM descendant`
+
+// listColumns are the columns read when a filter does not pick any, all but the geometry,
+// which is expensive to read and only needed when asked for.
+var listColumns = []string{
+	"id", "gadm_id", "parent_gadm_id", "name", "type", "eng_type", "level",
+	"centroid_lat", "centroid_lng", "label_lat", "label_lng", "area_km2", "perimeter_km",
+	"bbox_min_lng", "bbox_min_lat", "bbox_max_lng", "bbox_max_lat", "created_at", "updated_at",
+}
+
+// selectColumns returns the select list of filter: its columns, the geometry as WKT when
+// asked for and the distance from the near point.
+func (r *geospatialImpl) selectColumns(filter model.GeospatialFilter) string {
+	columns := listColumns
+	if len(filter.Columns) > 0 {
+		columns = filter.Columns
+	}
+	selects := strings.Join(columns, ", ")
+
+	if filter.WithGeometry {
+		selects += ", ST_AsText(geometry) AS geometry"
 	}
+	if filter.Near != nil {
+		selects += ", " + r.dialect.distanceKm(*filter.Near) + " AS distance_km"
+	}
+	return selects
 }
 
 func (r *geospatialImpl) FilteredDb(filter model.GeospatialFilter) *gorm.DB {
-	chain := r.db.Model(&model.Geospatial{})
+	chain := r.db.Model(&model.Geospatial{}).Select(r.selectColumns(filter))
+
+	if len(filter.IDs) > 0 {
+		chain.Where("id IN (?)", filter.IDs)
+	}
 
 	if filter.Name != "" {
-		chain.Where("name LIKE ?", "%"+filter.Name+"%")
+		chain.Where(fmt.Sprintf("name %s ?", r.dialect.like), "%"+filter.Name+"%")
 	}
 
 	if filter.Levels != nil && len(filter.Levels) > 0 {
@@ -44,7 +93,7 @@ func (r *geospatialImpl) FilteredDb(filter model.GeospatialFilter) *gorm.DB {
 	}
 
 	if filter.Types != nil && len(filter.Types) > 0 {
-		chain.Where("type IN (?)", filter.Types)
+		chain.Where("(type IN (?) OR eng_type IN (?))", filter.Types, filter.Types)
 	}
 
 	if filter.ExcludedIds != nil && len(filter.ExcludedIds) > 0 {
@@ -55,15 +104,85 @@ func (r *geospatialImpl) FilteredDb(filter model.GeospatialFilter) *gorm.DB {
 		chain.Where("parent_gadm_id IN (SELECT gadm_id FROM geospatial WHERE id IN (?))", filter.ParentIds)
 	}
 
-	if filter.Lat != 0 && filter.Lng != 0 {
-		point := geom.NewPointFlat(geom.XY, []float64{filter.Lng, filter.Lat})
-		wktString, err := wkt.Marshal(point)
+	if filter.AncestorID != 0 {
+		chain.Where("gadm_id IN ("+descendantsQuery+")", filter.AncestorID)
+	}
+
+	// Country codes are validated to letters and digits, nothing LIKE would interpret.
+	if filter.Country != "" {
+		chain.Where("gadm_id LIKE ?", filter.Country+"%")
+	}
+
+	if filter.MinAreaKm2 > 0 {
+		chain.Where("area_km2 >= ?", filter.MinAreaKm2)
+	}
+
+	if filter.MaxAreaKm2 > 0 {
+		chain.Where("area_km2 <= ?", filter.MaxAreaKm2)
+	}
+
+	whereSpatial(chain, r.dialect, filter.Point(), filter.Bbox, filter.Intersects)
+
+	return chain
+}
+
+// whereSpatial adds the filters regions and layer features share to chain: the rows
+// containing point, intersecting bbox and sharing a point with the WKT geometry intersects,
+// each when given.
+func whereSpatial(chain *gorm.DB, dialect sqlDialect, point *model.LatLng, bbox []float64, intersects string) {
+	if point != nil {
+		wherePoint(chain, dialect, point.Lat, point.Lng)
+	}
+
+	if len(bbox) == 4 {
+		envelope := geom.NewPolygonFlat(geom.XY, []float64{
+			bbox[0], bbox[1],
+			bbox[2], bbox[1],
+			bbox[2], bbox[3],
+			bbox[0], bbox[3],
+			bbox[0], bbox[1],
+		}, []int{10})
+		wktString, err := wkt.Marshal(envelope)
 		if err == nil {
-			chain.Where("ST_Contains(geometry, ST_GeomFromText(?))", wktString)
+			chain.Where(dialect.intersectsBbox, map[string]interface{}{
+				"envelope": wktString,
+				"minLng":   bbox[0],
+				"minLat":   bbox[1],
+				"maxLng":   bbox[2],
+				"maxLat":   bbox[3],
+			})
 		}
 	}
 
-	return chain
+	if intersects != "" {
+		// A geometry that cannot be read matches nothing rather than everything.
+		t, err := wkt.Unmarshal(intersects)
+		if err != nil || t.Bounds().IsEmpty() {
+			chain.Where("1 = 0")
+		} else {
+			b := t.Bounds()
+			chain.Where(dialect.intersects, map[string]interface{}{
+				"geometry": intersects,
+				"minLng":   b.Min(0),
+				"minLat":   b.Min(1),
+				"maxLng":   b.Max(0),
+				"maxLat":   b.Max(1),
+			})
+		}
+	}
+}
+
+// wherePoint keeps the rows whose geometry contains the point lat, lng.
+func wherePoint(chain *gorm.DB, dialect sqlDialect, lat, lng float64) {
+	point := geom.NewPointFlat(geom.XY, []float64{lng, lat})
+	wktString, err := wkt.Marshal(point)
+	if err == nil {
+		chain.Where(dialect.containsPoint, map[string]interface{}{
+			"point": wktString,
+			"lng":   lng,
+			"lat":   lat,
+		})
+	}
 }
 
 func (r *geospatialImpl) Get(ctx context.Context, filter model.GeospatialFilter) ([]model.Geospatial, error) {
@@ -76,58 +195,337 @@ func (r *geospatialImpl) Get(ctx context.Context, filter model.GeospatialFilter)
 	return geospatials, nil
 }
 
+func (r *geospatialImpl) GetWithGeometry(ctx context.Context, filter model.GeospatialFilter) ([]model.Geospatial, error) {
+	var geospatials []model.Geospatial
+
+	filter.WithGeometry = true
+	if err := r.FilteredDb(filter).
+		Order("level ASC, id ASC").
+		Find(&geospatials).Error; err != nil {
+		return nil, err
+	}
+
+	return geospatials, nil
+}
+
+// GetByGadmIds returns the regions with the given GADM ids without their geometry, its
+// attributes are read so an upsert can tell whether a region changed.
+func (r *geospatialImpl) GetByGadmIds(ctx context.Context, gadmIds []string) ([]model.Geospatial, error) {
+	var geospatials []model.Geospatial
+	if len(gadmIds) == 0 {
+		return geospatials, nil
+	}
+
+	if err := r.db.Model(&model.Geospatial{}).
+		Select("id, gadm_id, parent_gadm_id, name, type, eng_type, level, centroid_lat, centroid_lng, label_lat, label_lng, "+
+			"area_km2, perimeter_km, bbox_min_lng, bbox_min_lat, bbox_max_lng, bbox_max_lat").
+		Where("gadm_id IN (?)", gadmIds).
+		Find(&geospatials).Error; err != nil {
+		return nil, err
+	}
+
+	return geospatials, nil
+}
+
 func (r *geospatialImpl) GetPaginate(ctx context.Context, filter model.GeospatialFilter, param pagination.Param) ([]model.Geospatial, *pagination.Param, error) {
+	if param.UseCursor {
+		return r.getCursorPage(filter, param)
+	}
+
+	columns, err := keysetColumns(param.Sort, filter, r.sortExpr(filter))
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var geospatials []model.Geospatial
 
-	filteredDb := r.FilteredDb(filter)
+	chain := r.FilteredDb(filter)
 
-	if err := filteredDb.Scopes(pagination.Paginate(model.Geospatial{}, &param, filteredDb)).Find(&geospatials).Error; err != nil {
+	if err := chain.Scopes(pagination.Paginate(model.Geospatial{}, &param, r.FilteredDb(filter))).
+		Order(pagination.KeysetOrder(columns)).
+		Find(&geospatials).Error; err != nil {
 		return nil, nil, err
 	}
 
 	return geospatials, &param, nil
 }
 
-func (r *geospatialImpl) GetTypes(ctx context.Context) ([]string, error) {
+// sortExpr returns the SQL of the computed sort fields.
+func (r *geospatialImpl) sortExpr(filter model.GeospatialFilter) func(field string) string {
+	return func(field string) string {
+		if field == "distance_km" && filter.Near != nil {
+			return r.dialect.distanceKm(*filter.Near)
+		}
+		return field
+	}
+}
+
+// getCursorPage returns the page after param.Cursor. Seeking by the sort key and the id keeps
+// deep pages as fast as the first, unlike OFFSET, and one extra row tells whether there is a
+// next page.
+func (r *geospatialImpl) getCursorPage(filter model.GeospatialFilter, param pagination.Param) ([]model.Geospatial, *pagination.Param, error) {
+	columns, err := keysetColumns(param.Sort, filter, r.sortExpr(filter))
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := sortFields(param.Sort)
+	values, err := decodeCursor(param.Cursor, columns, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination.Count(model.Geospatial{}, &param, r.FilteredDb(filter))
+
+	chain := r.FilteredDb(filter)
+	if values != nil {
+		where, args := pagination.KeysetWhere(columns, values)
+		chain.Where(where, args...)
+	}
+
 	var geospatials []model.Geospatial
-	if err := r.db.Model(&model.Geospatial{}).Select("type").Group("type").Find(&geospatials).Error; err != nil {
-		return nil, err
+	limit := int(param.GetLimit())
+	if err := chain.Order(pagination.KeysetOrder(columns)).Limit(limit + 1).Find(&geospatials).Error; err != nil {
+		return nil, nil, err
 	}
 
-	var types []string
-	for _, g := range geospatials {
-		types = append(types, g.Type)
+	if len(geospatials) > limit {
+		geospatials = geospatials[:limit]
+		param.NextCursor = pagination.EncodeCursor(columns, cursorValues(&geospatials[limit-1], fields))
+	}
+
+	return geospatials, &param, nil
+}
+
+// GetTypes counts the regions matching filter by country, normalized type, local type and
+// level, ordered by level, then by type.
+func (r *geospatialImpl) GetTypes(ctx context.Context, filter model.GeospatialFilter) ([]model.GeospatialTypeCount, error) {
+	var counts []model.GeospatialTypeCount
+	if err := r.FilteredDb(filter).
+		Select("SUBSTR(gadm_id, 1, 3) AS country, eng_type, type, level, COUNT(*) AS count").
+		Group("SUBSTR(gadm_id, 1, 3), eng_type, type, level").
+		Order("level ASC, eng_type ASC, type ASC, country ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, err
 	}
 
-	return types, nil
+	return counts, nil
 }
 
-func (r *geospatialImpl) GetLevels(ctx context.Context) ([]uint, error) {
+// GetExamples returns the limit largest regions matching filter. PostgreSQL sorts NULL first
+// when descending, so regions without an area are put last explicitly.
+func (r *geospatialImpl) GetExamples(ctx context.Context, filter model.GeospatialFilter, limit int) ([]model.Geospatial, error) {
 	var geospatials []model.Geospatial
-	if err := r.db.Model(&model.Geospatial{}).Select("level").Group("level").Find(&geospatials).Error; err != nil {
+	if err := r.FilteredDb(filter).
+		Select("id, gadm_id, name, type, eng_type, level, area_km2").
+		Order("area_km2 IS NULL, area_km2 DESC, id ASC").
+		Limit(limit).
+		Find(&geospatials).Error; err != nil {
 		return nil, err
 	}
 
-	var levels []uint
+	return geospatials, nil
+}
+
+var upsertColumns = []string{
+	"parent_gadm_id", "name", "search_name", "type", "eng_type", "level", "geometry",
+	"centroid_lat", "centroid_lng", "label_lat", "label_lng", "area_km2", "perimeter_km",
+	"bbox_min_lng", "bbox_min_lat", "bbox_max_lng", "bbox_max_lat",
+}
+
+// engType is the normalized type of g, regions built by hand may not have one.
+func engType(g model.Geospatial) string {
+	if g.EngType == "" {
+		return constant.EngtypeOther
+	}
+	return g.EngType
+}
+
+// insertChunkSize keeps the name and trigram inserts below the placeholder limits of every database.
+const insertChunkSize = 1000
+
+// UpsertBulk writes geospatials, replacing the regions with the same GADM id, and events to
+// the outbox in the same transaction.
+func (r *geospatialImpl) UpsertBulk(ctx context.Context, geospatials []model.Geospatial, events ...model.OutboxEvent) error {
+	var values []interface{}
+	var placeholders, gadmIds []string
 	for _, g := range geospatials {
-		levels = append(levels, g.Level)
+		gadmIds = append(gadmIds, g.GadmID)
+		values = append(values, g.GadmID, g.ParentGadmID, g.Name, search.Fold(g.Name), g.Type, engType(g), g.Level, g.Geometry,
+			g.CentroidLat, g.CentroidLng, g.LabelLat, g.LabelLng, g.AreaKm2, g.PerimeterKm,
+			g.BboxMinLng, g.BboxMinLat, g.BboxMaxLng, g.BboxMaxLat)
+		placeholders = append(placeholders, fmt.Sprintf("(?, ?, ?, ?, ?, ?, ?, %s, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", r.dialect.geomFromText))
 	}
 
-	return levels, nil
+	query := fmt.Sprintf("INSERT INTO geospatial (gadm_id, %s) VALUES %s %s", strings.Join(upsertColumns, ", "), strings.Join(placeholders, ", "), r.dialect.upsert("gadm_id", upsertColumns))
+	// Writes always go to the primary, only reads are routed to replicas.
+	return r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		var existing []model.Geospatial
+		if err := tx.Model(&model.Geospatial{}).Select(geometryAttributeColumns).Where("gadm_id IN (?)", gadmIds).Find(&existing).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(query, values...).Error; err != nil {
+			return err
+		}
+		if err := markStale(tx, geospatials, existing); err != nil {
+			return err
+		}
+		if err := r.indexNames(tx, geospatials); err != nil {
+			return err
+		}
+		return addEvents(tx, events)
+	})
 }
 
-func (r *geospatialImpl) UpsertBulk(ctx context.Context, geospatials []model.Geospatial) error {
-	var values []interface{}
-	var placeholders []string
+// Delete removes the regions with the given ids with their names and neighbours, and writes
+// events to the outbox in the same transaction.
+func (r *geospatialImpl) Delete(ctx context.Context, ids []uint, events ...model.OutboxEvent) error {
+	return r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM geospatial_name WHERE geospatial_id IN (?)", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM geospatial_trigram WHERE geospatial_id IN (?)", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM geospatial_neighbor WHERE geospatial_id IN (?) OR neighbor_id IN (?)", ids, ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM geospatial WHERE id IN (?)", ids).Error; err != nil {
+			return err
+		}
+		return addEvents(tx, events)
+	})
+}
+
+// indexNames replaces the alternate names and the name trigrams of the upserted regions,
+// Search finds its fuzzy candidates through the trigrams.
+func (r *geospatialImpl) indexNames(tx *gorm.DB, geospatials []model.Geospatial) error {
+	byGadm := make(map[string]model.Geospatial, len(geospatials))
+	var gadmIds []string
 	for _, g := range geospatials {
-		values = append(values, g.GadmID, g.ParentGadmID, g.Name, g.Type, g.Level, g.Geometry)
-		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ST_GeomFromText(?))")
+		byGadm[g.GadmID] = g
+		gadmIds = append(gadmIds, g.GadmID)
+	}
+
+	var upserted []model.Geospatial
+	if err := tx.Model(&model.Geospatial{}).Select("id, gadm_id").Where("gadm_id IN (?)", gadmIds).Find(&upserted).Error; err != nil {
+		return err
+	}
+	if len(upserted) == 0 {
+		return nil
+	}
+
+	var ids []uint
+	var nameValues, trigramValues []interface{}
+	for _, u := range upserted {
+		g := byGadm[u.GadmID]
+		ids = append(ids, u.ID)
+
+		for _, name := range g.Names {
+			nameValues = append(nameValues, u.ID, name.Name, search.Fold(name.Name), name.Language, name.Kind)
+		}
+		for _, trigram := range nameTrigrams(g) {
+			trigramValues = append(trigramValues, u.ID, trigram)
+		}
+	}
+
+	if err := tx.Exec("DELETE FROM geospatial_name WHERE geospatial_id IN (?)", ids).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM geospatial_trigram WHERE geospatial_id IN (?)", ids).Error; err != nil {
+		return err
 	}
 
-	query := fmt.Sprintf("INSERT INTO geospatial (gadm_id, parent_gadm_id, name, type, level, geometry) VALUES %s ON DUPLICATE KEY UPDATE gadm_id=VALUES(gadm_id), parent_gadm_id=VALUES(parent_gadm_id), name=VALUES(name), type=VALUES(type), level=VALUES(level), geometry=VALUES(geometry)", strings.Join(placeholders, ", "))
-	result := r.db.Exec(query, values...)
-	if result.Error != nil {
-		return result.Error
+	if err := insertChunked(tx, "INSERT INTO geospatial_name (geospatial_id, name, search_name, language, kind) VALUES ", 5, nameValues); err != nil {
+		return err
+	}
+	return insertChunked(tx, "INSERT INTO geospatial_trigram (geospatial_id, trigram) VALUES ", 2, trigramValues)
+}
+
+// insertChunked inserts values, columns per row, at most insertChunkSize rows per statement.
+func insertChunked(tx *gorm.DB, insert string, columns int, values []interface{}) error {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", columns), ", ") + ")"
+	for start := 0; start < len(values); start += columns * insertChunkSize {
+		end := start + columns*insertChunkSize
+		if end > len(values) {
+			end = len(values)
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat(row+", ", (end-start)/columns), ", ")
+		if err := tx.Exec(insert+placeholders, values[start:end]...).Error; err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+func (r *geospatialImpl) GetNames(ctx context.Context, ids []uint) ([]model.GeospatialName, error) {
+	var names []model.GeospatialName
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	if err := r.db.Where("geospatial_id IN (?)", ids).Order("geospatial_id ASC, id ASC").Find(&names).Error; err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// Search ranks the regions matching filter by how well their name or one of their alternate
+// names matches query. Prefix candidates come from the folded search_name columns, fuzzy ones
+// from the trigram table.
+func (r *geospatialImpl) Search(ctx context.Context, query string, filter model.GeospatialFilter, limit int) ([]model.Geospatial, error) {
+	folded := search.Fold(query)
+	if folded == "" {
+		return []model.Geospatial{}, nil
+	}
+	candidateLimit := searchCandidateLimit(limit)
+
+	// Folded names only hold letters, digits and spaces, nothing LIKE would interpret.
+	var prefixed []model.Geospatial
+	if err := r.FilteredDb(filter).
+		Where("search_name LIKE ?", folded+"%").
+		Order("level ASC, id ASC").
+		Limit(candidateLimit).
+		Find(&prefixed).Error; err != nil {
+		return nil, err
+	}
+
+	var aliased []model.Geospatial
+	if err := r.FilteredDb(filter).
+		Where("id IN (SELECT geospatial_id FROM geospatial_name WHERE search_name LIKE ?)", folded+"%").
+		Order("level ASC, id ASC").
+		Limit(candidateLimit).
+		Find(&aliased).Error; err != nil {
+		return nil, err
+	}
+
+	var similar []model.Geospatial
+	if err := r.FilteredDb(filter).
+		Joins("JOIN (SELECT geospatial_id, COUNT(*) AS hits FROM geospatial_trigram WHERE trigram IN (?) GROUP BY geospatial_id) matches ON matches.geospatial_id = geospatial.id", search.Trigrams(folded)).
+		Order("matches.hits DESC, geospatial.id ASC").
+		Limit(candidateLimit).
+		Find(&similar).Error; err != nil {
+		return nil, err
+	}
+
+	candidates := append(append(prefixed, aliased...), similar...)
+
+	var ids []uint
+	for _, g := range candidates {
+		ids = append(ids, g.ID)
+	}
+	names, err := r.GetNames(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint][]model.GeospatialName)
+	for _, name := range names {
+		byID[name.GeospatialID] = append(byID[name.GeospatialID], name)
+	}
+	for i := range candidates {
+		candidates[i].Names = byID[candidates[i].ID]
+	}
+
+	return rankMatches(query, candidates, limit), nil
+}
@@ -0,0 +1,705 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/export"
+	"github.com/si-bas/go-rest-geospatial/pkg/gadm"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/si-bas/go-rest-geospatial/pkg/search"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/tidwall/rtree"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+	"github.com/twpayne/go-geom/encoding/wkt"
+)
+
+type memoryRegion struct {
+	geospatial model.Geospatial
+	geometry   *geom.MultiPolygon
+	min, max   [2]float64
+}
+
+type geospatialMemoryImpl struct {
+	mu       sync.RWMutex
+	regions  map[uint]*memoryRegion
+	byGadm   map[string]*memoryRegion
+	index    rtree.RTreeG[*memoryRegion]
+	trigrams map[string]map[uint]bool
+	nextID   uint
+	// neighbors holds the border length in km of every pair of neighbours, both ways round.
+	// Regions are stale until IndexNeighbors finds their neighbours.
+	neighbors map[uint]map[uint]float64
+	stale     map[uint]bool
+	// outbox takes the events written with the regions, they are dropped without one.
+	outbox EventRepository
+}
+
+// NewGeospatialMemoryRepository loads a GeoJSON feature collection or a GeoPackage into
+// memory. Point and bbox filters are answered from an R-tree, nothing is persisted. The events
+// of later upserts are written to outbox, which may be nil.
+func NewGeospatialMemoryRepository(path string, outbox EventRepository) (GeospatialRepository, error) {
+	r := &geospatialMemoryImpl{
+		regions:   make(map[uint]*memoryRegion),
+		byGadm:    make(map[string]*memoryRegion),
+		trigrams:  make(map[string]map[uint]bool),
+		nextID:    1,
+		neighbors: make(map[uint]map[uint]float64),
+		stale:     make(map[uint]bool),
+		outbox:    outbox,
+	}
+
+	if path == "" {
+		return r, nil
+	}
+
+	features, err := readFeatures(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var geospatials []model.Geospatial
+	for _, f := range features {
+		g, err := gadm.ParseFeature(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse feature of %s: %w", path, err)
+		}
+		geospatials = append(geospatials, g)
+	}
+
+	if err := r.UpsertBulk(context.Background(), geospatials); err != nil {
+		return nil, err
+	}
+	if err := r.IndexNeighbors(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func readFeatures(path string) ([]*geojson.Feature, error) {
+	if strings.EqualFold(filepath.Ext(path), ".gpkg") {
+		return export.ReadGeoPackage(context.Background(), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fc geojson.FeatureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, err
+	}
+
+	return fc.Features, nil
+}
+
+// filter returns the regions matching filter, unordered.
+func (r *geospatialMemoryImpl) filter(filter model.GeospatialFilter) []*memoryRegion {
+	point := filter.Point()
+
+	var candidates []*memoryRegion
+	switch {
+	case point != nil:
+		at := [2]float64{point.Lng, point.Lat}
+		r.index.Search(at, at, func(min, max [2]float64, region *memoryRegion) bool {
+			if geo.ContainsPoint(region.geometry, point.Lng, point.Lat) {
+				candidates = append(candidates, region)
+			}
+			return true
+		})
+	case filter.Intersects != "":
+		t, err := wkt.Unmarshal(filter.Intersects)
+		if err != nil || t.Bounds().IsEmpty() {
+			return nil
+		}
+		b := t.Bounds()
+		r.index.Search([2]float64{b.Min(0), b.Min(1)}, [2]float64{b.Max(0), b.Max(1)}, func(min, max [2]float64, region *memoryRegion) bool {
+			if geo.Intersects(region.geometry, t) {
+				candidates = append(candidates, region)
+			}
+			return true
+		})
+	case len(filter.Bbox) == 4:
+		r.index.Search([2]float64{filter.Bbox[0], filter.Bbox[1]}, [2]float64{filter.Bbox[2], filter.Bbox[3]}, func(min, max [2]float64, region *memoryRegion) bool {
+			candidates = append(candidates, region)
+			return true
+		})
+	default:
+		for _, region := range r.regions {
+			candidates = append(candidates, region)
+		}
+	}
+
+	// A point query still honours the intersects filter when both are given.
+	if point != nil && filter.Intersects != "" {
+		t, err := wkt.Unmarshal(filter.Intersects)
+		if err != nil {
+			return nil
+		}
+		var intersecting []*memoryRegion
+		for _, region := range candidates {
+			if geo.Intersects(region.geometry, t) {
+				intersecting = append(intersecting, region)
+			}
+		}
+		candidates = intersecting
+	}
+
+	// A point or intersects query still honours the bbox filter when both are given.
+	if (point != nil || filter.Intersects != "") && len(filter.Bbox) == 4 {
+		var inBbox []*memoryRegion
+		for _, region := range candidates {
+			if region.min[0] <= filter.Bbox[2] && region.max[0] >= filter.Bbox[0] &&
+				region.min[1] <= filter.Bbox[3] && region.max[1] >= filter.Bbox[1] {
+				inBbox = append(inBbox, region)
+			}
+		}
+		candidates = inBbox
+	}
+
+	parentGadmIds := make(map[string]bool)
+	for _, id := range filter.ParentIds {
+		if parent, ok := r.regions[id]; ok {
+			parentGadmIds[parent.geospatial.GadmID] = true
+		}
+	}
+
+	var descendants map[string]bool
+	if filter.AncestorID != 0 {
+		descendants = r.descendants(filter.AncestorID)
+	}
+
+	name := strings.ToLower(filter.Name)
+
+	var regions []*memoryRegion
+	for _, region := range candidates {
+		g := region.geospatial
+
+		if len(filter.IDs) > 0 && !containsUint(filter.IDs, g.ID) {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(g.Name), name) {
+			continue
+		}
+		if len(filter.Levels) > 0 && !containsUint(filter.Levels, g.Level) {
+			continue
+		}
+		if len(filter.Types) > 0 && !containsString(filter.Types, g.Type) && !containsString(filter.Types, g.EngType) {
+			continue
+		}
+		if len(filter.ExcludedIds) > 0 && containsUint(filter.ExcludedIds, g.ID) {
+			continue
+		}
+		if len(filter.ParentIds) > 0 && !parentGadmIds[g.ParentGadmID] {
+			continue
+		}
+		if filter.AncestorID != 0 && !descendants[g.GadmID] {
+			continue
+		}
+		if filter.Country != "" && !strings.HasPrefix(g.GadmID, filter.Country) {
+			continue
+		}
+		if filter.MinAreaKm2 > 0 && (g.AreaKm2 == nil || *g.AreaKm2 < filter.MinAreaKm2) {
+			continue
+		}
+		if filter.MaxAreaKm2 > 0 && (g.AreaKm2 == nil || *g.AreaKm2 > filter.MaxAreaKm2) {
+			continue
+		}
+
+		regions = append(regions, region)
+	}
+
+	return regions
+}
+
+// descendants returns the GADM ids of every region below the region with the given id.
+func (r *geospatialMemoryImpl) descendants(id uint) map[string]bool {
+	ancestor, ok := r.regions[id]
+	if !ok {
+		return nil
+	}
+
+	children := make(map[string][]string)
+	for _, region := range r.regions {
+		children[region.geospatial.ParentGadmID] = append(children[region.geospatial.ParentGadmID], region.geospatial.GadmID)
+	}
+
+	descendants := make(map[string]bool)
+	queue := children[ancestor.geospatial.GadmID]
+	for len(queue) > 0 {
+		gadmID := queue[0]
+		queue = queue[1:]
+		if descendants[gadmID] {
+			continue
+		}
+		descendants[gadmID] = true
+		queue = append(queue, children[gadmID]...)
+	}
+	return descendants
+}
+
+func (r *geospatialMemoryImpl) Get(ctx context.Context, filter model.GeospatialFilter) ([]model.Geospatial, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	regions := r.filter(filter)
+	sort.Slice(regions, func(i, j int) bool {
+		if regions[i].geospatial.Level != regions[j].geospatial.Level {
+			return regions[i].geospatial.Level < regions[j].geospatial.Level
+		}
+		return regions[i].geospatial.ID < regions[j].geospatial.ID
+	})
+
+	return readGeospatials(regions, filter), nil
+}
+
+func (r *geospatialMemoryImpl) GetWithGeometry(ctx context.Context, filter model.GeospatialFilter) ([]model.Geospatial, error) {
+	filter.WithGeometry = true
+	return r.Get(ctx, filter)
+}
+
+func (r *geospatialMemoryImpl) GetByGadmIds(ctx context.Context, gadmIds []string) ([]model.Geospatial, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	geospatials := make([]model.Geospatial, 0, len(gadmIds))
+	for _, gadmID := range gadmIds {
+		if region, ok := r.byGadm[gadmID]; ok {
+			geospatials = append(geospatials, region.geospatial)
+		}
+	}
+
+	return geospatials, nil
+}
+
+func (r *geospatialMemoryImpl) GetNames(ctx context.Context, ids []uint) ([]model.GeospatialName, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []model.GeospatialName
+	for _, id := range ids {
+		if region, ok := r.regions[id]; ok {
+			names = append(names, region.geospatial.Names...)
+		}
+	}
+
+	return names, nil
+}
+
+func (r *geospatialMemoryImpl) GetPaginate(ctx context.Context, filter model.GeospatialFilter, param pagination.Param) ([]model.Geospatial, *pagination.Param, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if param.UseCursor {
+		return r.getCursorPage(filter, param)
+	}
+
+	columns, err := keysetColumns(param.Sort, filter, func(field string) string { return field })
+	if err != nil {
+		return nil, nil, err
+	}
+
+	geospatials, _ := sortedGeospatials(r.filter(filter), filter, columns)
+
+	if !param.SkipTotal {
+		param.SetTotal(int64(len(geospatials)))
+	}
+
+	start := int(param.GetOffset())
+	if start > len(geospatials) {
+		start = len(geospatials)
+	}
+	end := start + int(param.GetLimit())
+	if end > len(geospatials) {
+		end = len(geospatials)
+	}
+
+	return geospatials[start:end], &param, nil
+}
+
+// getCursorPage returns the page after param.Cursor, ordered like the SQL repository orders
+// it.
+func (r *geospatialMemoryImpl) getCursorPage(filter model.GeospatialFilter, param pagination.Param) ([]model.Geospatial, *pagination.Param, error) {
+	columns, err := keysetColumns(param.Sort, filter, func(field string) string { return field })
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := sortFields(param.Sort)
+	values, err := decodeCursor(param.Cursor, columns, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	geospatials, compare := sortedGeospatials(r.filter(filter), filter, columns)
+	if !param.SkipTotal {
+		param.SetTotal(int64(len(geospatials)))
+	}
+
+	start := 0
+	if values != nil {
+		start = sort.Search(len(geospatials), func(i int) bool {
+			return compare(&geospatials[i], values) > 0
+		})
+	}
+	end := start + int(param.GetLimit())
+	if end < len(geospatials) {
+		param.NextCursor = pagination.EncodeCursor(columns, cursorValues(&geospatials[end-1], fields))
+	} else {
+		end = len(geospatials)
+	}
+
+	return geospatials[start:end], &param, nil
+}
+
+// sortedGeospatials returns copies of regions with their distance from filter.Near, ordered
+// by columns, and the comparison of a region with the sort values of a position.
+func sortedGeospatials(regions []*memoryRegion, filter model.GeospatialFilter, columns []pagination.KeysetColumn) ([]model.Geospatial, func(g *model.Geospatial, values []interface{}) int) {
+	geospatials := readGeospatials(regions, filter)
+	if filter.Near != nil {
+		for i := range geospatials {
+			g := &geospatials[i]
+			if g.LabelLat != nil && g.LabelLng != nil {
+				distance := geo.Haversine(filter.Near.Lng, filter.Near.Lat, *g.LabelLng, *g.LabelLat) / 1000
+				g.DistanceKm = &distance
+			}
+		}
+	}
+
+	fields := make([]string, len(columns))
+	for i, c := range columns {
+		fields[i] = c.Column
+	}
+	compare := func(g *model.Geospatial, values []interface{}) int {
+		for i, c := range columns {
+			cmp := compareSortValues(geospatialSortColumns[fields[i]].value(g), values[i])
+			if c.Desc {
+				cmp = -cmp
+			}
+			if cmp != 0 {
+				return cmp
+			}
+		}
+		return 0
+	}
+	sort.Slice(geospatials, func(i, j int) bool {
+		return compare(&geospatials[i], cursorValues(&geospatials[j], fields)) < 0
+	})
+	return geospatials, compare
+}
+
+func (r *geospatialMemoryImpl) GetTypes(ctx context.Context, filter model.GeospatialFilter) ([]model.GeospatialTypeCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byKey := make(map[model.GeospatialTypeCount]int64)
+	for _, region := range r.filter(filter) {
+		g := region.geospatial
+		byKey[model.GeospatialTypeCount{Country: g.CountryCode(), EngType: g.EngType, Type: g.Type, Level: g.Level}]++
+	}
+
+	counts := make([]model.GeospatialTypeCount, 0, len(byKey))
+	for key, count := range byKey {
+		key.Count = count
+		counts = append(counts, key)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Level != counts[j].Level {
+			return counts[i].Level < counts[j].Level
+		}
+		if counts[i].EngType != counts[j].EngType {
+			return counts[i].EngType < counts[j].EngType
+		}
+		if counts[i].Type != counts[j].Type {
+			return counts[i].Type < counts[j].Type
+		}
+		return counts[i].Country < counts[j].Country
+	})
+
+	return counts, nil
+}
+
+func (r *geospatialMemoryImpl) GetExamples(ctx context.Context, filter model.GeospatialFilter, limit int) ([]model.Geospatial, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	regions := r.filter(filter)
+	sort.Slice(regions, func(i, j int) bool {
+		a, b := regions[i].geospatial, regions[j].geospatial
+		if (a.AreaKm2 == nil) != (b.AreaKm2 == nil) {
+			return b.AreaKm2 == nil
+		}
+		if a.AreaKm2 != nil && *a.AreaKm2 != *b.AreaKm2 {
+			return *a.AreaKm2 > *b.AreaKm2
+		}
+		return a.ID < b.ID
+	})
+	if limit > 0 && len(regions) > limit {
+		regions = regions[:limit]
+	}
+
+	return toGeospatials(regions), nil
+}
+
+func (r *geospatialMemoryImpl) Search(ctx context.Context, query string, filter model.GeospatialFilter, limit int) ([]model.Geospatial, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hits := make(map[uint]int)
+	for _, trigram := range search.Trigrams(query) {
+		for id := range r.trigrams[trigram] {
+			hits[id]++
+		}
+	}
+
+	var candidates []*memoryRegion
+	for _, region := range r.filter(filter) {
+		if hits[region.geospatial.ID] > 0 {
+			candidates = append(candidates, region)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i].geospatial.ID, candidates[j].geospatial.ID
+		if hits[a] != hits[b] {
+			return hits[a] > hits[b]
+		}
+		return a < b
+	})
+	if candidateLimit := searchCandidateLimit(limit); len(candidates) > candidateLimit {
+		candidates = candidates[:candidateLimit]
+	}
+
+	return rankMatches(query, toGeospatials(candidates), limit), nil
+}
+
+func (r *geospatialMemoryImpl) UpsertBulk(ctx context.Context, geospatials []model.Geospatial, events ...model.OutboxEvent) error {
+	regions := make([]*memoryRegion, 0, len(geospatials))
+	for _, g := range geospatials {
+		t, err := wkt.Unmarshal(g.Geometry)
+		if err != nil {
+			return fmt.Errorf("failed to decode geometry of %s: %w", g.GadmID, err)
+		}
+		mp := geo.ToMultiPolygon(t)
+		if mp == nil {
+			return fmt.Errorf("unexpected geometry type %T of %s", t, g.GadmID)
+		}
+
+		b := mp.Bounds()
+		g.SearchName = search.Fold(g.Name)
+		g.EngType = engType(g)
+		regions = append(regions, &memoryRegion{
+			geospatial: g,
+			geometry:   mp,
+			min:        [2]float64{b.Min(0), b.Min(1)},
+			max:        [2]float64{b.Max(0), b.Max(1)},
+		})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, region := range regions {
+		if existing, ok := r.byGadm[region.geospatial.GadmID]; ok {
+			r.index.Delete(existing.min, existing.max, existing)
+			r.unindexTrigrams(existing)
+			region.geospatial.ID = existing.geospatial.ID
+			region.geospatial.CreatedAt = existing.geospatial.CreatedAt
+			if existing.geospatial.Geometry != region.geospatial.Geometry {
+				r.stale[region.geospatial.ID] = true
+			}
+		} else {
+			if region.geospatial.ID == 0 || r.regions[region.geospatial.ID] != nil {
+				region.geospatial.ID = r.nextID
+			}
+			region.geospatial.CreatedAt = now
+			r.stale[region.geospatial.ID] = true
+		}
+		region.geospatial.UpdatedAt = now
+
+		names := make([]model.GeospatialName, 0, len(region.geospatial.Names))
+		for _, name := range region.geospatial.Names {
+			name.GeospatialID = region.geospatial.ID
+			name.SearchName = search.Fold(name.Name)
+			names = append(names, name)
+		}
+		region.geospatial.Names = names
+
+		if region.geospatial.ID >= r.nextID {
+			r.nextID = region.geospatial.ID + 1
+		}
+
+		r.regions[region.geospatial.ID] = region
+		r.byGadm[region.geospatial.GadmID] = region
+		r.index.Insert(region.min, region.max, region)
+		r.indexTrigrams(region)
+	}
+
+	if r.outbox == nil || len(events) == 0 {
+		return nil
+	}
+	return r.outbox.AddEvents(ctx, events)
+}
+
+func (r *geospatialMemoryImpl) Delete(ctx context.Context, ids []uint, events ...model.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		region, ok := r.regions[id]
+		if !ok {
+			continue
+		}
+		r.index.Delete(region.min, region.max, region)
+		r.unindexTrigrams(region)
+		for neighborID := range r.neighbors[id] {
+			delete(r.neighbors[neighborID], id)
+		}
+		delete(r.neighbors, id)
+		delete(r.stale, id)
+		delete(r.byGadm, region.geospatial.GadmID)
+		delete(r.regions, id)
+	}
+
+	if r.outbox == nil || len(events) == 0 {
+		return nil
+	}
+	return r.outbox.AddEvents(ctx, events)
+}
+
+// GetNeighbors returns the neighbours of the region with the given id, longest border first.
+func (r *geospatialMemoryImpl) GetNeighbors(ctx context.Context, id uint) ([]model.GeospatialNeighbor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	neighbors := make([]model.GeospatialNeighbor, 0, len(r.neighbors[id]))
+	for neighborID, borderKm := range r.neighbors[id] {
+		neighbors = append(neighbors, model.GeospatialNeighbor{GeospatialID: id, NeighborID: neighborID, BorderKm: borderKm})
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		if neighbors[i].BorderKm != neighbors[j].BorderKm {
+			return neighbors[i].BorderKm > neighbors[j].BorderKm
+		}
+		return neighbors[i].NeighborID < neighbors[j].NeighborID
+	})
+
+	return neighbors, nil
+}
+
+// IndexNeighbors replaces the neighbours of the regions that are new or whose geometry
+// changed, searching the regions of their level and kind whose bounds touch theirs.
+func (r *geospatialMemoryImpl) IndexNeighbors(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id := range r.stale {
+		for neighborID := range r.neighbors[id] {
+			delete(r.neighbors[neighborID], id)
+		}
+		delete(r.neighbors, id)
+	}
+
+	for id := range r.stale {
+		region := r.regions[id]
+		if region == nil {
+			continue
+		}
+		r.index.Search(region.min, region.max, func(min, max [2]float64, other *memoryRegion) bool {
+			otherID := other.geospatial.ID
+			if otherID == id || other.geospatial.Level != region.geospatial.Level || !sameKind(region.geospatial, other.geospatial) {
+				return true
+			}
+			if _, ok := r.neighbors[id][otherID]; ok {
+				return true
+			}
+			if border, touches := geo.SharedBorder(region.geometry, other.geometry); touches {
+				r.addNeighbor(id, otherID, border/1000)
+				r.addNeighbor(otherID, id, border/1000)
+			}
+			return true
+		})
+	}
+
+	r.stale = make(map[uint]bool)
+	return nil
+}
+
+func (r *geospatialMemoryImpl) addNeighbor(id, neighborID uint, borderKm float64) {
+	if r.neighbors[id] == nil {
+		r.neighbors[id] = make(map[uint]float64)
+	}
+	r.neighbors[id][neighborID] = borderKm
+}
+
+func (r *geospatialMemoryImpl) indexTrigrams(region *memoryRegion) {
+	for _, trigram := range nameTrigrams(region.geospatial) {
+		if r.trigrams[trigram] == nil {
+			r.trigrams[trigram] = make(map[uint]bool)
+		}
+		r.trigrams[trigram][region.geospatial.ID] = true
+	}
+}
+
+func (r *geospatialMemoryImpl) unindexTrigrams(region *memoryRegion) {
+	for _, trigram := range nameTrigrams(region.geospatial) {
+		delete(r.trigrams[trigram], region.geospatial.ID)
+	}
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// readGeospatials returns copies of regions like the SQL repository reads them for filter,
+// with the geometry only when asked for. Every other field is kept, they are all in memory.
+func readGeospatials(regions []*memoryRegion, filter model.GeospatialFilter) []model.Geospatial {
+	geospatials := toGeospatials(regions)
+	if !filter.WithGeometry {
+		for i := range geospatials {
+			geospatials[i].Geometry = ""
+		}
+	}
+	return geospatials
+}
+
+func toGeospatials(regions []*memoryRegion) []model.Geospatial {
+	geospatials := make([]model.Geospatial, 0, len(regions))
+	for _, region := range regions {
+		geospatials = append(geospatials, region.geospatial)
+	}
+	return geospatials
+}
+
+func containsUint(values []uint, v uint) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type LayerRepository interface {
+	GetLayers(context.Context, []string) ([]model.Layer, error)
+	UpsertLayer(context.Context, string) (*model.Layer, error)
+	DeleteLayer(context.Context, uint) error
+	GetFeaturesPaginate(context.Context, model.LayerFeatureFilter, pagination.Param) ([]model.LayerFeature, *pagination.Param, error)
+	UpsertFeatures(context.Context, []model.LayerFeature) error
+}
+
+type layerImpl struct {
+	db      *gorm.DB
+	dialect sqlDialect
+}
+
+// NewLayerRepository returns the repository of the layers stored next to the regions in the
+// database db is connected to.
+func NewLayerRepository(db *gorm.DB) LayerRepository {
+	return &layerImpl{
+		db:      db,
+		dialect: dialectOf(db.Dialector.Name()).on("layer_feature"),
+	}
+}
+
+// layerFeatureColumns are the columns of a feature read by default, all but the geometry.
+var layerFeatureColumns = []string{
+	"id", "layer_id", "feature_id", "name", "properties", "area_km2",
+	"bbox_min_lng", "bbox_min_lat", "bbox_max_lng", "bbox_max_lat", "created_at", "updated_at",
+}
+
+// layerFeatureUpsertColumns are the columns written by UpsertFeatures besides the key.
+var layerFeatureUpsertColumns = []string{
+	"name", "properties", "geometry", "area_km2", "bbox_min_lng", "bbox_min_lat", "bbox_max_lng", "bbox_max_lat",
+}
+
+// GetLayers returns the layers with the given names, or every layer when there are none,
+// with the number of features of each.
+func (r *layerImpl) GetLayers(ctx context.Context, names []string) ([]model.Layer, error) {
+	chain := r.db.Model(&model.Layer{}).
+		Select("id, name, created_at, updated_at, (SELECT COUNT(*) FROM layer_feature WHERE layer_feature.layer_id = layer.id) AS feature_count")
+	if len(names) > 0 {
+		chain.Where("name IN (?)", names)
+	}
+
+	var layers []model.Layer
+	if err := chain.Order("name ASC").Find(&layers).Error; err != nil {
+		return nil, err
+	}
+
+	return layers, nil
+}
+
+// UpsertLayer returns the layer with the given name, created when there is none.
+func (r *layerImpl) UpsertLayer(ctx context.Context, name string) (*model.Layer, error) {
+	db := r.db.Clauses(dbresolver.Write)
+	query := "INSERT INTO layer (name) VALUES (?) " + r.dialect.upsert("name", nil)
+	if err := db.Exec(query, name).Error; err != nil {
+		return nil, err
+	}
+
+	var layer model.Layer
+	if err := db.Model(&model.Layer{}).Select("id, name, created_at, updated_at").Where("name = ?", name).Take(&layer).Error; err != nil {
+		return nil, err
+	}
+
+	return &layer, nil
+}
+
+// DeleteLayer deletes the layer with the given id and its features.
+func (r *layerImpl) DeleteLayer(ctx context.Context, id uint) error {
+	return r.db.Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM layer_feature WHERE layer_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM layer WHERE id = ?", id).Error
+	})
+}
+
+func (r *layerImpl) filteredDb(filter model.LayerFeatureFilter) *gorm.DB {
+	selects := strings.Join(layerFeatureColumns, ", ")
+	if filter.WithGeometry {
+		selects += ", ST_AsText(geometry) AS geometry"
+	}
+
+	chain := r.db.Model(&model.LayerFeature{}).Select(selects).Where("layer_id = ?", filter.LayerID)
+
+	whereSpatial(chain, r.dialect, filter.Point, filter.Bbox, filter.Intersects)
+
+	return chain
+}
+
+// GetFeaturesPaginate returns a page of the features of a layer matching filter, in the order
+// they were imported.
+func (r *layerImpl) GetFeaturesPaginate(ctx context.Context, filter model.LayerFeatureFilter, param pagination.Param) ([]model.LayerFeature, *pagination.Param, error) {
+	var features []model.LayerFeature
+	if err := r.filteredDb(filter).
+		Scopes(pagination.Paginate(model.LayerFeature{}, &param, r.filteredDb(filter))).
+		Order("id ASC").
+		Find(&features).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return features, &param, nil
+}
+
+// UpsertFeatures writes features, replacing those with the same layer and feature id.
+// Features without a feature id are always added.
+func (r *layerImpl) UpsertFeatures(ctx context.Context, features []model.LayerFeature) error {
+	if len(features) == 0 {
+		return nil
+	}
+
+	var values []interface{}
+	var placeholders []string
+	for _, f := range features {
+		properties := f.Properties
+		if properties == "" {
+			properties = "{}"
+		}
+		values = append(values, f.LayerID, f.FeatureID, f.Name, properties, f.Geometry, f.AreaKm2,
+			f.BboxMinLng, f.BboxMinLat, f.BboxMaxLng, f.BboxMaxLat)
+		placeholders = append(placeholders, fmt.Sprintf("(?, ?, ?, ?, %s, ?, ?, ?, ?, ?)", r.dialect.geomFromText))
+	}
+
+	query := fmt.Sprintf("INSERT INTO layer_feature (layer_id, feature_id, %s) VALUES %s %s",
+		strings.Join(layerFeatureUpsertColumns, ", "), strings.Join(placeholders, ", "),
+		r.dialect.upsert("layer_id, feature_id", layerFeatureUpsertColumns))
+	return r.db.Clauses(dbresolver.Write).Exec(query, values...).Error
+}
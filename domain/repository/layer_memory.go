@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/tidwall/rtree"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/wkt"
+)
+
+type memoryFeature struct {
+	feature  model.LayerFeature
+	geometry *geom.MultiPolygon
+	min, max [2]float64
+}
+
+type layerMemoryImpl struct {
+	mu            sync.RWMutex
+	layers        map[uint]*model.Layer
+	features      map[uint]*memoryFeature
+	index         rtree.RTreeG[*memoryFeature]
+	nextLayerID   uint
+	nextFeatureID uint
+}
+
+// NewLayerMemoryRepository returns an empty repository of layers kept in memory, for the
+// memory storage backend. Nothing is persisted, layers are lost on restart.
+func NewLayerMemoryRepository() LayerRepository {
+	return &layerMemoryImpl{
+		layers:        make(map[uint]*model.Layer),
+		features:      make(map[uint]*memoryFeature),
+		nextLayerID:   1,
+		nextFeatureID: 1,
+	}
+}
+
+func (r *layerMemoryImpl) GetLayers(ctx context.Context, names []string) ([]model.Layer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[uint]int64)
+	for _, f := range r.features {
+		counts[f.feature.LayerID]++
+	}
+
+	var layers []model.Layer
+	for _, layer := range r.layers {
+		if len(names) > 0 && !containsString(names, layer.Name) {
+			continue
+		}
+		l := *layer
+		l.FeatureCount = counts[l.ID]
+		layers = append(layers, l)
+	}
+	sort.Slice(layers, func(i, j int) bool { return layers[i].Name < layers[j].Name })
+
+	return layers, nil
+}
+
+func (r *layerMemoryImpl) UpsertLayer(ctx context.Context, name string) (*model.Layer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, layer := range r.layers {
+		if layer.Name == name {
+			layer.UpdatedAt = now
+			l := *layer
+			return &l, nil
+		}
+	}
+
+	layer := &model.Layer{ID: r.nextLayerID, Name: name, CreatedAt: now, UpdatedAt: now}
+	r.nextLayerID++
+	r.layers[layer.ID] = layer
+
+	l := *layer
+	return &l, nil
+}
+
+func (r *layerMemoryImpl) DeleteLayer(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for featureID, f := range r.features {
+		if f.feature.LayerID == id {
+			r.index.Delete(f.min, f.max, f)
+			delete(r.features, featureID)
+		}
+	}
+	delete(r.layers, id)
+
+	return nil
+}
+
+// filter returns the features matching filter, unordered. Candidates are taken from the
+// R-tree by the bounds of the spatial filters, then checked against every filter.
+func (r *layerMemoryImpl) filter(filter model.LayerFeatureFilter) []*memoryFeature {
+	var t geom.T
+	if filter.Intersects != "" {
+		var err error
+		t, err = wkt.Unmarshal(filter.Intersects)
+		if err != nil || t.Bounds().IsEmpty() {
+			return nil
+		}
+	}
+
+	var candidates []*memoryFeature
+	collect := func(min, max [2]float64, f *memoryFeature) bool {
+		candidates = append(candidates, f)
+		return true
+	}
+	switch {
+	case filter.Point != nil:
+		point := [2]float64{filter.Point.Lng, filter.Point.Lat}
+		r.index.Search(point, point, collect)
+	case t != nil:
+		b := t.Bounds()
+		r.index.Search([2]float64{b.Min(0), b.Min(1)}, [2]float64{b.Max(0), b.Max(1)}, collect)
+	case len(filter.Bbox) == 4:
+		r.index.Search([2]float64{filter.Bbox[0], filter.Bbox[1]}, [2]float64{filter.Bbox[2], filter.Bbox[3]}, collect)
+	default:
+		for _, f := range r.features {
+			candidates = append(candidates, f)
+		}
+	}
+
+	var features []*memoryFeature
+	for _, f := range candidates {
+		if f.feature.LayerID != filter.LayerID {
+			continue
+		}
+		if filter.Point != nil && !geo.ContainsPoint(f.geometry, filter.Point.Lng, filter.Point.Lat) {
+			continue
+		}
+		if t != nil && !geo.Intersects(f.geometry, t) {
+			continue
+		}
+		if len(filter.Bbox) == 4 && (f.min[0] > filter.Bbox[2] || f.max[0] < filter.Bbox[0] ||
+			f.min[1] > filter.Bbox[3] || f.max[1] < filter.Bbox[1]) {
+			continue
+		}
+		features = append(features, f)
+	}
+
+	return features
+}
+
+func (r *layerMemoryImpl) GetFeaturesPaginate(ctx context.Context, filter model.LayerFeatureFilter, param pagination.Param) ([]model.LayerFeature, *pagination.Param, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := r.filter(filter)
+	sort.Slice(matches, func(i, j int) bool { return matches[i].feature.ID < matches[j].feature.ID })
+
+	if !param.SkipTotal {
+		param.SetTotal(int64(len(matches)))
+	}
+
+	start := int(param.GetOffset())
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := start + int(param.GetLimit())
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	features := make([]model.LayerFeature, 0, end-start)
+	for _, f := range matches[start:end] {
+		feature := f.feature
+		if !filter.WithGeometry {
+			feature.Geometry = ""
+		}
+		features = append(features, feature)
+	}
+
+	return features, &param, nil
+}
+
+func (r *layerMemoryImpl) UpsertFeatures(ctx context.Context, features []model.LayerFeature) error {
+	items := make([]*memoryFeature, 0, len(features))
+	for _, f := range features {
+		t, err := wkt.Unmarshal(f.Geometry)
+		if err != nil {
+			return fmt.Errorf("failed to decode geometry of feature %q: %w", f.Name, err)
+		}
+		mp := geo.ToMultiPolygon(t)
+		if mp == nil {
+			return fmt.Errorf("unexpected geometry type %T of feature %q", t, f.Name)
+		}
+
+		if f.Properties == "" {
+			f.Properties = "{}"
+		}
+		b := mp.Bounds()
+		items = append(items, &memoryFeature{
+			feature:  f,
+			geometry: mp,
+			min:      [2]float64{b.Min(0), b.Min(1)},
+			max:      [2]float64{b.Max(0), b.Max(1)},
+		})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing := make(map[uint]map[string]*memoryFeature)
+	for _, f := range r.features {
+		if f.feature.FeatureID == nil {
+			continue
+		}
+		if existing[f.feature.LayerID] == nil {
+			existing[f.feature.LayerID] = make(map[string]*memoryFeature)
+		}
+		existing[f.feature.LayerID][*f.feature.FeatureID] = f
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		var old *memoryFeature
+		if item.feature.FeatureID != nil {
+			old = existing[item.feature.LayerID][*item.feature.FeatureID]
+		}
+
+		if old != nil {
+			r.index.Delete(old.min, old.max, old)
+			item.feature.ID = old.feature.ID
+			item.feature.CreatedAt = old.feature.CreatedAt
+		} else {
+			item.feature.ID = r.nextFeatureID
+			r.nextFeatureID++
+			item.feature.CreatedAt = now
+		}
+		item.feature.UpdatedAt = now
+
+		r.features[item.feature.ID] = item
+		r.index.Insert(item.min, item.max, item)
+		if item.feature.FeatureID != nil {
+			if existing[item.feature.LayerID] == nil {
+				existing[item.feature.LayerID] = make(map[string]*memoryFeature)
+			}
+			existing[item.feature.LayerID][*item.feature.FeatureID] = item
+		}
+	}
+
+	return nil
+}
@@ -5,8 +5,6 @@ package mocks
 import (
 	context "context"
 
-	gorm "gorm.io/gorm"
-
 	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/si-bas/go-rest-geospatial/domain/model"
@@ -19,17 +17,15 @@ type GeospatialRepository struct {
 	mock.Mock
 }
 
-// FilteredDb provides a mock function with given fields: _a0
-func (_m *GeospatialRepository) FilteredDb(_a0 model.GeospatialFilter) *gorm.DB {
-	ret := _m.Called(_a0)
+// Delete provides a mock function with given fields: _a0, _a1, _a2
+func (_m *GeospatialRepository) Delete(_a0 context.Context, _a1 []uint, _a2 ...model.OutboxEvent) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
-	var r0 *gorm.DB
-	if rf, ok := ret.Get(0).(func(model.GeospatialFilter) *gorm.DB); ok {
-		r0 = rf(_a0)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint, ...model.OutboxEvent) error); ok {
+		r0 = rf(_a0, _a1, _a2...)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gorm.DB)
-		}
+		r0 = ret.Error(0)
 	}
 
 	return r0
@@ -61,25 +57,103 @@ func (_m *GeospatialRepository) Get(_a0 context.Context, _a1 model.GeospatialFil
 	return r0, r1
 }
 
-// GetLevels provides a mock function with given fields: _a0
-func (_m *GeospatialRepository) GetLevels(_a0 context.Context) ([]uint, error) {
-	ret := _m.Called(_a0)
+// GetByGadmIds provides a mock function with given fields: _a0, _a1
+func (_m *GeospatialRepository) GetByGadmIds(_a0 context.Context, _a1 []string) ([]model.Geospatial, error) {
+	ret := _m.Called(_a0, _a1)
 
-	var r0 []uint
+	var r0 []model.Geospatial
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]uint, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]model.Geospatial, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []uint); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []model.Geospatial); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Geospatial)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExamples provides a mock function with given fields: _a0, _a1, _a2
+func (_m *GeospatialRepository) GetExamples(_a0 context.Context, _a1 model.GeospatialFilter, _a2 int) ([]model.Geospatial, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []model.Geospatial
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.GeospatialFilter, int) ([]model.Geospatial, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.GeospatialFilter, int) []model.Geospatial); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Geospatial)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.GeospatialFilter, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNames provides a mock function with given fields: _a0, _a1
+func (_m *GeospatialRepository) GetNames(_a0 context.Context, _a1 []uint) ([]model.GeospatialName, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.GeospatialName
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) ([]model.GeospatialName, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) []model.GeospatialName); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]uint)
+			r0 = ret.Get(0).([]model.GeospatialName)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, []uint) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNeighbors provides a mock function with given fields: _a0, _a1
+func (_m *GeospatialRepository) GetNeighbors(_a0 context.Context, _a1 uint) ([]model.GeospatialNeighbor, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.GeospatialNeighbor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]model.GeospatialNeighbor, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []model.GeospatialNeighbor); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.GeospatialNeighbor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -122,25 +196,25 @@ func (_m *GeospatialRepository) GetPaginate(_a0 context.Context, _a1 model.Geosp
 	return r0, r1, r2
 }
 
-// GetTypes provides a mock function with given fields: _a0
-func (_m *GeospatialRepository) GetTypes(_a0 context.Context) ([]string, error) {
-	ret := _m.Called(_a0)
+// GetTypes provides a mock function with given fields: _a0, _a1
+func (_m *GeospatialRepository) GetTypes(_a0 context.Context, _a1 model.GeospatialFilter) ([]model.GeospatialTypeCount, error) {
+	ret := _m.Called(_a0, _a1)
 
-	var r0 []string
+	var r0 []model.GeospatialTypeCount
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, model.GeospatialFilter) ([]model.GeospatialTypeCount, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, model.GeospatialFilter) []model.GeospatialTypeCount); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).([]model.GeospatialTypeCount)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, model.GeospatialFilter) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -148,13 +222,79 @@ func (_m *GeospatialRepository) GetTypes(_a0 context.Context) ([]string, error)
 	return r0, r1
 }
 
-// UpsertBulk provides a mock function with given fields: _a0, _a1
-func (_m *GeospatialRepository) UpsertBulk(_a0 context.Context, _a1 []model.Geospatial) error {
+// GetWithGeometry provides a mock function with given fields: _a0, _a1
+func (_m *GeospatialRepository) GetWithGeometry(_a0 context.Context, _a1 model.GeospatialFilter) ([]model.Geospatial, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, []model.Geospatial) error); ok {
+	var r0 []model.Geospatial
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.GeospatialFilter) ([]model.Geospatial, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.GeospatialFilter) []model.Geospatial); ok {
 		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Geospatial)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.GeospatialFilter) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IndexNeighbors provides a mock function with given fields: _a0
+func (_m *GeospatialRepository) IndexNeighbors(_a0 context.Context) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *GeospatialRepository) Search(_a0 context.Context, _a1 string, _a2 model.GeospatialFilter, _a3 int) ([]model.Geospatial, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 []model.Geospatial
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.GeospatialFilter, int) ([]model.Geospatial, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.GeospatialFilter, int) []model.Geospatial); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Geospatial)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.GeospatialFilter, int) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertBulk provides a mock function with given fields: _a0, _a1, _a2
+func (_m *GeospatialRepository) UpsertBulk(_a0 context.Context, _a1 []model.Geospatial, _a2 ...model.OutboxEvent) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.Geospatial, ...model.OutboxEvent) error); ok {
+		r0 = rf(_a0, _a1, _a2...)
 	} else {
 		r0 = ret.Error(0)
 	}
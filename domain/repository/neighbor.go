@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/wkt"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// neighborBatchSize is how many stale regions IndexNeighbors indexes per transaction.
+const neighborBatchSize = 100
+
+// GetNeighbors returns the neighbours of the region with the given id, longest border first.
+func (r *geospatialImpl) GetNeighbors(ctx context.Context, id uint) ([]model.GeospatialNeighbor, error) {
+	var neighbors []model.GeospatialNeighbor
+	if err := r.db.Where("geospatial_id = ?", id).Order("border_km DESC, neighbor_id ASC").Find(&neighbors).Error; err != nil {
+		return nil, err
+	}
+
+	return neighbors, nil
+}
+
+// IndexNeighbors replaces the neighbours of every region UpsertBulk marked stale, new regions
+// and regions whose geometry changed. It runs after the concurrent chunks of an import are
+// written, so that regions of different chunks find each other.
+func (r *geospatialImpl) IndexNeighbors(ctx context.Context) error {
+	db := r.db.Clauses(dbresolver.Write)
+	for {
+		var stale []model.Geospatial
+		err := db.Model(&model.Geospatial{}).
+			Select("id, level, eng_type, ST_AsText(geometry) AS geometry").
+			Where("neighbors_stale = ?", true).
+			Order("id ASC").
+			Limit(neighborBatchSize).
+			Find(&stale).Error
+		if err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return r.indexNeighbors(tx, stale)
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// indexNeighbors finds the neighbours of stale among the regions of their level and kind
+// whose bounds touch theirs, and replaces every pair they are part of.
+func (r *geospatialImpl) indexNeighbors(tx *gorm.DB, stale []model.Geospatial) error {
+	txRepo := &geospatialImpl{db: tx, dialect: r.dialect}
+
+	ids := make([]uint, 0, len(stale))
+	borders := make(map[[2]uint]float64)
+	seen := make(map[[2]uint]bool)
+	for _, g := range stale {
+		ids = append(ids, g.ID)
+
+		mp, err := readMultiPolygon(g)
+		if err != nil {
+			return err
+		}
+
+		b := mp.Bounds()
+		var candidates []model.Geospatial
+		err = txRepo.FilteredDb(model.GeospatialFilter{
+			Levels:       []uint{g.Level},
+			ExcludedIds:  []uint{g.ID},
+			Bbox:         []float64{b.Min(0), b.Min(1), b.Max(0), b.Max(1)},
+			Columns:      []string{"id", "eng_type"},
+			WithGeometry: true,
+		}).Find(&candidates).Error
+		if err != nil {
+			return err
+		}
+
+		for _, c := range candidates {
+			pair := [2]uint{g.ID, c.ID}
+			if c.ID < g.ID {
+				pair = [2]uint{c.ID, g.ID}
+			}
+			if seen[pair] || !sameKind(g, c) {
+				continue
+			}
+			seen[pair] = true
+
+			other, err := readMultiPolygon(c)
+			if err != nil {
+				return err
+			}
+			if border, touches := geo.SharedBorder(mp, other); touches {
+				borders[pair] = border / 1000
+			}
+		}
+	}
+
+	pairs := make([][2]uint, 0, len(borders))
+	for pair := range borders {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1]
+	})
+
+	var values []interface{}
+	for _, pair := range pairs {
+		values = append(values, pair[0], pair[1], borders[pair], pair[1], pair[0], borders[pair])
+	}
+
+	if err := tx.Exec("DELETE FROM geospatial_neighbor WHERE geospatial_id IN (?) OR neighbor_id IN (?)", ids, ids).Error; err != nil {
+		return err
+	}
+	if err := insertChunked(tx, "INSERT INTO geospatial_neighbor (geospatial_id, neighbor_id, border_km) VALUES ", 3, values); err != nil {
+		return err
+	}
+	return tx.Exec("UPDATE geospatial SET neighbors_stale = ? WHERE id IN (?)", false, ids).Error
+}
+
+// sameKind reports whether a and b may be neighbours, custom areas only border each other,
+// they lie on top of the regions they were made of.
+func sameKind(a, b model.Geospatial) bool {
+	return (a.EngType == constant.EngtypeCustom) == (b.EngType == constant.EngtypeCustom)
+}
+
+// markStale flags the regions of geospatials whose geometry differs from the one stored in
+// existing for IndexNeighbors, new regions are stale by default. The geometry attributes are
+// compared, they are derived from the geometry and read without it.
+func markStale(tx *gorm.DB, geospatials, existing []model.Geospatial) error {
+	stored := make(map[string]model.Geospatial, len(existing))
+	for _, e := range existing {
+		stored[e.GadmID] = e
+	}
+
+	var changed []string
+	for _, g := range geospatials {
+		if e, ok := stored[g.GadmID]; ok && !sameGeometryAttributes(g, e) {
+			changed = append(changed, g.GadmID)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	return tx.Exec("UPDATE geospatial SET neighbors_stale = ? WHERE gadm_id IN (?)", true, changed).Error
+}
+
+// geometryAttributeColumns are the columns markStale compares.
+var geometryAttributeColumns = []string{
+	"gadm_id", "centroid_lat", "centroid_lng", "area_km2", "perimeter_km",
+	"bbox_min_lng", "bbox_min_lat", "bbox_max_lng", "bbox_max_lat",
+}
+
+func sameGeometryAttributes(a, b model.Geospatial) bool {
+	return sameFloat(a.CentroidLat, b.CentroidLat) && sameFloat(a.CentroidLng, b.CentroidLng) &&
+		sameFloat(a.AreaKm2, b.AreaKm2) && sameFloat(a.PerimeterKm, b.PerimeterKm) &&
+		sameFloat(a.BboxMinLng, b.BboxMinLng) && sameFloat(a.BboxMinLat, b.BboxMinLat) &&
+		sameFloat(a.BboxMaxLng, b.BboxMaxLng) && sameFloat(a.BboxMaxLat, b.BboxMaxLat)
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func readMultiPolygon(g model.Geospatial) (*geom.MultiPolygon, error) {
+	t, err := wkt.Unmarshal(g.Geometry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode geometry of region %d: %w", g.ID, err)
+	}
+	mp := geo.ToMultiPolygon(t)
+	if mp == nil {
+		return nil, fmt.Errorf("unexpected geometry type %T of region %d", t, g.ID)
+	}
+	return mp, nil
+}
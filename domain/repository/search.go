@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/search"
+)
+
+// searchCandidates is how many candidates per requested result are ranked, the index only
+// narrows them down roughly.
+const (
+	searchCandidates    = 10
+	minSearchCandidates = 100
+)
+
+func searchCandidateLimit(limit int) int {
+	if limit*searchCandidates < minSearchCandidates {
+		return minSearchCandidates
+	}
+	return limit * searchCandidates
+}
+
+// nameTrigrams returns the distinct trigrams of the name and the alternate names of g.
+func nameTrigrams(g model.Geospatial) []string {
+	seen := make(map[string]bool)
+	var trigrams []string
+	for _, trigram := range search.Trigrams(g.Name) {
+		seen[trigram] = true
+		trigrams = append(trigrams, trigram)
+	}
+	for _, name := range g.Names {
+		for _, trigram := range search.Trigrams(name.Name) {
+			if !seen[trigram] {
+				seen[trigram] = true
+				trigrams = append(trigrams, trigram)
+			}
+		}
+	}
+	return trigrams
+}
+
+// rankMatches scores candidates against query and returns the best limit matches, best first.
+// A region scores as well as its best matching name. Ties go to the higher level region, then
+// to the lower id.
+func rankMatches(query string, candidates []model.Geospatial, limit int) []model.Geospatial {
+	seen := make(map[uint]bool)
+	matches := make([]model.Geospatial, 0, len(candidates))
+	for _, g := range candidates {
+		if seen[g.ID] {
+			continue
+		}
+		seen[g.ID] = true
+
+		score, kind, ok := search.Score(query, g.Name)
+		for _, name := range g.Names {
+			if s, k, matched := search.Score(query, name.Name); matched && s > score {
+				score, kind, ok = s, k, true
+				g.MatchedName = name.Name
+			}
+		}
+		if !ok {
+			continue
+		}
+		g.Score = &score
+		g.Match = kind
+		matches = append(matches, g)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if *matches[i].Score != *matches[j].Score {
+			return *matches[i].Score > *matches[j].Score
+		}
+		if matches[i].Level != matches[j].Level {
+			return matches[i].Level < matches[j].Level
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+type sortKind int
+
+const (
+	sortNumber sortKind = iota
+	sortText
+	sortTime
+)
+
+// sortColumn is a field of model.GeospatialSortFields, value reads it from a region the way
+// a cursor stores it. Computed fields are not columns, the SQL repository selects them.
+type sortColumn struct {
+	kind     sortKind
+	nullable bool
+	computed bool
+	value    func(g *model.Geospatial) interface{}
+}
+
+func floatPtrValue(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+var geospatialSortColumns = map[string]sortColumn{
+	"id":           {kind: sortNumber, value: func(g *model.Geospatial) interface{} { return float64(g.ID) }},
+	"name":         {kind: sortText, value: func(g *model.Geospatial) interface{} { return g.Name }},
+	"type":         {kind: sortText, value: func(g *model.Geospatial) interface{} { return g.Type }},
+	"eng_type":     {kind: sortText, value: func(g *model.Geospatial) interface{} { return g.EngType }},
+	"level":        {kind: sortNumber, value: func(g *model.Geospatial) interface{} { return float64(g.Level) }},
+	"area_km2":     {kind: sortNumber, nullable: true, value: func(g *model.Geospatial) interface{} { return floatPtrValue(g.AreaKm2) }},
+	"perimeter_km": {kind: sortNumber, nullable: true, value: func(g *model.Geospatial) interface{} { return floatPtrValue(g.PerimeterKm) }},
+	"distance_km":  {kind: sortNumber, nullable: true, computed: true, value: func(g *model.Geospatial) interface{} { return floatPtrValue(g.DistanceKm) }},
+	"created_at":   {kind: sortTime, value: func(g *model.Geospatial) interface{} { return g.CreatedAt }},
+	"updated_at":   {kind: sortTime, value: func(g *model.Geospatial) interface{} { return g.UpdatedAt }},
+}
+
+// keysetColumns returns the columns of sorts, followed by the id as the tiebreaker that makes
+// every position unique, descending like the default order. expr returns the SQL of a
+// computed field. Sorting by distance needs filter.Near.
+func keysetColumns(sorts []pagination.ParamSort, filter model.GeospatialFilter, expr func(field string) string) ([]pagination.KeysetColumn, error) {
+	var columns []pagination.KeysetColumn
+	seen := make(map[string]bool)
+	for _, s := range sorts {
+		column, ok := geospatialSortColumns[s.Column]
+		order := strings.ToUpper(s.Order)
+		if !ok || (order != pagination.OrderAsc && order != pagination.OrderDesc) {
+			return nil, pagination.ErrInvalidSort
+		}
+		if s.Column == "distance_km" && filter.Near == nil {
+			return nil, pagination.ErrInvalidSort
+		}
+		if seen[s.Column] {
+			continue
+		}
+		seen[s.Column] = true
+
+		name := s.Column
+		if column.computed {
+			name = expr(s.Column)
+		}
+		columns = append(columns, pagination.KeysetColumn{Column: name, Desc: order == pagination.OrderDesc, Nullable: column.nullable})
+	}
+
+	if !seen["id"] {
+		columns = append(columns, pagination.KeysetColumn{Column: "id", Desc: true})
+	}
+	return columns, nil
+}
+
+// sortFields returns the fields sorts sort by, followed by the id like keysetColumns.
+func sortFields(sorts []pagination.ParamSort) []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, s := range sorts {
+		if !seen[s.Column] {
+			seen[s.Column] = true
+			fields = append(fields, s.Column)
+		}
+	}
+	if !seen["id"] {
+		fields = append(fields, "id")
+	}
+	return fields
+}
+
+// cursorValues returns the values of the fields of g, as a cursor stores them.
+func cursorValues(g *model.Geospatial, fields []string) []interface{} {
+	values := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		values = append(values, geospatialSortColumns[field].value(g))
+	}
+	return values
+}
+
+// decodeCursor returns the values of the cursor, typed like cursorValues returns them.
+func decodeCursor(encoded string, columns []pagination.KeysetColumn, fields []string) ([]interface{}, error) {
+	values, err := pagination.DecodeCursor(encoded, columns)
+	if err != nil || values == nil {
+		return nil, err
+	}
+
+	for i, field := range fields {
+		column := geospatialSortColumns[field]
+		if values[i] == nil {
+			if !column.nullable {
+				return nil, pagination.ErrInvalidCursor
+			}
+			continue
+		}
+
+		var ok bool
+		switch column.kind {
+		case sortNumber:
+			_, ok = values[i].(float64)
+		case sortText:
+			_, ok = values[i].(string)
+		case sortTime:
+			var text string
+			if text, ok = values[i].(string); ok {
+				var err error
+				values[i], err = time.Parse(time.RFC3339Nano, text)
+				ok = err == nil
+			}
+		}
+		if !ok {
+			return nil, pagination.ErrInvalidCursor
+		}
+	}
+	return values, nil
+}
+
+// compareSortValues compares values of a sort column like the memory repository orders
+// them: missing values first, text regardless of case.
+func compareSortValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch a := a.(type) {
+	case float64:
+		return compareFloat(a, b.(float64))
+	case string:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b.(string)))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/wkt"
+)
+
+// SRID is the spatial reference of every exported geometry, GADM boundaries are WGS 84.
+const SRID = 4326
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Formats lists the export formats in the order they are offered to clients.
+var Formats = []string{
+	constant.ExportFormatGeoPackage,
+	constant.ExportFormatFlatGeobuf,
+}
+
+// IsSupported reports whether format is one of Formats.
+func IsSupported(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType returns the media type used when the exported file is downloaded.
+func ContentType(format string) string {
+	switch format {
+	case constant.ExportFormatGeoPackage:
+		return "application/geopackage+sqlite3"
+	case constant.ExportFormatFlatGeobuf:
+		return "application/flatgeobuf"
+	}
+	return "application/octet-stream"
+}
+
+// Writer writes regions to a file a page at a time, so an export never holds more than a
+// page of geometries. The file is complete once Close returns nil, after a failed Write
+// Close returns the error and the file is to be removed.
+type Writer interface {
+	Write(ctx context.Context, geospatials []model.Geospatial) error
+	Close() error
+}
+
+// Create returns a Writer of format to a new file at path, replacing any existing file.
+func Create(ctx context.Context, format string, path string) (Writer, error) {
+	switch format {
+	case constant.ExportFormatGeoPackage:
+		return createGeoPackage(ctx, path)
+	case constant.ExportFormatFlatGeobuf:
+		return createFlatGeobuf(path)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+// Write writes geospatials to a new file at path, replacing any existing file.
+func Write(ctx context.Context, format string, path string, geospatials []model.Geospatial) error {
+	w, err := Create(ctx, format, path)
+	if err != nil {
+		return err
+	}
+	if err := w.Write(ctx, geospatials); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func decodeMultiPolygon(g model.Geospatial) (*geom.MultiPolygon, error) {
+	t, err := wkt.Unmarshal(g.Geometry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode geometry of %s: %w", g.GadmID, err)
+	}
+
+	mp := geo.ToMultiPolygon(t)
+	if mp == nil {
+		return nil, fmt.Errorf("unexpected geometry type %T of %s", t, g.GadmID)
+	}
+
+	return mp, nil
+}
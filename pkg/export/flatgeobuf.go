@@ -0,0 +1,476 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/twpayne/go-geom"
+)
+
+// FlatGeobuf schema values, see https://github.com/flatgeobuf/flatgeobuf/tree/master/src/fbs
+const (
+	fgbGeometryTypePolygon      = 3
+	fgbGeometryTypeMultiPolygon = 6
+
+	fgbColumnTypeUByte  = 1
+	fgbColumnTypeUInt   = 6
+	fgbColumnTypeString = 11
+
+	fgbNodeSize     = 16
+	fgbNodeItemSize = 40
+)
+
+var fgbMagicBytes = []byte{'f', 'g', 'b', 3, 'f', 'g', 'b', 0}
+
+type fgbColumn struct {
+	name     string
+	kind     byte
+	nullable bool
+}
+
+var fgbColumns = []fgbColumn{
+	{name: "region_id", kind: fgbColumnTypeUInt},
+	{name: "gadm_id", kind: fgbColumnTypeString},
+	{name: "parent_gadm_id", kind: fgbColumnTypeString, nullable: true},
+	{name: "name", kind: fgbColumnTypeString},
+	{name: "type", kind: fgbColumnTypeString},
+	{name: "level", kind: fgbColumnTypeUByte},
+	{name: "eng_type", kind: fgbColumnTypeString},
+}
+
+type fgbNode struct {
+	minX, minY, maxX, maxY float64
+	offset                 uint64
+}
+
+func (n *fgbNode) expand(o fgbNode) {
+	n.minX = math.Min(n.minX, o.minX)
+	n.minY = math.Min(n.minY, o.minY)
+	n.maxX = math.Max(n.maxX, o.maxX)
+	n.maxY = math.Max(n.maxY, o.maxY)
+}
+
+func emptyFgbNode(offset uint64) fgbNode {
+	return fgbNode{
+		minX:   math.Inf(1),
+		minY:   math.Inf(1),
+		maxX:   math.Inf(-1),
+		maxY:   math.Inf(-1),
+		offset: offset,
+	}
+}
+
+// fgbFeature is an encoded feature, held in data or, when spilled, size bytes at spilledAt
+// in the spill file.
+type fgbFeature struct {
+	node      fgbNode
+	data      []byte
+	size      int
+	spilledAt int64
+}
+
+// WriteFlatGeobuf writes geospatials as a single FlatGeobuf layer, features are
+// ordered along a Hilbert curve and preceded by a packed R-tree index.
+func WriteFlatGeobuf(w io.Writer, geospatials []model.Geospatial) error {
+	extent := emptyFgbNode(0)
+	features := make([]fgbFeature, 0, len(geospatials))
+	for _, g := range geospatials {
+		f, err := newFgbFeature(g)
+		if err != nil {
+			return err
+		}
+		extent.expand(f.node)
+		features = append(features, f)
+	}
+
+	return writeFgb(w, features, extent, func(f fgbFeature) ([]byte, error) { return f.data, nil })
+}
+
+func newFgbFeature(g model.Geospatial) (fgbFeature, error) {
+	mp, err := decodeMultiPolygon(g)
+	if err != nil {
+		return fgbFeature{}, err
+	}
+
+	b := mp.Bounds()
+	data := encodeFgbFeature(g, mp)
+	return fgbFeature{
+		node: fgbNode{minX: b.Min(0), minY: b.Min(1), maxX: b.Max(0), maxY: b.Max(1)},
+		data: data,
+		size: len(data),
+	}, nil
+}
+
+// writeFgb writes the layer of features within extent, read returns the encoded feature.
+func writeFgb(w io.Writer, features []fgbFeature, extent fgbNode, read func(fgbFeature) ([]byte, error)) error {
+	hilbertSort(features, extent)
+
+	var offset uint64
+	for i := range features {
+		features[i].node.offset = offset
+		offset += uint64(features[i].size)
+	}
+
+	if _, err := w.Write(fgbMagicBytes); err != nil {
+		return err
+	}
+	if _, err := w.Write(encodeFgbHeader(extent, len(features))); err != nil {
+		return err
+	}
+	if len(features) > 0 {
+		if err := writeFgbIndex(w, features); err != nil {
+			return err
+		}
+	}
+	for _, f := range features {
+		data, err := read(f)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flatGeobufWriter spills the encoded features to a temporary file next to path and keeps
+// only their bounds, the index needs every feature before the first can be written.
+type flatGeobufWriter struct {
+	path     string
+	spill    *os.File
+	spilled  int64
+	features []fgbFeature
+	extent   fgbNode
+	err      error
+}
+
+func createFlatGeobuf(path string) (*flatGeobufWriter, error) {
+	spill, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &flatGeobufWriter{path: path, spill: spill, extent: emptyFgbNode(0)}, nil
+}
+
+func (w *flatGeobufWriter) Write(ctx context.Context, geospatials []model.Geospatial) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	for _, g := range geospatials {
+		f, err := newFgbFeature(g)
+		if err == nil {
+			_, err = w.spill.Write(f.data)
+		}
+		if err != nil {
+			w.err = err
+			return err
+		}
+
+		f.data, f.spilledAt = nil, w.spilled
+		w.spilled += int64(f.size)
+		w.extent.expand(f.node)
+		w.features = append(w.features, f)
+	}
+
+	return nil
+}
+
+func (w *flatGeobufWriter) Close() error {
+	defer os.Remove(w.spill.Name())
+	defer w.spill.Close()
+	if w.err != nil {
+		return w.err
+	}
+
+	out, err := os.Create(w.path)
+	if err != nil {
+		return err
+	}
+
+	buffered := bufio.NewWriter(out)
+	err = writeFgb(buffered, w.features, w.extent, func(f fgbFeature) ([]byte, error) {
+		data := make([]byte, f.size)
+		_, err := w.spill.ReadAt(data, f.spilledAt)
+		return data, err
+	})
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func encodeFgbHeader(extent fgbNode, featuresCount int) []byte {
+	b := flatbuffers.NewBuilder(1024)
+
+	columns := make([]flatbuffers.UOffsetT, len(fgbColumns))
+	for i, c := range fgbColumns {
+		name := b.CreateString(c.name)
+		b.StartObject(11)
+		b.PrependUOffsetTSlot(0, name, 0)
+		b.PrependByteSlot(1, c.kind, 0)
+		b.PrependBoolSlot(7, c.nullable, true)
+		columns[i] = b.EndObject()
+	}
+	b.StartVector(4, len(columns), 4)
+	for i := len(columns) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(columns[i])
+	}
+	columnsVec := b.EndVector(len(columns))
+
+	var envelopeVec flatbuffers.UOffsetT
+	if featuresCount > 0 {
+		envelopeVec = createFloat64Vector(b, []float64{extent.minX, extent.minY, extent.maxX, extent.maxY})
+	}
+
+	org := b.CreateString("EPSG")
+	b.StartObject(6)
+	b.PrependUOffsetTSlot(0, org, 0)
+	b.PrependInt32Slot(1, SRID, 0)
+	crs := b.EndObject()
+
+	name := b.CreateString("geospatial")
+
+	indexNodeSize := uint16(fgbNodeSize)
+	if featuresCount == 0 {
+		indexNodeSize = 0
+	}
+
+	b.StartObject(14)
+	if envelopeVec != 0 {
+		b.PrependUOffsetTSlot(0, envelopeVec, 0)
+	}
+	b.PrependUOffsetTSlot(1, name, 0)
+	b.PrependByteSlot(2, fgbGeometryTypeMultiPolygon, 0)
+	b.PrependUOffsetTSlot(7, columnsVec, 0)
+	b.PrependUint64Slot(8, uint64(featuresCount), 0)
+	b.PrependUint16Slot(9, indexNodeSize, fgbNodeSize)
+	b.PrependUOffsetTSlot(10, crs, 0)
+	b.FinishSizePrefixed(b.EndObject())
+
+	return b.FinishedBytes()
+}
+
+func encodeFgbFeature(g model.Geospatial, mp *geom.MultiPolygon) []byte {
+	b := flatbuffers.NewBuilder(1024)
+
+	parts := make([]flatbuffers.UOffsetT, mp.NumPolygons())
+	for i := range parts {
+		p := mp.Polygon(i)
+
+		var endsVec flatbuffers.UOffsetT
+		if p.NumLinearRings() > 1 {
+			ends := p.Ends()
+			b.StartVector(4, len(ends), 4)
+			for j := len(ends) - 1; j >= 0; j-- {
+				b.PrependUint32(uint32(ends[j] / p.Stride()))
+			}
+			endsVec = b.EndVector(len(ends))
+		}
+		xyVec := createFloat64Vector(b, p.FlatCoords())
+
+		b.StartObject(8)
+		if endsVec != 0 {
+			b.PrependUOffsetTSlot(0, endsVec, 0)
+		}
+		b.PrependUOffsetTSlot(1, xyVec, 0)
+		b.PrependByteSlot(6, fgbGeometryTypePolygon, 0)
+		parts[i] = b.EndObject()
+	}
+	b.StartVector(4, len(parts), 4)
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(parts[i])
+	}
+	partsVec := b.EndVector(len(parts))
+
+	b.StartObject(8)
+	b.PrependByteSlot(6, fgbGeometryTypeMultiPolygon, 0)
+	b.PrependUOffsetTSlot(7, partsVec, 0)
+	geometry := b.EndObject()
+
+	properties := b.CreateByteVector(encodeFgbProperties(g))
+
+	b.StartObject(3)
+	b.PrependUOffsetTSlot(0, geometry, 0)
+	b.PrependUOffsetTSlot(1, properties, 0)
+	b.FinishSizePrefixed(b.EndObject())
+
+	return b.FinishedBytes()
+}
+
+func encodeFgbProperties(g model.Geospatial) []byte {
+	var buf bytes.Buffer
+
+	writeString := func(column uint16, s string) {
+		binary.Write(&buf, binary.LittleEndian, column)
+		binary.Write(&buf, binary.LittleEndian, uint32(len(s)))
+		buf.WriteString(s)
+	}
+
+	binary.Write(&buf, binary.LittleEndian, uint16(0))
+	binary.Write(&buf, binary.LittleEndian, uint32(g.ID))
+	writeString(1, g.GadmID)
+	if g.ParentGadmID != "" {
+		writeString(2, g.ParentGadmID)
+	}
+	writeString(3, g.Name)
+	writeString(4, g.Type)
+	binary.Write(&buf, binary.LittleEndian, uint16(5))
+	buf.WriteByte(byte(g.Level))
+	writeString(6, g.EngType)
+
+	return buf.Bytes()
+}
+
+func createFloat64Vector(b *flatbuffers.Builder, values []float64) flatbuffers.UOffsetT {
+	b.StartVector(8, len(values), 8)
+	for i := len(values) - 1; i >= 0; i-- {
+		b.PrependFloat64(values[i])
+	}
+	return b.EndVector(len(values))
+}
+
+// writeFgbIndex writes the packed Hilbert R-tree, root first and leaves last, where
+// every leaf points at the byte offset of its feature in the data section.
+func writeFgbIndex(w io.Writer, features []fgbFeature) error {
+	levelBounds := fgbLevelBounds(len(features), fgbNodeSize)
+	numNodes := levelBounds[0][1]
+	nodes := make([]fgbNode, numNodes)
+
+	leafStart := levelBounds[0][0]
+	for i, f := range features {
+		nodes[leafStart+i] = f.node
+	}
+
+	for i := 0; i < len(levelBounds)-1; i++ {
+		pos, end := levelBounds[i][0], levelBounds[i][1]
+		newPos := levelBounds[i+1][0]
+		for pos < end {
+			node := emptyFgbNode(uint64(pos))
+			for j := 0; j < fgbNodeSize && pos < end; j++ {
+				node.expand(nodes[pos])
+				pos++
+			}
+			nodes[newPos] = node
+			newPos++
+		}
+	}
+
+	buf := make([]byte, fgbNodeItemSize)
+	for _, n := range nodes {
+		binary.LittleEndian.PutUint64(buf[0:], math.Float64bits(n.minX))
+		binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(n.minY))
+		binary.LittleEndian.PutUint64(buf[16:], math.Float64bits(n.maxX))
+		binary.LittleEndian.PutUint64(buf[24:], math.Float64bits(n.maxY))
+		binary.LittleEndian.PutUint64(buf[32:], n.offset)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fgbLevelBounds returns the [start, end) node positions of every tree level, leaves first.
+func fgbLevelBounds(numItems, nodeSize int) [][2]int {
+	n := numItems
+	numNodes := n
+	levelNumNodes := []int{n}
+	for n != 1 {
+		n = (n + nodeSize - 1) / nodeSize
+		numNodes += n
+		levelNumNodes = append(levelNumNodes, n)
+	}
+
+	bounds := make([][2]int, len(levelNumNodes))
+	n = numNodes
+	for i, size := range levelNumNodes {
+		bounds[i] = [2]int{n - size, n}
+		n -= size
+	}
+
+	return bounds
+}
+
+func hilbertSort(features []fgbFeature, extent fgbNode) {
+	const hilbertMax = (1 << 16) - 1
+
+	width := extent.maxX - extent.minX
+	height := extent.maxY - extent.minY
+	key := func(n fgbNode) uint32 {
+		var x, y uint32
+		if width != 0 {
+			x = uint32(hilbertMax * ((n.minX+n.maxX)/2 - extent.minX) / width)
+		}
+		if height != 0 {
+			y = uint32(hilbertMax * ((n.minY+n.maxY)/2 - extent.minY) / height)
+		}
+		return hilbert(x, y)
+	}
+
+	sort.SliceStable(features, func(i, j int) bool {
+		return key(features[i].node) > key(features[j].node)
+	})
+}
+
+// hilbert maps a position on a 16 bit grid to its distance along the Hilbert curve.
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00FF00FF
+	i0 = (i0 | (i0 << 4)) & 0x0F0F0F0F
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00FF00FF
+	i1 = (i1 | (i1 << 4)) & 0x0F0F0F0F
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
@@ -0,0 +1,442 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+	"github.com/twpayne/go-geom/encoding/wkb"
+)
+
+const (
+	gpkgApplicationID = 0x47504B47 // "GPKG"
+	gpkgUserVersion   = 10300
+	gpkgGeometryCol   = "geom"
+	gpkgRtreeDef      = "http://www.geopackage.org/spec120/#extension_rtree"
+	wgs84Definition   = `GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563,AUTHORITY["EPSG","7030"]],AUTHORITY["EPSG","6326"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4326"]]`
+)
+
+var gpkgSchema = []string{
+	`CREATE TABLE gpkg_spatial_ref_sys (
+		srs_name TEXT NOT NULL,
+		srs_id INTEGER NOT NULL PRIMARY KEY,
+		organization TEXT NOT NULL,
+		organization_coordsys_id INTEGER NOT NULL,
+		definition TEXT NOT NULL,
+		description TEXT
+	)`,
+	`CREATE TABLE gpkg_contents (
+		table_name TEXT NOT NULL PRIMARY KEY,
+		data_type TEXT NOT NULL,
+		identifier TEXT UNIQUE,
+		description TEXT DEFAULT '',
+		last_change DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+		min_x DOUBLE,
+		min_y DOUBLE,
+		max_x DOUBLE,
+		max_y DOUBLE,
+		srs_id INTEGER,
+		CONSTRAINT fk_gc_r_srs_id FOREIGN KEY (srs_id) REFERENCES gpkg_spatial_ref_sys(srs_id)
+	)`,
+	`CREATE TABLE gpkg_geometry_columns (
+		table_name TEXT NOT NULL,
+		column_name TEXT NOT NULL,
+		geometry_type_name TEXT NOT NULL,
+		srs_id INTEGER NOT NULL,
+		z TINYINT NOT NULL,
+		m TINYINT NOT NULL,
+		CONSTRAINT pk_geom_cols PRIMARY KEY (table_name, column_name),
+		CONSTRAINT fk_gc_tn FOREIGN KEY (table_name) REFERENCES gpkg_contents(table_name),
+		CONSTRAINT fk_gc_srs FOREIGN KEY (srs_id) REFERENCES gpkg_spatial_ref_sys (srs_id)
+	)`,
+	`CREATE TABLE gpkg_extensions (
+		table_name TEXT,
+		column_name TEXT,
+		extension_name TEXT NOT NULL,
+		definition TEXT NOT NULL,
+		scope TEXT NOT NULL,
+		CONSTRAINT ge_tce UNIQUE (table_name, column_name, extension_name)
+	)`,
+}
+
+// WriteGeoPackage writes geospatials to a new GeoPackage at path. Every level gets its
+// own feature table named level_<n>, each with an R-tree spatial index.
+func WriteGeoPackage(ctx context.Context, path string, geospatials []model.Geospatial) error {
+	w, err := createGeoPackage(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := w.Write(ctx, geospatials); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// geoPackageWriter inserts the regions as they come in one transaction, the tables of the
+// levels are described in the GeoPackage metadata once every region is in.
+type geoPackageWriter struct {
+	db     *sql.DB
+	tx     *sql.Tx
+	levels map[uint]*geoPackageLevel
+	err    error
+}
+
+type geoPackageLevel struct {
+	insertFeature *sql.Stmt
+	insertIndex   *sql.Stmt
+	extent        *geom.Bounds
+}
+
+func createGeoPackage(ctx context.Context, path string) (*geoPackageWriter, error) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	w := &geoPackageWriter{db: db, levels: make(map[uint]*geoPackageLevel)}
+	if err := w.init(ctx); err != nil {
+		w.err = err
+		w.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *geoPackageWriter) init(ctx context.Context) error {
+	if _, err := w.db.ExecContext(ctx, fmt.Sprintf("PRAGMA application_id = %d", gpkgApplicationID)); err != nil {
+		return err
+	}
+	if _, err := w.db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", gpkgUserVersion)); err != nil {
+		return err
+	}
+
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	w.tx = tx
+
+	for _, stmt := range gpkgSchema {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO gpkg_spatial_ref_sys (srs_name, srs_id, organization, organization_coordsys_id, definition, description) VALUES
+		('Undefined cartesian SRS', -1, 'NONE', -1, 'undefined', 'undefined cartesian coordinate reference system'),
+		('Undefined geographic SRS', 0, 'NONE', 0, 'undefined', 'undefined geographic coordinate reference system'),
+		('WGS 84 geodetic', ?, 'EPSG', ?, ?, 'longitude/latitude coordinates in decimal degrees on the WGS 84 spheroid')`,
+		SRID, SRID, wgs84Definition)
+	return err
+}
+
+func (w *geoPackageWriter) Write(ctx context.Context, geospatials []model.Geospatial) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	for _, g := range geospatials {
+		if err := w.insert(ctx, g); err != nil {
+			w.err = err
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *geoPackageWriter) insert(ctx context.Context, g model.Geospatial) error {
+	level, ok := w.levels[g.Level]
+	if !ok {
+		var err error
+		if level, err = createGeoPackageLevel(ctx, w.tx, g.Level); err != nil {
+			return err
+		}
+		w.levels[g.Level] = level
+	}
+
+	mp, err := decodeMultiPolygon(g)
+	if err != nil {
+		return err
+	}
+
+	blob, err := geoPackageBinary(mp)
+	if err != nil {
+		return err
+	}
+
+	res, err := level.insertFeature.ExecContext(ctx, blob, g.ID, g.GadmID, g.ParentGadmID, g.Name, g.Type, g.Level, g.EngType)
+	if err != nil {
+		return err
+	}
+	fid, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	b := mp.Bounds()
+	if _, err := level.insertIndex.ExecContext(ctx, fid, b.Min(0), b.Max(0), b.Min(1), b.Max(1)); err != nil {
+		return err
+	}
+	level.extent.Extend(mp)
+
+	return nil
+}
+
+func (w *geoPackageWriter) Close() error {
+	defer w.db.Close()
+
+	for _, level := range w.levels {
+		level.insertFeature.Close()
+		level.insertIndex.Close()
+	}
+
+	if w.err == nil {
+		w.err = w.describe()
+	}
+	if w.err != nil {
+		if w.tx != nil {
+			w.tx.Rollback()
+		}
+		return w.err
+	}
+
+	return w.tx.Commit()
+}
+
+// describe adds the tables of the levels to the GeoPackage metadata, in level order.
+func (w *geoPackageWriter) describe() error {
+	var levels []uint
+	for level := range w.levels {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	for _, level := range levels {
+		if err := describeGeoPackageLevel(w.tx, level, w.levels[level].extent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func geoPackageTable(level uint) string {
+	return fmt.Sprintf("level_%d", level)
+}
+
+func createGeoPackageLevel(ctx context.Context, tx *sql.Tx, level uint) (*geoPackageLevel, error) {
+	table := geoPackageTable(level)
+	rtree := fmt.Sprintf("rtree_%s_%s", table, gpkgGeometryCol)
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE %s (
+		fid INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+		%s MULTIPOLYGON,
+		region_id INTEGER NOT NULL,
+		gadm_id TEXT NOT NULL,
+		parent_gadm_id TEXT,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		level INTEGER NOT NULL,
+		eng_type TEXT NOT NULL
+	)`, table, gpkgGeometryCol)); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE VIRTUAL TABLE %s USING rtree(id, minx, maxx, miny, maxy)", rtree)); err != nil {
+		return nil, err
+	}
+
+	insertFeature, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s, region_id, gadm_id, parent_gadm_id, name, type, level, eng_type) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", table, gpkgGeometryCol))
+	if err != nil {
+		return nil, err
+	}
+
+	insertIndex, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (id, minx, maxx, miny, maxy) VALUES (?, ?, ?, ?, ?)", rtree))
+	if err != nil {
+		insertFeature.Close()
+		return nil, err
+	}
+
+	return &geoPackageLevel{insertFeature: insertFeature, insertIndex: insertIndex, extent: geom.NewBounds(geom.XY)}, nil
+}
+
+func describeGeoPackageLevel(tx *sql.Tx, level uint, extent *geom.Bounds) error {
+	table := geoPackageTable(level)
+
+	minX, minY, maxX, maxY := extent.Min(0), extent.Min(1), extent.Max(0), extent.Max(1)
+	if extent.IsEmpty() {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	if _, err := tx.Exec("INSERT INTO gpkg_contents (table_name, data_type, identifier, description, min_x, min_y, max_x, max_y, srs_id) VALUES (?, 'features', ?, ?, ?, ?, ?, ?, ?)",
+		table, table, fmt.Sprintf("Administrative boundaries of level %d", level), minX, minY, maxX, maxY, SRID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT INTO gpkg_geometry_columns (table_name, column_name, geometry_type_name, srs_id, z, m) VALUES (?, ?, 'MULTIPOLYGON', ?, 0, 0)",
+		table, gpkgGeometryCol, SRID); err != nil {
+		return err
+	}
+
+	// The file is written once and never updated afterwards, so the triggers that keep
+	// the index in sync with the feature table are not needed.
+	if _, err := tx.Exec("INSERT INTO gpkg_extensions (table_name, column_name, extension_name, definition, scope) VALUES (?, ?, 'gpkg_rtree_index', ?, 'write-only')",
+		table, gpkgGeometryCol, gpkgRtreeDef); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// geoPackageBinary encodes g as a GeoPackage geometry blob: the "GP" header with an
+// [minx, maxx, miny, maxy] envelope followed by little endian WKB.
+func geoPackageBinary(g geom.T) ([]byte, error) {
+	var buf bytes.Buffer
+
+	// flags: little endian header, envelope indicator 1
+	buf.Write([]byte{'G', 'P', 0, 0x03})
+
+	b := g.Bounds()
+	header := []interface{}{int32(SRID), b.Min(0), b.Max(0), b.Min(1), b.Max(1)}
+	for _, v := range header {
+		if f, ok := v.(float64); ok && math.IsInf(f, 0) {
+			v = math.NaN()
+		}
+		if err := binary.Write(&buf, binary.LittleEndian, v); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := wkb.Marshal(g, wkb.NDR)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	return buf.Bytes(), nil
+}
+
+// ReadGeoPackage reads every feature table of the GeoPackage at path, the columns other
+// than the geometry become the feature properties.
+func ReadGeoPackage(ctx context.Context, path string) ([]*geojson.Feature, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "SELECT g.table_name, g.column_name FROM gpkg_geometry_columns g JOIN gpkg_contents c ON c.table_name = g.table_name WHERE c.data_type = 'features' ORDER BY g.table_name")
+	if err != nil {
+		return nil, err
+	}
+
+	var tables [][2]string
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tables = append(tables, [2]string{table, column})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var features []*geojson.Feature
+	for _, t := range tables {
+		tableFeatures, err := readGeoPackageTable(ctx, db, t[0], t[1])
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, tableFeatures...)
+	}
+
+	return features, nil
+}
+
+func readGeoPackageTable(ctx context.Context, db *sql.DB, table, geometryColumn string) ([]*geojson.Feature, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`SELECT * FROM "%s"`, strings.ReplaceAll(table, `"`, `""`)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var features []*geojson.Feature
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		feature := &geojson.Feature{Properties: make(map[string]interface{})}
+		for i, column := range columns {
+			if column != geometryColumn {
+				if b, ok := values[i].([]byte); ok {
+					values[i] = string(b)
+				}
+				feature.Properties[column] = values[i]
+				continue
+			}
+
+			blob, ok := values[i].([]byte)
+			if !ok {
+				continue
+			}
+			if feature.Geometry, err = decodeGeoPackageBinary(blob); err != nil {
+				return nil, fmt.Errorf("failed to decode geometry in %s: %w", table, err)
+			}
+		}
+
+		features = append(features, feature)
+	}
+
+	return features, rows.Err()
+}
+
+// decodeGeoPackageBinary is the inverse of geoPackageBinary and accepts any envelope kind.
+func decodeGeoPackageBinary(blob []byte) (geom.T, error) {
+	if len(blob) < 8 || blob[0] != 'G' || blob[1] != 'P' {
+		return nil, errors.New("not a GeoPackage geometry")
+	}
+
+	flags := blob[3]
+	envelopeSize := map[byte]int{0: 0, 1: 32, 2: 48, 3: 48, 4: 64}[(flags>>1)&0x07]
+	offset := 8 + envelopeSize
+	if len(blob) < offset {
+		return nil, errors.New("truncated GeoPackage geometry")
+	}
+
+	return wkb.Unmarshal(blob[offset:])
+}
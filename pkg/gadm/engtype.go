@@ -0,0 +1,46 @@
+package gadm
+
+import (
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/pkg/search"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+)
+
+// engtypeAliases maps folded ENGTYPE values that are spelled differently from the catalogue.
+var engtypeAliases = map[string]string{
+	"subdistrict": constant.EngtypeSubDistrict,
+	"waterbody":   constant.EngtypeWaterBody,
+	"town":        constant.EngtypeCity,
+	"commune":     constant.EngtypeMunicipality,
+}
+
+// NormalizeEngtype maps a free-form ENGTYPE_n value like "Sub-district" or "Special Region"
+// to one of constant.Engtypes. Qualified types fall back to their last word, so "Autonomous
+// Province" is a province, anything unrecognised is constant.EngtypeOther.
+func NormalizeEngtype(value string) string {
+	folded := search.Fold(value)
+	if folded == "" {
+		return constant.EngtypeOther
+	}
+
+	if engtype, ok := lookupEngtype(folded); ok {
+		return engtype
+	}
+
+	words := strings.Fields(folded)
+	if engtype, ok := lookupEngtype(words[len(words)-1]); ok {
+		return engtype
+	}
+	return constant.EngtypeOther
+}
+
+func lookupEngtype(folded string) (string, bool) {
+	for _, engtype := range constant.Engtypes {
+		if search.Fold(engtype) == folded {
+			return engtype, true
+		}
+	}
+	engtype, ok := engtypeAliases[folded]
+	return engtype, ok
+}
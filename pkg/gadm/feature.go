@@ -0,0 +1,163 @@
+package gadm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/twpayne/go-geom/encoding/geojson"
+	"github.com/twpayne/go-geom/encoding/wkt"
+)
+
+// MaxLevel is the deepest GID_n property GADM publishes.
+const MaxLevel = 4
+
+// ParseFeature converts a feature of a GADM export into a region. The level is the
+// deepest GID_n property present, level 0 features are countries. Features written by
+// this service's own exports, recognised by their gadm_id property, are read as is.
+func ParseFeature(f *geojson.Feature) (model.Geospatial, error) {
+	if _, ok := f.Properties["gadm_id"]; ok {
+		return parseExportedFeature(f)
+	}
+
+	level := -1
+	for i := 0; i <= MaxLevel; i++ {
+		if f.Properties[fmt.Sprintf("GID_%d", i)] == nil {
+			break
+		}
+
+		level = i
+	}
+	if level < 0 {
+		return model.Geospatial{}, fmt.Errorf("feature has no GID_0 property")
+	}
+
+	wktEncoder := wkt.NewEncoder()
+	mpStr, err := wktEncoder.Encode(f.Geometry)
+	if err != nil {
+		return model.Geospatial{}, err
+	}
+
+	geospatial := model.Geospatial{
+		GadmID:   property(f, "GID_%d", level),
+		Name:     property(f, "COUNTRY"),
+		Type:     "Country",
+		EngType:  constant.EngtypeCountry,
+		Level:    uint(level + 1),
+		Geometry: mpStr,
+	}
+
+	if level > 0 {
+		geospatial.ParentGadmID = property(f, "GID_%d", level-1)
+		geospatial.Name = property(f, "NAME_%d", level)
+		geospatial.Type = property(f, "TYPE_%d", level)
+		geospatial.EngType = NormalizeEngtype(property(f, "ENGTYPE_%d", level))
+
+		for _, name := range listProperty(f, "NL_NAME_%d", level) {
+			geospatial.Names = append(geospatial.Names, model.GeospatialName{Name: name, Language: constant.NameLanguageLocal, Kind: constant.NameKindOfficial})
+		}
+		for _, name := range listProperty(f, "VARNAME_%d", level) {
+			geospatial.Names = append(geospatial.Names, model.GeospatialName{Name: name, Kind: constant.NameKindVariant})
+		}
+	}
+	geospatial.Names = append(geospatial.Names, namesProperty(f)...)
+
+	if mp := geo.ToMultiPolygon(f.Geometry); mp != nil && mp.NumPolygons() > 0 {
+		SetGeometryAttributes(&geospatial, geo.Measure(mp))
+	}
+
+	return geospatial, nil
+}
+
+func parseExportedFeature(f *geojson.Feature) (model.Geospatial, error) {
+	mpStr, err := wkt.NewEncoder().Encode(f.Geometry)
+	if err != nil {
+		return model.Geospatial{}, err
+	}
+
+	geospatial := model.Geospatial{
+		ID:           uint(integerProperty(f, "region_id")),
+		GadmID:       property(f, "gadm_id"),
+		ParentGadmID: property(f, "parent_gadm_id"),
+		Name:         property(f, "name"),
+		Type:         property(f, "type"),
+		EngType:      NormalizeEngtype(property(f, "eng_type")),
+		Level:        uint(integerProperty(f, "level")),
+		Geometry:     mpStr,
+		Names:        namesProperty(f),
+	}
+
+	if mp := geo.ToMultiPolygon(f.Geometry); mp != nil && mp.NumPolygons() > 0 {
+		SetGeometryAttributes(&geospatial, geo.Measure(mp))
+	}
+
+	return geospatial, nil
+}
+
+// SetGeometryAttributes copies the measured attributes onto g.
+func SetGeometryAttributes(g *model.Geospatial, attrs geo.Attributes) {
+	g.CentroidLng, g.CentroidLat = &attrs.Centroid[0], &attrs.Centroid[1]
+	g.LabelLng, g.LabelLat = &attrs.LabelPoint[0], &attrs.LabelPoint[1]
+	g.AreaKm2 = &attrs.AreaKm2
+	g.PerimeterKm = &attrs.PerimeterKm
+
+	minLng, minLat := attrs.Bounds.Min(0), attrs.Bounds.Min(1)
+	maxLng, maxLat := attrs.Bounds.Max(0), attrs.Bounds.Max(1)
+	g.BboxMinLng, g.BboxMinLat = &minLng, &minLat
+	g.BboxMaxLng, g.BboxMaxLat = &maxLng, &maxLat
+}
+
+func property(f *geojson.Feature, format string, a ...interface{}) string {
+	value, _ := f.Properties[fmt.Sprintf(format, a...)].(string)
+	return value
+}
+
+// listProperty splits a GADM list like "Jogjakarta|Yogya", GADM writes "NA" for no value.
+func listProperty(f *geojson.Feature, format string, a ...interface{}) []string {
+	var values []string
+	for _, value := range strings.Split(property(f, format, a...), "|") {
+		value = strings.TrimSpace(value)
+		if value != "" && value != "NA" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// namesProperty reads alternate names given as a "names" property, a list of objects with
+// name, language and kind, which is how datasets other than GADM can add historical names.
+func namesProperty(f *geojson.Feature) []model.GeospatialName {
+	list, _ := f.Properties["names"].([]interface{})
+
+	var names []model.GeospatialName
+	for _, item := range list {
+		object, _ := item.(map[string]interface{})
+		name, _ := object["name"].(string)
+		language, _ := object["language"].(string)
+		kind, _ := object["kind"].(string)
+		if name == "" {
+			continue
+		}
+
+		switch kind {
+		case constant.NameKindOfficial, constant.NameKindVariant, constant.NameKindHistorical:
+		default:
+			kind = constant.NameKindVariant
+		}
+
+		names = append(names, model.GeospatialName{Name: name, Language: language, Kind: kind})
+	}
+	return names
+}
+
+func integerProperty(f *geojson.Feature, key string) int64 {
+	switch v := f.Properties[key].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
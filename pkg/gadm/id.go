@@ -0,0 +1,15 @@
+package gadm
+
+import "strings"
+
+// CustomIDPrefix starts the GADM id of the areas clients save, which GADM never uses.
+const CustomIDPrefix = "CUSTOM."
+
+// Stem returns the GADM id without its version suffix, "IDN.7.1_1" is "IDN.7.1". The stem
+// of a region starts with the stems of the regions above it, followed by a dot.
+func Stem(id string) string {
+	if i := strings.IndexByte(id, '_'); i >= 0 {
+		return id[:i]
+	}
+	return id
+}
@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"github.com/twpayne/go-geom"
+)
+
+// Dissolve returns the union of polygons that meet along shared borders, like the regions of
+// a level of GADM. Every outer ring is walked counterclockwise and every hole clockwise, so
+// a border two polygons share is walked once each way; those edges are dropped and what is
+// left is stitched into the rings of the union. Polygons that overlap rather than meet are
+// not merged, the borders of each stay in the result.
+func Dissolve(mps []*geom.MultiPolygon) *geom.MultiPolygon {
+	var edges []segment
+	seen := make(map[segment]bool)
+	for _, mp := range mps {
+		for _, s := range polygonSegments(orientRings(mp, identity)) {
+			// A polygon given twice is only counted once.
+			if !seen[s] {
+				seen[s] = true
+				edges = append(edges, s)
+			}
+		}
+	}
+
+	var boundary []segment
+	outgoing := make(map[[2]float64][]int)
+	for _, s := range edges {
+		if seen[segment{s.b, s.a}] {
+			continue
+		}
+		outgoing[s.a] = append(outgoing[s.a], len(boundary))
+		boundary = append(boundary, s)
+	}
+
+	var outers, holes [][]geom.Coord
+	used := make([]bool, len(boundary))
+	for i := range boundary {
+		if used[i] {
+			continue
+		}
+		if ring := walkRing(boundary, outgoing, used, i); len(ring) >= 4 {
+			if signedArea(ring) > 0 {
+				outers = append(outers, ring)
+			} else {
+				holes = append(holes, ring)
+			}
+		}
+	}
+
+	return assembleRings(outers, holes)
+}
+
+// walkRing follows the unused edges from boundary[start] until it is back where it started,
+// and returns the closed ring, or nil when the edges do not close.
+func walkRing(boundary []segment, outgoing map[[2]float64][]int, used []bool, start int) []geom.Coord {
+	first := boundary[start].a
+	ring := []geom.Coord{{first[0], first[1]}}
+	for i := start; ; {
+		used[i] = true
+		end := boundary[i].b
+		ring = append(ring, geom.Coord{end[0], end[1]})
+		if end == first {
+			return ring
+		}
+
+		next := -1
+		for _, j := range outgoing[end] {
+			if !used[j] {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			return nil
+		}
+		i = next
+	}
+}
+
+// assembleRings returns the polygons of outers, each with the holes lying in it. A hole
+// belongs to the smallest outer ring containing it.
+func assembleRings(outers, holes [][]geom.Coord) *geom.MultiPolygon {
+	polygons := make([][][]geom.Coord, len(outers))
+	areas := make([]float64, len(outers))
+	shells := make([]*geom.Polygon, len(outers))
+	for i, outer := range outers {
+		polygons[i] = [][]geom.Coord{outer}
+		areas[i] = signedArea(outer)
+		shells[i] = geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{outer})
+	}
+
+	for _, hole := range holes {
+		// The middle of an edge of a hole is inside its polygon, a vertex may be on it.
+		mid := midpoint(segment{[2]float64{hole[0][0], hole[0][1]}, [2]float64{hole[1][0], hole[1][1]}})
+		best := -1
+		for i, shell := range shells {
+			if polygonContainsPoint(shell, mid[0], mid[1]) && (best < 0 || areas[i] < areas[best]) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			polygons[best] = append(polygons[best], hole)
+		}
+	}
+
+	mp := geom.NewMultiPolygon(geom.XY)
+	for _, rings := range polygons {
+		if polygon, err := geom.NewPolygon(geom.XY).SetCoords(rings); err == nil {
+			_ = mp.Push(polygon)
+		}
+	}
+	return mp
+}
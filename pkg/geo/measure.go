@@ -0,0 +1,217 @@
+package geo
+
+import (
+	"math"
+	"sort"
+
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/xy"
+)
+
+// EarthRadius is the WGS 84 equatorial radius in meters.
+const EarthRadius = 6378137.0
+
+// Attributes are the values derived from a region boundary at import time.
+type Attributes struct {
+	Centroid    geom.Coord
+	LabelPoint  geom.Coord
+	AreaKm2     float64
+	PerimeterKm float64
+	Bounds      *geom.Bounds
+}
+
+// Measure computes the derived attributes of mp, coordinates are expected in lng/lat order.
+func Measure(mp *geom.MultiPolygon) Attributes {
+	return Attributes{
+		Centroid:    xy.MultiPolygonCentroid(mp),
+		LabelPoint:  PointOnSurface(mp),
+		AreaKm2:     GeodesicArea(mp) / 1e6,
+		PerimeterKm: GeodesicPerimeter(mp) / 1e3,
+		Bounds:      mp.Bounds(),
+	}
+}
+
+// GeodesicArea returns the area of mp in square meters on a spherical earth.
+func GeodesicArea(mp *geom.MultiPolygon) float64 {
+	var area float64
+	for i := 0; i < mp.NumPolygons(); i++ {
+		p := mp.Polygon(i)
+		for j := 0; j < p.NumLinearRings(); j++ {
+			ringArea := math.Abs(ringArea(p.LinearRing(j).FlatCoords(), p.Stride()))
+			if j == 0 {
+				area += ringArea
+			} else {
+				area -= ringArea
+			}
+		}
+	}
+	return area
+}
+
+// ringArea is the signed spherical area of a closed ring, see "Some Algorithms for
+// Polygons on a Sphere" (Chamberlain and Duquette, JPL Publication 07-03).
+func ringArea(coords []float64, stride int) float64 {
+	n := len(coords) / stride
+	if n < 3 {
+		return 0
+	}
+
+	var total float64
+	for i := 0; i < n; i++ {
+		lng1, lat1 := coords[i*stride], coords[i*stride+1]
+		next := (i + 1) % n
+		lng2, lat2 := coords[next*stride], coords[next*stride+1]
+		total += radians(lng2-lng1) * (2 + math.Sin(radians(lat1)) + math.Sin(radians(lat2)))
+	}
+
+	return total * EarthRadius * EarthRadius / 2
+}
+
+// GeodesicPerimeter returns the length of every ring of mp, holes included, in meters.
+func GeodesicPerimeter(mp *geom.MultiPolygon) float64 {
+	var length float64
+	for i := 0; i < mp.NumPolygons(); i++ {
+		p := mp.Polygon(i)
+		for j := 0; j < p.NumLinearRings(); j++ {
+			length += lineLength(p.LinearRing(j).FlatCoords(), p.Stride())
+		}
+	}
+	return length
+}
+
+func lineLength(coords []float64, stride int) float64 {
+	var length float64
+	for i := stride; i < len(coords); i += stride {
+		length += Haversine(coords[i-stride], coords[i-stride+1], coords[i], coords[i+1])
+	}
+	return length
+}
+
+// Haversine returns the great-circle distance in meters between two lng/lat positions.
+func Haversine(lng1, lat1, lng2, lat2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ContainsPoint reports whether the lng/lat position lies inside mp, points inside a
+// hole are outside.
+func ContainsPoint(mp *geom.MultiPolygon, lng, lat float64) bool {
+	for i := 0; i < mp.NumPolygons(); i++ {
+		if polygonContainsPoint(mp.Polygon(i), lng, lat) {
+			return true
+		}
+	}
+	return false
+}
+
+func polygonContainsPoint(p *geom.Polygon, lng, lat float64) bool {
+	if p.NumLinearRings() == 0 {
+		return false
+	}
+
+	coord := geom.Coord{lng, lat}
+	if !xy.IsPointInRing(p.Layout(), coord, p.LinearRing(0).FlatCoords()) {
+		return false
+	}
+	for j := 1; j < p.NumLinearRings(); j++ {
+		if xy.IsPointInRing(p.Layout(), coord, p.LinearRing(j).FlatCoords()) {
+			return false
+		}
+	}
+	return true
+}
+
+// PointOnSurface returns a point guaranteed to lie inside mp, suitable for placing a
+// label. The centroid is used when it falls inside, otherwise the midpoint of the
+// widest horizontal span through the largest polygon.
+func PointOnSurface(mp *geom.MultiPolygon) geom.Coord {
+	if mp.NumPolygons() == 0 {
+		return nil
+	}
+
+	centroid := xy.MultiPolygonCentroid(mp)
+	if len(centroid) >= 2 && ContainsPoint(mp, centroid[0], centroid[1]) {
+		return centroid
+	}
+
+	var largest *geom.Polygon
+	var largestArea float64
+	for i := 0; i < mp.NumPolygons(); i++ {
+		p := mp.Polygon(i)
+		if area := math.Abs(p.Area()); largest == nil || area > largestArea {
+			largest, largestArea = p, area
+		}
+	}
+
+	return scanlineInteriorPoint(largest)
+}
+
+func scanlineInteriorPoint(p *geom.Polygon) geom.Coord {
+	b := p.Bounds()
+	centerY := (b.Min(1) + b.Max(1)) / 2
+
+	// Scan halfway between the vertices closest to the center so that the line never
+	// passes exactly through a vertex.
+	loY, hiY := b.Min(1), b.Max(1)
+	coords := p.FlatCoords()
+	stride := p.Stride()
+	for i := 1; i < len(coords); i += stride {
+		y := coords[i]
+		if y <= centerY && y > loY {
+			loY = y
+		}
+		if y > centerY && y < hiY {
+			hiY = y
+		}
+	}
+	scanY := (loY + hiY) / 2
+
+	var crossings []float64
+	for j := 0; j < p.NumLinearRings(); j++ {
+		ring := p.LinearRing(j).FlatCoords()
+		for i := stride; i < len(ring); i += stride {
+			x1, y1 := ring[i-stride], ring[i-stride+1]
+			x2, y2 := ring[i], ring[i+1]
+			if (y1 > scanY) != (y2 > scanY) {
+				crossings = append(crossings, x1+(scanY-y1)*(x2-x1)/(y2-y1))
+			}
+		}
+	}
+	sort.Float64s(crossings)
+
+	var best geom.Coord
+	var bestWidth float64 = -1
+	for i := 0; i+1 < len(crossings); i += 2 {
+		if width := crossings[i+1] - crossings[i]; width > bestWidth {
+			best, bestWidth = geom.Coord{(crossings[i] + crossings[i+1]) / 2, scanY}, width
+		}
+	}
+	if best == nil {
+		return geom.Coord{(b.Min(0) + b.Max(0)) / 2, centerY}
+	}
+
+	return best
+}
+
+// ToMultiPolygon normalises a Polygon or MultiPolygon into a MultiPolygon, any other
+// geometry type returns nil.
+func ToMultiPolygon(t geom.T) *geom.MultiPolygon {
+	switch v := t.(type) {
+	case *geom.MultiPolygon:
+		return v
+	case *geom.Polygon:
+		mp := geom.NewMultiPolygon(v.Layout())
+		if err := mp.Push(v); err != nil {
+			return nil
+		}
+		return mp
+	}
+	return nil
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,434 @@
+package geo
+
+import (
+	"math"
+	"sort"
+
+	"github.com/twpayne/go-geom"
+)
+
+// How far a point may lie from an edge and still be on it, in meters of the equal-area
+// projection and in degrees.
+const (
+	boundaryTolerance       = 1e-3
+	boundaryToleranceDegree = 1e-9
+)
+
+// segment is an edge of a ring or a line, from a to b.
+type segment struct {
+	a, b [2]float64
+}
+
+// Intersects reports whether mp and t share at least one point. t may be a Polygon, a
+// MultiPolygon, a LineString, a MultiLineString or a Point.
+func Intersects(mp *geom.MultiPolygon, t geom.T) bool {
+	if !boundsIntersect(mp.Bounds(), t.Bounds()) {
+		return false
+	}
+
+	switch v := t.(type) {
+	case *geom.Point:
+		return ContainsPoint(mp, v.X(), v.Y())
+	case *geom.LineString, *geom.MultiLineString:
+		line := lineSegments(t)
+		if segmentsCross(polygonSegments(mp), line) {
+			return true
+		}
+		return len(line) > 0 && ContainsPoint(mp, line[0].a[0], line[0].a[1])
+	}
+
+	other := ToMultiPolygon(t)
+	if other == nil {
+		return false
+	}
+	if segmentsCross(polygonSegments(mp), polygonSegments(other)) {
+		return true
+	}
+	// Without crossing edges one lies inside the other, or they are apart.
+	return containsAnyVertex(mp, other) || containsAnyVertex(other, mp)
+}
+
+// IntersectionArea returns the area in square meters that a and b have in common. Both are
+// projected onto a cylindrical equal-area projection, the projection GeodesicArea measures
+// in, and the boundary of the intersection is collected from the edges of each inside the
+// other.
+func IntersectionArea(a, b *geom.MultiPolygon) float64 {
+	if !boundsIntersect(a.Bounds(), b.Bounds()) {
+		return 0
+	}
+
+	pa, pb := equalAreaProjection(a), equalAreaProjection(b)
+	sa, sb := polygonSegments(pa), polygonSegments(pb)
+
+	// By Green's theorem the area is half the sum of the cross products of the boundary
+	// edges. An edge both share counts once, and only when their insides are on the same
+	// side of it, otherwise the intersection has no area there.
+	boundsA, boundsB := pa.Bounds(), pb.Bounds()
+	var twiceArea float64
+	for _, s := range sa {
+		if !segmentInBounds(s, boundsB) {
+			continue
+		}
+		for _, piece := range splitSegment(s, sb) {
+			mid := midpoint(piece)
+			if dir, ok := onBoundary(mid, sb, boundaryTolerance); ok {
+				if dot(dir, direction(piece)) > 0 {
+					twiceArea += cross(piece.a, piece.b)
+				}
+				continue
+			}
+			if ContainsPoint(pb, mid[0], mid[1]) {
+				twiceArea += cross(piece.a, piece.b)
+			}
+		}
+	}
+	for _, s := range sb {
+		if !segmentInBounds(s, boundsA) {
+			continue
+		}
+		for _, piece := range splitSegment(s, sa) {
+			mid := midpoint(piece)
+			if _, ok := onBoundary(mid, sa, boundaryTolerance); ok {
+				continue
+			}
+			if ContainsPoint(pa, mid[0], mid[1]) {
+				twiceArea += cross(piece.a, piece.b)
+			}
+		}
+	}
+
+	return math.Abs(twiceArea) / 2
+}
+
+// IntersectionLength returns the length in meters of the part of t, a LineString or a
+// MultiLineString, that lies inside mp or on its boundary.
+func IntersectionLength(mp *geom.MultiPolygon, t geom.T) float64 {
+	if !boundsIntersect(mp.Bounds(), t.Bounds()) {
+		return 0
+	}
+
+	edges := polygonSegments(mp)
+	var length float64
+	for _, s := range lineSegments(t) {
+		for _, piece := range splitSegment(s, edges) {
+			mid := midpoint(piece)
+			if _, ok := onBoundary(mid, edges, boundaryToleranceDegree); ok || ContainsPoint(mp, mid[0], mid[1]) {
+				length += Haversine(piece.a[0], piece.a[1], piece.b[0], piece.b[1])
+			}
+		}
+	}
+	return length
+}
+
+// SharedBorder returns the length in meters of the boundary a and b share and whether they
+// touch or overlap at all. Like GADM's administrative boundaries, neighbours are expected to
+// share the vertices of their common border, edges are only matched where both have them.
+func SharedBorder(a, b *geom.MultiPolygon) (float64, bool) {
+	if !boundsIntersect(a.Bounds(), b.Bounds()) {
+		return 0, false
+	}
+
+	// Only the edges where the bounds of both overlap can be shared or cross.
+	box := geom.NewBounds(geom.XY).Set(
+		math.Max(a.Bounds().Min(0), b.Bounds().Min(0)), math.Max(a.Bounds().Min(1), b.Bounds().Min(1)),
+		math.Min(a.Bounds().Max(0), b.Bounds().Max(0)), math.Min(a.Bounds().Max(1), b.Bounds().Max(1)),
+	)
+	sa, sb := segmentsInBounds(polygonSegments(a), box), segmentsInBounds(polygonSegments(b), box)
+
+	edges := make(map[segment]bool, len(sb))
+	vertices := make(map[[2]float64]bool, len(sb))
+	for _, s := range sb {
+		edges[undirected(s)] = true
+		vertices[s.a], vertices[s.b] = true, true
+	}
+
+	var length float64
+	touches := false
+	for _, s := range sa {
+		if edges[undirected(s)] {
+			length += Haversine(s.a[0], s.a[1], s.b[0], s.b[1])
+			touches = true
+		} else if vertices[s.a] || vertices[s.b] {
+			touches = true
+		}
+	}
+	if touches {
+		return length, true
+	}
+
+	// Without a shared vertex they touch where edges cross, or one lies inside the other.
+	if segmentsCross(sa, sb) {
+		return 0, true
+	}
+	return 0, containsFirstVertex(a, b) || containsFirstVertex(b, a)
+}
+
+// LineLength returns the length in meters of a LineString or a MultiLineString.
+func LineLength(t geom.T) float64 {
+	var length float64
+	for _, s := range lineSegments(t) {
+		length += Haversine(s.a[0], s.a[1], s.b[0], s.b[1])
+	}
+	return length
+}
+
+func boundsIntersect(a, b *geom.Bounds) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return false
+	}
+	return a.Min(0) <= b.Max(0) && a.Max(0) >= b.Min(0) && a.Min(1) <= b.Max(1) && a.Max(1) >= b.Min(1)
+}
+
+// equalAreaProjection returns mp in Lambert's cylindrical equal-area projection, in meters,
+// with every outer ring counterclockwise and every hole clockwise.
+func equalAreaProjection(mp *geom.MultiPolygon) *geom.MultiPolygon {
+	return orientRings(mp, func(lng, lat float64) geom.Coord {
+		return geom.Coord{EarthRadius * radians(lng), EarthRadius * math.Sin(radians(lat))}
+	})
+}
+
+func identity(x, y float64) geom.Coord {
+	return geom.Coord{x, y}
+}
+
+// orientRings returns mp with its positions mapped by project, every outer ring
+// counterclockwise and every hole clockwise. project must keep the orientation of rings.
+func orientRings(mp *geom.MultiPolygon, project func(x, y float64) geom.Coord) *geom.MultiPolygon {
+	oriented := geom.NewMultiPolygon(geom.XY)
+	for i := 0; i < mp.NumPolygons(); i++ {
+		p := mp.Polygon(i)
+		var rings [][]geom.Coord
+		for j := 0; j < p.NumLinearRings(); j++ {
+			flat := p.LinearRing(j).FlatCoords()
+			ring := make([]geom.Coord, 0, len(flat)/p.Stride())
+			for k := 0; k < len(flat); k += p.Stride() {
+				ring = append(ring, project(flat[k], flat[k+1]))
+			}
+			if counterclockwise := signedArea(ring) > 0; counterclockwise != (j == 0) {
+				for l, r := 0, len(ring)-1; l < r; l, r = l+1, r-1 {
+					ring[l], ring[r] = ring[r], ring[l]
+				}
+			}
+			rings = append(rings, ring)
+		}
+		polygon, err := geom.NewPolygon(geom.XY).SetCoords(rings)
+		if err == nil {
+			_ = oriented.Push(polygon)
+		}
+	}
+	return oriented
+}
+
+func signedArea(ring []geom.Coord) float64 {
+	var twiceArea float64
+	for i := range ring {
+		next := ring[(i+1)%len(ring)]
+		twiceArea += ring[i][0]*next[1] - next[0]*ring[i][1]
+	}
+	return twiceArea / 2
+}
+
+func polygonSegments(mp *geom.MultiPolygon) []segment {
+	var segments []segment
+	for i := 0; i < mp.NumPolygons(); i++ {
+		p := mp.Polygon(i)
+		for j := 0; j < p.NumLinearRings(); j++ {
+			segments = appendSegments(segments, p.LinearRing(j).FlatCoords(), p.Stride(), true)
+		}
+	}
+	return segments
+}
+
+func lineSegments(t geom.T) []segment {
+	switch v := t.(type) {
+	case *geom.LineString:
+		return appendSegments(nil, v.FlatCoords(), v.Stride(), false)
+	case *geom.MultiLineString:
+		var segments []segment
+		for i := 0; i < v.NumLineStrings(); i++ {
+			segments = appendSegments(segments, v.LineString(i).FlatCoords(), v.Stride(), false)
+		}
+		return segments
+	}
+	return nil
+}
+
+// appendSegments appends the edges between consecutive positions of coords, and from the
+// last back to the first when closing a ring that does not repeat its first position.
+func appendSegments(segments []segment, coords []float64, stride int, ring bool) []segment {
+	n := len(coords) / stride
+	for i := 1; i < n; i++ {
+		a := [2]float64{coords[(i-1)*stride], coords[(i-1)*stride+1]}
+		b := [2]float64{coords[i*stride], coords[i*stride+1]}
+		if a != b {
+			segments = append(segments, segment{a, b})
+		}
+	}
+	if ring && n > 2 {
+		first := [2]float64{coords[0], coords[1]}
+		last := [2]float64{coords[(n-1)*stride], coords[(n-1)*stride+1]}
+		if first != last {
+			segments = append(segments, segment{last, first})
+		}
+	}
+	return segments
+}
+
+func segmentsCross(a, b []segment) bool {
+	for _, s := range a {
+		for _, o := range b {
+			if segmentBoundsIntersect(s, o) && len(crossings(s, o)) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsAnyVertex(mp, other *geom.MultiPolygon) bool {
+	coords := other.FlatCoords()
+	for i := 0; i < len(coords); i += other.Stride() {
+		if ContainsPoint(mp, coords[i], coords[i+1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFirstVertex reports whether mp contains the first vertex of a polygon of other,
+// which is enough to tell whether they overlap once their edges are known not to cross.
+func containsFirstVertex(mp, other *geom.MultiPolygon) bool {
+	for i := 0; i < other.NumPolygons(); i++ {
+		coords := other.Polygon(i).FlatCoords()
+		if len(coords) >= 2 && ContainsPoint(mp, coords[0], coords[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentsInBounds(segments []segment, b *geom.Bounds) []segment {
+	var in []segment
+	for _, s := range segments {
+		if segmentInBounds(s, b) {
+			in = append(in, s)
+		}
+	}
+	return in
+}
+
+// undirected returns s from its lowest to its highest end, the same for both directions.
+func undirected(s segment) segment {
+	if s.b[0] < s.a[0] || s.b[0] == s.a[0] && s.b[1] < s.a[1] {
+		return segment{s.b, s.a}
+	}
+	return s
+}
+
+func segmentInBounds(s segment, b *geom.Bounds) bool {
+	return math.Min(s.a[0], s.b[0]) <= b.Max(0) && math.Max(s.a[0], s.b[0]) >= b.Min(0) &&
+		math.Min(s.a[1], s.b[1]) <= b.Max(1) && math.Max(s.a[1], s.b[1]) >= b.Min(1)
+}
+
+func segmentBoundsIntersect(s, o segment) bool {
+	return math.Min(s.a[0], s.b[0]) <= math.Max(o.a[0], o.b[0]) && math.Max(s.a[0], s.b[0]) >= math.Min(o.a[0], o.b[0]) &&
+		math.Min(s.a[1], s.b[1]) <= math.Max(o.a[1], o.b[1]) && math.Max(s.a[1], s.b[1]) >= math.Min(o.a[1], o.b[1])
+}
+
+// crossings returns the positions along s, from 0 at s.a to 1 at s.b, where o meets it. A
+// collinear o meets s where their overlap starts and ends.
+func crossings(s, o segment) []float64 {
+	d := sub(s.b, s.a)
+	e := sub(o.b, o.a)
+	denom := cross(d, e)
+	w := sub(o.a, s.a)
+
+	if denom == 0 {
+		if cross(w, d) != 0 {
+			return nil
+		}
+		length := dot(d, d)
+		t0, t1 := dot(w, d)/length, dot(sub(o.b, s.a), d)/length
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		if t1 < 0 || t0 > 1 {
+			return nil
+		}
+		return []float64{math.Max(t0, 0), math.Min(t1, 1)}
+	}
+
+	t := cross(w, e) / denom
+	u := cross(w, d) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return nil
+	}
+	return []float64{t}
+}
+
+// splitSegment splits s wherever one of edges meets it.
+func splitSegment(s segment, edges []segment) []segment {
+	ts := []float64{0, 1}
+	for _, o := range edges {
+		if segmentBoundsIntersect(s, o) {
+			ts = append(ts, crossings(s, o)...)
+		}
+	}
+	sort.Float64s(ts)
+
+	d := sub(s.b, s.a)
+	at := func(t float64) [2]float64 {
+		switch t {
+		case 0:
+			return s.a
+		case 1:
+			return s.b
+		}
+		return [2]float64{s.a[0] + t*d[0], s.a[1] + t*d[1]}
+	}
+
+	pieces := make([]segment, 0, len(ts)-1)
+	for i := 1; i < len(ts); i++ {
+		if ts[i] > ts[i-1] {
+			pieces = append(pieces, segment{at(ts[i-1]), at(ts[i])})
+		}
+	}
+	return pieces
+}
+
+// onBoundary returns the direction of the edge p lies within tolerance of, if any.
+func onBoundary(p [2]float64, edges []segment, tolerance float64) ([2]float64, bool) {
+	for _, o := range edges {
+		d := sub(o.b, o.a)
+		length := math.Sqrt(dot(d, d))
+		w := sub(p, o.a)
+		t := dot(w, d) / (length * length)
+		if t < 0 || t > 1 {
+			continue
+		}
+		if math.Abs(cross(d, w))/length <= tolerance {
+			return d, true
+		}
+	}
+	return [2]float64{}, false
+}
+
+func midpoint(s segment) [2]float64 {
+	return [2]float64{(s.a[0] + s.b[0]) / 2, (s.a[1] + s.b[1]) / 2}
+}
+
+func direction(s segment) [2]float64 {
+	return sub(s.b, s.a)
+}
+
+func sub(a, b [2]float64) [2]float64 {
+	return [2]float64{a[0] - b[0], a[1] - b[1]}
+}
+
+func cross(a, b [2]float64) float64 {
+	return a[0]*b[1] - a[1]*b[0]
+}
+
+func dot(a, b [2]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1]
+}
@@ -1,11 +1,17 @@
 package gorm
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/si-bas/go-rest-geospatial/config"
+	pkglogger "github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
@@ -13,27 +19,54 @@ import (
 )
 
 func ConnectDB() *gorm.DB {
+	dbConfig := config.Config.Db
+
 	// Write
-	db := connectMysqlDb(config.Config.Db)
+	db := connectDb(dbConfig)
+
+	// A SQLite file has no replicas, reads share the primary's pool.
+	if len(dbConfig.Replicas) == 0 || dbConfig.Driver == constant.DbDriverSqlite {
+		return db
+	}
+
+	// Read, the primary is the last replica so reads fail over to it when no replica is healthy.
+	// A replica that cannot be reached yet is left to the health check instead of failing the start.
+	var replicas []gorm.Dialector
+	for _, replica := range dbConfig.Replicas {
+		replicaDbConfig := replicaConfig(dbConfig, replica)
+		sqlDb, err := OpenReplica(replicaDbConfig)
+		if err != nil {
+			pkglogger.Error(context.Background(), "failed to open database replica", err, tag.Tag{Key: "host", Value: replicaDbConfig.Host})
+			continue
+		}
+		replicas = append(replicas, replicaDialector(dbConfig.Driver, sqlDb))
+	}
+	primaryDb, _ := db.DB()
+	replicas = append(replicas, replicaDialector(dbConfig.Driver, primaryDb))
 
-	// Read
-	db.Use(dbresolver.Register(dbresolver.Config{
-		Replicas: []gorm.Dialector{mysql.Open(configToDsn(config.Config.Db))},
-		Policy:   dbresolver.RandomPolicy{},
+	// dbresolver opens the replicas with the primary's config, the primary was pinged already.
+	db.Config.DisableAutomaticPing = true
+	policy := NewReplicaPolicy(time.Duration(dbConfig.HealthCheck) * time.Millisecond)
+	err := db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   policy,
 	}))
+	if err != nil {
+		pkglogger.Error(context.Background(), "failed to register database replicas", err)
+		return db
+	}
+	registerReplicaHealth(db, policy)
 
 	return db
 }
 
-func connectMysqlDb(dbConfig config.DB) *gorm.DB {
-	dsn := configToDsn(dbConfig)
-
+func connectDb(dbConfig config.DB) *gorm.DB {
 	logLevel := logger.Silent
 	if config.Config.App.Debug {
 		logLevel = logger.Info
 	}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(configToDialector(dbConfig), &gorm.Config{
 		Logger: logger.Default.LogMode(logLevel),
 		NamingStrategy: schema.NamingStrategy{
 			SingularTable: true,
@@ -43,16 +76,45 @@ func connectMysqlDb(dbConfig config.DB) *gorm.DB {
 		panic("error connecting to database, err=" + err.Error())
 	}
 
-	mysqlDb, _ := db.DB()
-	mysqlDb.SetMaxOpenConns(dbConfig.Connection.Open)
-	mysqlDb.SetMaxIdleConns(dbConfig.Connection.Idle)
+	sqlDb, _ := db.DB()
+	configurePool(sqlDb, dbConfig)
 
-	mili, _ := time.ParseDuration(fmt.Sprintf("%dms", dbConfig.Connection.TTL))
-	mysqlDb.SetConnMaxLifetime(time.Duration(mili.Nanoseconds()))
+	// A local SQLite file is created and migrated on first use, there is no database server to prepare.
+	if dbConfig.Driver == constant.DbDriverSqlite {
+		if err := MigrateSqlite(db); err != nil {
+			panic("error migrating database, err=" + err.Error())
+		}
+	}
 
 	return db
 }
 
+func configurePool(sqlDb *sql.DB, dbConfig config.DB) {
+	sqlDb.SetMaxOpenConns(dbConfig.Connection.Open)
+	sqlDb.SetMaxIdleConns(dbConfig.Connection.Idle)
+
+	mili, _ := time.ParseDuration(fmt.Sprintf("%dms", dbConfig.Connection.TTL))
+	sqlDb.SetConnMaxLifetime(time.Duration(mili.Nanoseconds()))
+}
+
+func configToDialector(dbConfig config.DB) gorm.Dialector {
+	switch dbConfig.Driver {
+	case constant.DbDriverPostgres:
+		return postgres.Open(configToPostgresDsn(dbConfig))
+	case constant.DbDriverSqlite:
+		return sqliteDialector(dbConfig)
+	}
+	return mysql.Open(configToDsn(dbConfig))
+}
+
 func configToDsn(dbConfig config.DB) string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&loc=Local", dbConfig.Username, dbConfig.Password, dbConfig.Host, dbConfig.Port, dbConfig.Name)
 }
+
+func configToPostgresDsn(dbConfig config.DB) string {
+	sslMode := dbConfig.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", dbConfig.Host, dbConfig.Port, dbConfig.Username, dbConfig.Password, dbConfig.Name, sslMode)
+}
@@ -0,0 +1,220 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultHealthCheck = 5 * time.Second
+	pingTimeout        = time.Second
+)
+
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type poolHealth struct {
+	healthy   bool
+	checkedAt time.Time
+	checking  bool
+}
+
+// ReplicaPolicy is a dbresolver policy that spreads reads randomly over the healthy replicas.
+// The last pool it is given is the fallback, a pool on the primary, used when no replica is
+// healthy. A pool is pinged the first time it is seen and again in the background once its
+// state is older than the interval, failed queries mark it unhealthy right away.
+type ReplicaPolicy struct {
+	interval time.Duration
+	mu       sync.Mutex
+	pools    map[gorm.ConnPool]*poolHealth
+}
+
+func NewReplicaPolicy(interval time.Duration) *ReplicaPolicy {
+	if interval <= 0 {
+		interval = defaultHealthCheck
+	}
+	return &ReplicaPolicy{
+		interval: interval,
+		pools:    make(map[gorm.ConnPool]*poolHealth),
+	}
+}
+
+func (p *ReplicaPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	fallback := connPools[len(connPools)-1]
+
+	var healthy []gorm.ConnPool
+	for _, connPool := range connPools[:len(connPools)-1] {
+		if p.isHealthy(connPool) {
+			healthy = append(healthy, connPool)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return fallback
+	}
+	return healthy[rand.Intn(len(healthy))]
+}
+
+// MarkUnhealthy takes connPool out of rotation until its next successful health check.
+func (p *ReplicaPolicy) MarkUnhealthy(connPool gorm.ConnPool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if health, ok := p.pools[connPool]; ok && health.healthy {
+		health.healthy = false
+		health.checkedAt = time.Now()
+		logger.Warn(context.Background(), "database replica marked unhealthy")
+	}
+}
+
+func (p *ReplicaPolicy) isHealthy(connPool gorm.ConnPool) bool {
+	p.mu.Lock()
+	health, ok := p.pools[connPool]
+	if !ok {
+		p.mu.Unlock()
+
+		healthy := ping(connPool)
+		p.mu.Lock()
+		p.pools[connPool] = &poolHealth{healthy: healthy, checkedAt: time.Now()}
+		p.mu.Unlock()
+		return healthy
+	}
+	defer p.mu.Unlock()
+
+	if !health.checking && time.Since(health.checkedAt) >= p.interval {
+		health.checking = true
+		go p.check(connPool, health)
+	}
+
+	return health.healthy
+}
+
+func (p *ReplicaPolicy) check(connPool gorm.ConnPool, health *poolHealth) {
+	healthy := ping(connPool)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if healthy != health.healthy {
+		if healthy {
+			logger.Info(context.Background(), "database replica is healthy again")
+		} else {
+			logger.Warn(context.Background(), "database replica failed health check")
+		}
+	}
+	health.healthy = healthy
+	health.checkedAt = time.Now()
+	health.checking = false
+}
+
+func ping(connPool gorm.ConnPool) bool {
+	p, ok := connPool.(pinger)
+	if !ok {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	return p.PingContext(ctx) == nil
+}
+
+// isConnectionError tells failures of the connection apart from errors of the query itself.
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr)
+}
+
+// registerReplicaHealth marks the pool a read ran on unhealthy when the read failed to reach it.
+func registerReplicaHealth(db *gorm.DB, policy *ReplicaPolicy) {
+	markUnhealthy := func(tx *gorm.DB) {
+		if tx.Error != nil && isConnectionError(tx.Error) {
+			policy.MarkUnhealthy(tx.Statement.ConnPool)
+		}
+	}
+
+	db.Callback().Query().After("gorm:query").Register("replica:health", markUnhealthy)
+	db.Callback().Row().After("gorm:row").Register("replica:health", markUnhealthy)
+	db.Callback().Raw().After("gorm:raw").Register("replica:health", markUnhealthy)
+}
+
+// replicaConfig returns the primary's config with the fields set on replica replaced.
+func replicaConfig(primary config.DB, replica config.DbReplica) config.DB {
+	dbConfig := primary
+	dbConfig.Replicas = nil
+
+	if replica.Host != "" {
+		dbConfig.Host = replica.Host
+	}
+	if replica.Port != 0 {
+		dbConfig.Port = replica.Port
+	}
+	if replica.Username != "" {
+		dbConfig.Username = replica.Username
+	}
+	if replica.Password != "" {
+		dbConfig.Password = replica.Password
+	}
+	if replica.Name != "" {
+		dbConfig.Name = replica.Name
+	}
+	if replica.SSLMode != "" {
+		dbConfig.SSLMode = replica.SSLMode
+	}
+	if replica.Connection.Open != 0 {
+		dbConfig.Connection.Open = replica.Connection.Open
+	}
+	if replica.Connection.Idle != 0 {
+		dbConfig.Connection.Idle = replica.Connection.Idle
+	}
+	if replica.Connection.TTL != 0 {
+		dbConfig.Connection.TTL = replica.Connection.TTL
+	}
+
+	return dbConfig
+}
+
+// OpenReplica opens a pool on the replica described by dbConfig without connecting to it,
+// the replica may still be down, the ReplicaPolicy keeps it out of rotation until it answers.
+func OpenReplica(dbConfig config.DB) (*sql.DB, error) {
+	dialector := mysql.New(mysql.Config{DSN: configToDsn(dbConfig), SkipInitializeWithVersion: true})
+	if dbConfig.Driver == constant.DbDriverPostgres {
+		dialector = postgres.Open(configToPostgresDsn(dbConfig))
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDb, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	configurePool(sqlDb, dbConfig)
+
+	return sqlDb, nil
+}
+
+// replicaDialector wraps an open pool, dbresolver would otherwise open every replica with
+// the driver's default pool settings.
+func replicaDialector(driver string, sqlDb *sql.DB) gorm.Dialector {
+	if driver == constant.DbDriverPostgres {
+		return postgres.New(postgres.Config{Conn: sqlDb})
+	}
+	return mysql.New(mysql.Config{Conn: sqlDb, SkipInitializeWithVersion: true})
+}
@@ -0,0 +1,216 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	sqlitedriver "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"github.com/si-bas/go-rest-geospatial/config"
+	migrations "github.com/si-bas/go-rest-geospatial/database/sqlite"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/wkt"
+	"gorm.io/gorm"
+)
+
+// SQLite has no geometry type, geometries are stored as WKT and the few spatial functions the
+// repository needs are implemented in Go. They are registered on the driver, so they exist on
+// every connection opened afterwards.
+func init() {
+	sqlitedriver.MustRegisterDeterministicScalarFunction("ST_GeomFromText", 1, stGeomFromText)
+	sqlitedriver.MustRegisterDeterministicScalarFunction("ST_AsText", 1, stAsText)
+	sqlitedriver.MustRegisterDeterministicScalarFunction("ST_Contains", 2, stContains)
+	sqlitedriver.MustRegisterDeterministicScalarFunction("ST_Intersects", 2, stIntersects)
+}
+
+// stGeomFromText validates the WKT and normalises it to a MULTIPOLYGON, like the PostGIS
+// dialect does with ST_Multi.
+func stGeomFromText(ctx *sqlitedriver.FunctionContext, args []driver.Value) (driver.Value, error) {
+	text, ok := textArg(args[0])
+	if !ok {
+		return nil, nil
+	}
+
+	t, err := wkt.Unmarshal(text)
+	if err != nil {
+		return nil, fmt.Errorf("ST_GeomFromText: %w", err)
+	}
+
+	if _, ok := t.(*geom.Point); ok {
+		return text, nil
+	}
+
+	mp := geo.ToMultiPolygon(t)
+	if mp == nil {
+		return nil, fmt.Errorf("ST_GeomFromText: unsupported geometry type %T", t)
+	}
+
+	return wkt.Marshal(mp)
+}
+
+func stAsText(ctx *sqlitedriver.FunctionContext, args []driver.Value) (driver.Value, error) {
+	text, ok := textArg(args[0])
+	if !ok {
+		return nil, nil
+	}
+	return text, nil
+}
+
+// stContains only supports a point as the second geometry, which is all the repository asks for.
+func stContains(ctx *sqlitedriver.FunctionContext, args []driver.Value) (driver.Value, error) {
+	geometryText, ok := textArg(args[0])
+	if !ok {
+		return nil, nil
+	}
+	pointText, ok := textArg(args[1])
+	if !ok {
+		return nil, nil
+	}
+
+	t, err := wkt.Unmarshal(geometryText)
+	if err != nil {
+		return nil, fmt.Errorf("ST_Contains: %w", err)
+	}
+	p, err := wkt.Unmarshal(pointText)
+	if err != nil {
+		return nil, fmt.Errorf("ST_Contains: %w", err)
+	}
+
+	point, ok := p.(*geom.Point)
+	if !ok {
+		return nil, fmt.Errorf("ST_Contains: unsupported geometry type %T", p)
+	}
+
+	mp := geo.ToMultiPolygon(t)
+	if mp == nil {
+		return int64(0), nil
+	}
+
+	if geo.ContainsPoint(mp, point.X(), point.Y()) {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+// stIntersects only supports a region as the first geometry, the second may be anything
+// geo.Intersects accepts.
+func stIntersects(ctx *sqlitedriver.FunctionContext, args []driver.Value) (driver.Value, error) {
+	geometryText, ok := textArg(args[0])
+	if !ok {
+		return nil, nil
+	}
+	otherText, ok := textArg(args[1])
+	if !ok {
+		return nil, nil
+	}
+
+	t, err := wkt.Unmarshal(geometryText)
+	if err != nil {
+		return nil, fmt.Errorf("ST_Intersects: %w", err)
+	}
+	other, err := wkt.Unmarshal(otherText)
+	if err != nil {
+		return nil, fmt.Errorf("ST_Intersects: %w", err)
+	}
+
+	mp := geo.ToMultiPolygon(t)
+	if mp == nil {
+		return int64(0), nil
+	}
+
+	if geo.Intersects(mp, other) {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+func textArg(v driver.Value) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
+
+func configToSqliteDsn(dbConfig config.DB) string {
+	return dbConfig.Path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+}
+
+func sqliteDialector(dbConfig config.DB) gorm.Dialector {
+	return sqlite.Open(configToSqliteDsn(dbConfig))
+}
+
+// MigrateSqlite applies the embedded migrations in database/sqlite that were not applied yet.
+// Applied versions are recorded in goose's version table, so the goose CLI can take over.
+func MigrateSqlite(db *gorm.DB) error {
+	err := db.Exec(`CREATE TABLE IF NOT EXISTS goose_db_version (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		version_id INTEGER NOT NULL,
+		is_applied INTEGER NOT NULL,
+		tstamp TIMESTAMP DEFAULT (datetime('now'))
+	)`).Error
+	if err != nil {
+		return err
+	}
+
+	var applied []int64
+	err = db.Raw("SELECT version_id FROM goose_db_version WHERE is_applied = 1").Scan(&applied).Error
+	if err != nil {
+		return err
+	}
+	isApplied := make(map[int64]bool)
+	for _, version := range applied {
+		isApplied[version] = true
+	}
+
+	files, err := fs.Glob(migrations.Migrations, "*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		version, err := strconv.ParseInt(strings.SplitN(file, "_", 2)[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid migration file name %s: %w", file, err)
+		}
+		if isApplied[version] {
+			continue
+		}
+
+		content, err := fs.ReadFile(migrations.Migrations, file)
+		if err != nil {
+			return err
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(upMigration(string(content))).Error; err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, 1)", version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// upMigration returns the statements between the goose Up and Down annotations.
+func upMigration(content string) string {
+	if i := strings.Index(content, "-- +goose Up"); i >= 0 {
+		content = content[i:]
+	}
+	if i := strings.Index(content, "-- +goose Down"); i >= 0 {
+		content = content[:i]
+	}
+	return content
+}
@@ -0,0 +1,209 @@
+// Package ratelimit limits how fast clients spend tokens, with a token bucket per client that
+// refills continuously and a quota of tokens per UTC day. Clients are kept in memory, every
+// instance of the service counts on its own.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// sweepInterval is how often clients that are back to their full limits are forgotten.
+const sweepInterval = time.Minute
+
+// defaultBurstSeconds is how many seconds of Rate the bucket holds when Burst is not set.
+const defaultBurstSeconds = 10
+
+// Limit is how much a client may spend. The bucket is unlimited when Rate is 0 and the day
+// when DailyQuota is 0.
+type Limit struct {
+	// Rate is how many tokens the bucket gets back per second.
+	Rate float64
+	// Burst is how many tokens the bucket holds, 10 seconds of Rate when it is 0.
+	Burst int
+	// DailyQuota is how many tokens can be spent per UTC day.
+	DailyQuota int
+}
+
+// Unlimited reports whether l limits nothing.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 && l.DailyQuota <= 0
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return int(math.Max(1, math.Ceil(l.Rate*defaultBurstSeconds)))
+}
+
+// policy describes l like the RateLimit-Policy header, e.g. `50;w=10, 10000;w=86400`.
+func (l Limit) policy() string {
+	var policies []string
+	if l.Rate > 0 {
+		burst := l.burst()
+		policies = append(policies, fmt.Sprintf("%d;w=%d", burst, int(math.Ceil(float64(burst)/l.Rate))))
+	}
+	if l.DailyQuota > 0 {
+		policies = append(policies, fmt.Sprintf("%d;w=%d", l.DailyQuota, int(day.Seconds())))
+	}
+	return strings.Join(policies, ", ")
+}
+
+// Result is whether a Take spent its cost, and the state of the bucket or the day, whichever
+// has less left.
+type Result struct {
+	Allowed bool
+	// Limit is the size of the bucket or the daily quota, 0 when nothing is limited.
+	Limit     int
+	Remaining int
+	// Reset is the time until Remaining is back to Limit.
+	Reset time.Duration
+	// RetryAfter is the time until the cost can be spent, when it was not.
+	RetryAfter time.Duration
+	// Policy lists the limits like the RateLimit-Policy header.
+	Policy string
+}
+
+type client struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket is full again.
+	full time.Time
+	// day is the start of the day spent was spent on.
+	day   time.Time
+	spent int
+}
+
+// Limiter keeps the bucket and the spending of the day of every client.
+type Limiter struct {
+	mu      sync.Mutex
+	clients map[string]*client
+	swept   time.Time
+}
+
+func New() *Limiter {
+	return &Limiter{clients: make(map[string]*client)}
+}
+
+// Take spends cost tokens of the client key under limit, if the bucket and the quota of the
+// day both have them. A cost above the size of the bucket or the daily quota spends the whole
+// of it, so a request is never refused forever.
+func (l *Limiter) Take(key string, limit Limit, cost int) Result {
+	return l.TakeAt(key, limit, cost, time.Now())
+}
+
+// TakeAt is Take at the time now.
+func (l *Limiter) TakeAt(key string, limit Limit, cost int, now time.Time) Result {
+	return l.take(key, limit, cost, now, true)
+}
+
+// Peek reports whether the client key could spend cost tokens under limit, like Take without
+// spending them.
+func (l *Limiter) Peek(key string, limit Limit, cost int) Result {
+	return l.PeekAt(key, limit, cost, time.Now())
+}
+
+// PeekAt is Peek at the time now.
+func (l *Limiter) PeekAt(key string, limit Limit, cost int, now time.Time) Result {
+	return l.take(key, limit, cost, now, false)
+}
+
+func (l *Limiter) take(key string, limit Limit, cost int, now time.Time, spend bool) Result {
+	if limit.Unlimited() {
+		return Result{Allowed: true}
+	}
+
+	burst := limit.burst()
+	if cost < 1 {
+		cost = 1
+	}
+	if limit.Rate > 0 && cost > burst {
+		cost = burst
+	}
+	if limit.DailyQuota > 0 && cost > limit.DailyQuota {
+		cost = limit.DailyQuota
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{tokens: float64(burst), updated: now}
+		l.clients[key] = c
+	}
+	if elapsed := now.Sub(c.updated).Seconds(); limit.Rate > 0 && elapsed > 0 {
+		c.tokens = math.Min(float64(burst), c.tokens+elapsed*limit.Rate)
+	}
+	c.updated = now
+	today := now.UTC().Truncate(day)
+	if !c.day.Equal(today) {
+		c.day = today
+		c.spent = 0
+	}
+
+	bucketAllows := limit.Rate <= 0 || c.tokens >= float64(cost)
+	quotaAllows := limit.DailyQuota <= 0 || c.spent+cost <= limit.DailyQuota
+	result := Result{Allowed: bucketAllows && quotaAllows, Policy: limit.policy()}
+	if result.Allowed && spend {
+		if limit.Rate > 0 {
+			c.tokens -= float64(cost)
+		}
+		c.spent += cost
+	}
+
+	tomorrow := today.Add(day).Sub(now)
+	switch {
+	case !quotaAllows:
+		result.RetryAfter = tomorrow
+	case !bucketAllows:
+		result.RetryAfter = seconds((float64(cost) - c.tokens) / limit.Rate)
+	}
+
+	c.full = now
+	if limit.Rate > 0 {
+		result.Limit = burst
+		result.Remaining = int(math.Floor(c.tokens))
+		result.Reset = seconds((float64(burst) - c.tokens) / limit.Rate)
+		c.full = now.Add(result.Reset)
+	}
+	if limit.DailyQuota > 0 {
+		// The quota of the day is kept until the day is over.
+		c.full = now.Add(tomorrow)
+		if remaining := limit.DailyQuota - c.spent; limit.Rate <= 0 || remaining < result.Remaining {
+			result.Limit = limit.DailyQuota
+			result.Remaining = remaining
+			result.Reset = tomorrow
+		}
+	}
+
+	return result
+}
+
+// sweep forgets the clients whose bucket is full and whose day is over, they start over as
+// new clients.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < sweepInterval {
+		return
+	}
+	l.swept = now
+
+	for key, c := range l.clients {
+		if !now.Before(c.full) {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// seconds returns s seconds rounded up to a whole second, the precision of the headers.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s)) * time.Second
+}
@@ -0,0 +1,148 @@
+// Package search folds names into a comparable form and scores how well a name matches a
+// query. Candidates are found through trigrams, which every storage backend indexes.
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Match kinds, from the best to the weakest.
+const (
+	MatchExact      = "exact"
+	MatchPrefix     = "prefix"
+	MatchWordPrefix = "word_prefix"
+	MatchFuzzy      = "fuzzy"
+)
+
+// MinSimilarity is the edit similarity below which a name is not a fuzzy match, it lets
+// "jogjakarta" find "Yogyakarta".
+const MinSimilarity = 0.6
+
+// Fold lowercases s, strips diacritics and replaces everything that is not a letter or a
+// digit with a single space, so "Daerah Istimewa Yogyakarta" and "daerah-istimewa yógyakarta"
+// fold to the same string.
+func Fold(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// Trigrams returns the distinct trigrams of the folded s. Like pg_trgm every word is padded
+// with two spaces in front and one behind, so short words and word starts weigh more.
+func Trigrams(s string) []string {
+	seen := make(map[string]bool)
+	var trigrams []string
+	for _, word := range strings.Fields(Fold(s)) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			trigram := string(runes[i : i+3])
+			if !seen[trigram] {
+				seen[trigram] = true
+				trigrams = append(trigrams, trigram)
+			}
+		}
+	}
+	return trigrams
+}
+
+// Similarity is one minus the edit distance of the folded a and b relative to the longer of
+// the two, between 0 and 1.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(Fold(a)), []rune(Fold(b))
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// wordSimilarity is the best Similarity of q against any run of as many consecutive words of
+// n as q has, so "jogjakarta" is compared with "yogyakarta" in "daerah istimewa yogyakarta".
+func wordSimilarity(q, n string) float64 {
+	qWords, nWords := strings.Fields(q), strings.Fields(n)
+	best := Similarity(q, n)
+	for i := 0; i+len(qWords) <= len(nWords); i++ {
+		if s := Similarity(q, strings.Join(nWords[i:i+len(qWords)], " ")); s > best {
+			best = s
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// Score rates how well name matches query, between 0 and 1. The match kinds occupy disjoint
+// ranges so an exact match always outranks a prefix match, which always outranks a match on
+// the start of a later word, which always outranks a fuzzy match. Shorter names rank first
+// within the prefix kinds, closer names within the fuzzy kind. It returns false when name
+// does not match at all.
+func Score(query, name string) (float64, string, bool) {
+	q, n := Fold(query), Fold(name)
+	if q == "" || n == "" {
+		return 0, "", false
+	}
+
+	coverage := float64(len([]rune(q))) / float64(len([]rune(n)))
+	switch {
+	case q == n:
+		return 1, MatchExact, true
+	case strings.HasPrefix(n, q):
+		return 0.7 + 0.2*coverage, MatchPrefix, true
+	case strings.Contains(" "+n, " "+q):
+		return 0.5 + 0.2*coverage, MatchWordPrefix, true
+	}
+
+	// The similarity of the whole name breaks ties between names sharing the closest word.
+	if similarity := wordSimilarity(q, n); similarity >= MinSimilarity {
+		return 0.5 * (0.8*similarity + 0.2*Similarity(q, n)), MatchFuzzy, true
+	}
+	return 0, "", false
+}
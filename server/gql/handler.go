@@ -0,0 +1,67 @@
+package gql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/service"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+// maxDepth caps the nesting of a query, a region page needs about four levels.
+const maxDepth = 10
+
+// maxQueryLength caps the bytes of a query, a longer one is rejected before it is parsed.
+const maxQueryLength = 10000
+
+type request struct {
+	Query         string                 `json:"query" form:"query"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler serves the GraphQL API over geospatialService. The query is posted as JSON, or sent
+// in the query string of a GET with the variables as JSON. Responses are GraphQL responses,
+// not the envelope of the REST API.
+func Handler(geospatialService service.GeospatialService) gin.HandlerFunc {
+	schema := graphql.MustParseSchema(schemaString, &rootResolver{geospatialService: geospatialService},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(maxDepth),
+	)
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var req request
+		var err error
+		if c.Request.Method == http.MethodGet {
+			err = c.ShouldBindQuery(&req)
+			if variables := c.Query("variables"); err == nil && variables != "" {
+				err = json.Unmarshal([]byte(variables), &req.Variables)
+			}
+		} else {
+			err = c.ShouldBindJSON(&req)
+		}
+		if err == nil && req.Query == "" {
+			err = gqlerrors.Errorf("query is required")
+		}
+		if err == nil && len(req.Query) > maxQueryLength {
+			err = gqlerrors.Errorf("query must be at most %d bytes", maxQueryLength)
+		}
+		if err != nil {
+			logger.Warn(ctx, "failed to bind graphql request", tag.Err(err))
+			c.JSON(http.StatusBadRequest, &graphql.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}})
+			return
+		}
+
+		c.JSON(http.StatusOK, schema.Exec(withBudget(ctx), req.Query, req.OperationName, req.Variables))
+	}
+}
@@ -0,0 +1,208 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/service"
+)
+
+// maxRegions caps the regions a query resolves, whatever its shape, so a few nested lists of
+// children cannot load a whole country with its geometry.
+const maxRegions = 10000
+
+type budgetKey struct{}
+
+// withBudget returns a context allowing maxRegions regions to be resolved.
+func withBudget(ctx context.Context) context.Context {
+	budget := new(atomic.Int64)
+	budget.Store(maxRegions)
+	return context.WithValue(ctx, budgetKey{}, budget)
+}
+
+// spend takes n regions from the budget of ctx, failing once the query resolved too many.
+func spend(ctx context.Context, n int) error {
+	budget, ok := ctx.Value(budgetKey{}).(*atomic.Int64)
+	if !ok {
+		return nil
+	}
+	if budget.Add(-int64(n)) < 0 {
+		return fmt.Errorf("query resolves more than %d regions, page or narrow it", maxRegions)
+	}
+	return nil
+}
+
+// childrenPage is a page of the children of each region.
+type childrenPage struct {
+	limit, page int
+}
+
+// batch is a set of regions resolved together, the items of a list or the children of the
+// items of a list. The first time a region of a batch asks for a relation it is loaded for
+// every region of the batch, so a nested query costs a query per level instead of one per
+// region. The regions a relation returns form the next batch, for children the regions of the
+// page asked for, so a deeper level only loads what is returned.
+type batch struct {
+	geospatialService service.GeospatialService
+	regions           []model.Geospatial
+
+	childrenOnce sync.Once
+	children     map[string][]model.Geospatial
+	childrenErr  error
+
+	pagesMu sync.Mutex
+	pages   map[childrenPage]map[string][]*regionResolver
+
+	ancestorsOnce sync.Once
+	ancestors     map[uint][]*regionResolver
+	ancestorsErr  error
+
+	geometryOnce sync.Once
+	geometry     map[uint]json.RawMessage
+	geometryErr  error
+}
+
+// newBatch returns the resolvers of regions, which share a batch.
+func newBatch(geospatialService service.GeospatialService, regions []model.Geospatial) []*regionResolver {
+	b := &batch{geospatialService: geospatialService, regions: regions}
+
+	resolvers := make([]*regionResolver, 0, len(regions))
+	for i := range regions {
+		resolvers = append(resolvers, &regionResolver{g: &regions[i], batch: b})
+	}
+	return resolvers
+}
+
+// childrenOf returns a page of the children of the region with the given GADM id.
+func (b *batch) childrenOf(ctx context.Context, gadmID string, page childrenPage) ([]*regionResolver, error) {
+	b.childrenOnce.Do(func() {
+		ids := make([]uint, 0, len(b.regions))
+		for _, g := range b.regions {
+			ids = append(ids, g.ID)
+		}
+
+		children, err := b.geospatialService.List(ctx, model.GeospatialFilter{ParentIds: ids})
+		if err == nil {
+			err = spend(ctx, len(children))
+		}
+		if err != nil {
+			b.childrenErr = err
+			return
+		}
+		sortByID(children)
+
+		b.children = make(map[string][]model.Geospatial)
+		for _, child := range children {
+			b.children[child.ParentGadmID] = append(b.children[child.ParentGadmID], child)
+		}
+	})
+	if b.childrenErr != nil {
+		return nil, b.childrenErr
+	}
+
+	// The same page of every region of the batch forms the next batch.
+	b.pagesMu.Lock()
+	defer b.pagesMu.Unlock()
+
+	pages, ok := b.pages[page]
+	if !ok {
+		var paged []model.Geospatial
+		for _, children := range b.children {
+			start := min(len(children), (page.page-1)*page.limit)
+			end := min(len(children), start+page.limit)
+			paged = append(paged, children[start:end]...)
+		}
+		sortByID(paged)
+
+		pages = make(map[string][]*regionResolver)
+		for _, child := range newBatch(b.geospatialService, paged) {
+			pages[child.g.ParentGadmID] = append(pages[child.g.ParentGadmID], child)
+		}
+		if b.pages == nil {
+			b.pages = make(map[childrenPage]map[string][]*regionResolver)
+		}
+		b.pages[page] = pages
+	}
+
+	children := pages[gadmID]
+	if children == nil {
+		children = []*regionResolver{}
+	}
+	return children, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ancestorsOf returns the regions above the region with the given id, the country first.
+func (b *batch) ancestorsOf(ctx context.Context, id uint) ([]*regionResolver, error) {
+	b.ancestorsOnce.Do(func() {
+		byRegion, err := b.geospatialService.Ancestors(ctx, b.regions)
+		if err != nil {
+			b.ancestorsErr = err
+			return
+		}
+
+		// The ancestors shared by several regions of the batch are resolved once.
+		var ancestors []model.Geospatial
+		index := make(map[uint]int)
+		for _, chain := range byRegion {
+			for _, a := range chain {
+				if _, ok := index[a.ID]; !ok {
+					index[a.ID] = len(ancestors)
+					ancestors = append(ancestors, a)
+				}
+			}
+		}
+		if err := spend(ctx, len(ancestors)); err != nil {
+			b.ancestorsErr = err
+			return
+		}
+		resolvers := newBatch(b.geospatialService, ancestors)
+
+		b.ancestors = make(map[uint][]*regionResolver, len(byRegion))
+		for regionID, chain := range byRegion {
+			for _, a := range chain {
+				b.ancestors[regionID] = append(b.ancestors[regionID], resolvers[index[a.ID]])
+			}
+		}
+	})
+	if b.ancestorsErr != nil {
+		return nil, b.ancestorsErr
+	}
+
+	ancestors := b.ancestors[id]
+	if ancestors == nil {
+		ancestors = []*regionResolver{}
+	}
+	return ancestors, nil
+}
+
+// geometryOf returns the GeoJSON geometry of the region with the given id.
+func (b *batch) geometryOf(ctx context.Context, id uint) (json.RawMessage, error) {
+	b.geometryOnce.Do(func() {
+		regions, err := b.geospatialService.Include(ctx, b.regions, []string{model.IncludeGeometry})
+		if err != nil {
+			b.geometryErr = err
+			return
+		}
+
+		b.geometry = make(map[uint]json.RawMessage, len(regions))
+		for _, g := range regions {
+			b.geometry[g.ID] = g.Shape
+		}
+	})
+	if b.geometryErr != nil {
+		return nil, b.geometryErr
+	}
+
+	return b.geometry[id], nil
+}
@@ -0,0 +1,260 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+type rootResolver struct {
+	geospatialService service.GeospatialService
+}
+
+func parseID(id graphql.ID) (uint, error) {
+	value, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil {
+		return 0, errors.New("id must be an integer")
+	}
+	return uint(value), nil
+}
+
+func (r *rootResolver) Region(ctx context.Context, args struct{ ID graphql.ID }) (*regionResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	geospatial, err := r.geospatialService.Get(ctx, id, model.GeospatialFilter{})
+	if err == service.ErrGeospatialNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := spend(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	return newBatch(r.geospatialService, []model.Geospatial{*geospatial})[0], nil
+}
+
+type regionsArgs struct {
+	Name     *string
+	Levels   *[]int32
+	Types    *[]string
+	ParentID *graphql.ID
+	Limit    *int32
+	Page     *int32
+}
+
+func (r *rootResolver) Regions(ctx context.Context, args regionsArgs) ([]*regionResolver, error) {
+	var filter model.GeospatialFilter
+	if args.Name != nil {
+		filter.Name = *args.Name
+	}
+	if args.Levels != nil {
+		for _, level := range *args.Levels {
+			if level < 0 {
+				return nil, errors.New("levels must not be negative")
+			}
+			filter.Levels = append(filter.Levels, uint(level))
+		}
+	}
+	if args.Types != nil {
+		filter.Types = *args.Types
+	}
+	if args.ParentID != nil {
+		parentID, err := parseID(*args.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		filter.ParentIds = []uint{parentID}
+	}
+
+	param := pagination.Param{SkipTotal: true}
+	if args.Limit != nil {
+		if *args.Limit < 1 {
+			return nil, errors.New("limit must be positive")
+		}
+		param.Limit = uint(*args.Limit)
+	}
+	if args.Page != nil {
+		if *args.Page < 1 {
+			return nil, errors.New("page must be positive")
+		}
+		param.Page = uint(*args.Page)
+	}
+
+	data, _, err := r.geospatialService.ListPaginate(ctx, filter, param)
+	if err == nil {
+		err = spend(ctx, len(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newBatch(r.geospatialService, data), nil
+}
+
+func (r *rootResolver) Reverse(ctx context.Context, args struct{ Lat, Lng float64 }) ([]*regionResolver, error) {
+	if !(args.Lat >= -90 && args.Lat <= 90) || !(args.Lng >= -180 && args.Lng <= 180) {
+		return nil, errors.New("lat must be within -90 and 90, lng within -180 and 180")
+	}
+	data, err := r.geospatialService.List(ctx, model.GeospatialFilter{Lat: &args.Lat, Lng: &args.Lng})
+	if err == nil {
+		err = spend(ctx, len(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(data, func(i, j int) bool { return data[i].Level < data[j].Level })
+
+	return newBatch(r.geospatialService, data), nil
+}
+
+type regionResolver struct {
+	g     *model.Geospatial
+	batch *batch
+}
+
+func (r *regionResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(r.g.ID), 10))
+}
+
+func (r *regionResolver) Name() string {
+	return r.g.Name
+}
+
+func (r *regionResolver) Type() string {
+	return r.g.Type
+}
+
+func (r *regionResolver) EngType() string {
+	return r.g.EngType
+}
+
+func (r *regionResolver) Level() int32 {
+	return int32(r.g.Level)
+}
+
+func (r *regionResolver) AreaKm2() *float64 {
+	return r.g.AreaKm2
+}
+
+func (r *regionResolver) PerimeterKm() *float64 {
+	return r.g.PerimeterKm
+}
+
+func (r *regionResolver) Centroid() *latLngResolver {
+	return newLatLngResolver(r.g.Centroid())
+}
+
+func (r *regionResolver) LabelPoint() *latLngResolver {
+	return newLatLngResolver(r.g.LabelPoint())
+}
+
+func (r *regionResolver) Bbox() *[]float64 {
+	bbox := r.g.Bbox()
+	if bbox == nil {
+		return nil
+	}
+	return &bbox
+}
+
+func (r *regionResolver) Geometry(ctx context.Context) (*GeoJSON, error) {
+	geometry, err := r.batch.geometryOf(ctx, r.g.ID)
+	if err != nil || len(geometry) == 0 {
+		return nil, err
+	}
+	return &GeoJSON{raw: geometry}, nil
+}
+
+func (r *regionResolver) Parent(ctx context.Context) (*regionResolver, error) {
+	ancestors, err := r.batch.ancestorsOf(ctx, r.g.ID)
+	if err != nil || len(ancestors) == 0 {
+		return nil, err
+	}
+	return ancestors[len(ancestors)-1], nil
+}
+
+func (r *regionResolver) Ancestors(ctx context.Context) ([]*regionResolver, error) {
+	return r.batch.ancestorsOf(ctx, r.g.ID)
+}
+
+// defaultChildren and maxChildren are the default and largest page of children.
+const (
+	defaultChildren = 100
+	maxChildren     = 1000
+)
+
+type childrenArgs struct {
+	Limit *int32
+	Page  *int32
+}
+
+func (r *regionResolver) Children(ctx context.Context, args childrenArgs) ([]*regionResolver, error) {
+	page := childrenPage{limit: defaultChildren, page: 1}
+	if args.Limit != nil {
+		if *args.Limit < 1 || *args.Limit > maxChildren {
+			return nil, fmt.Errorf("limit must be within 1 and %d", maxChildren)
+		}
+		page.limit = int(*args.Limit)
+	}
+	if args.Page != nil {
+		if *args.Page < 1 {
+			return nil, errors.New("page must be positive")
+		}
+		page.page = int(*args.Page)
+	}
+
+	return r.batch.childrenOf(ctx, r.g.GadmID, page)
+}
+
+type latLngResolver struct {
+	p *model.LatLng
+}
+
+func newLatLngResolver(p *model.LatLng) *latLngResolver {
+	if p == nil {
+		return nil
+	}
+	return &latLngResolver{p: p}
+}
+
+func (r *latLngResolver) Lat() float64 {
+	return r.p.Lat
+}
+
+func (r *latLngResolver) Lng() float64 {
+	return r.p.Lng
+}
+
+// GeoJSON is a geometry written as is.
+type GeoJSON struct {
+	raw json.RawMessage
+}
+
+func (GeoJSON) ImplementsGraphQLType(name string) bool {
+	return name == "GeoJSON"
+}
+
+func (g *GeoJSON) UnmarshalGraphQL(input interface{}) error {
+	return errors.New("GeoJSON is an output type")
+}
+
+func (g GeoJSON) MarshalJSON() ([]byte, error) {
+	return g.raw, nil
+}
+
+func sortByID(geospatials []model.Geospatial) {
+	sort.SliceStable(geospatials, func(i, j int) bool { return geospatials[i].ID < geospatials[j].ID })
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+)
+
+// maxAPIKeyNameLength is the length of the name column.
+const maxAPIKeyNameLength = 255
+
+// APIKeyCreate creates an API key, the response holds the key itself.
+func (h *Handler) APIKeyCreate(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var body model.APIKeyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	name := strings.TrimSpace(body.Name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "name must be 1 to 255 characters"))
+		return
+	}
+
+	data, err := h.authService.CreateKey(ctx, name, body.Role, body.Limit)
+	if err == service.ErrInvalidRole || err == service.ErrInvalidRateLimit {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusCreated().StatusCode, result.SetData(data))
+}
+
+// APIKeyList returns every API key, without the keys themselves.
+func (h *Handler) APIKeyList(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	data, err := h.authService.ListKeys(ctx)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
+}
+
+// APIKeyRevoke revokes an API key.
+func (h *Handler) APIKeyRevoke(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "id must be an integer"))
+		return
+	}
+
+	err = h.authService.RevokeKey(ctx, uint(id))
+	if err == service.ErrAPIKeyNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result)
+}
+
+// APIKeyLimit replaces the rate limit of an API key.
+func (h *Handler) APIKeyLimit(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "id must be an integer"))
+		return
+	}
+
+	var body model.RateLimit
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	data, err := h.authService.SetKeyLimit(ctx, uint(id), body)
+	if err == service.ErrInvalidRateLimit {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	if err == service.ErrAPIKeyNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+)
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 50
+)
+
+func (h *Handler) Autocomplete(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var query model.AutocompleteParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	if strings.TrimSpace(query.Q) == "" {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "q is required"))
+		return
+	}
+
+	if query.Limit > maxAutocompleteLimit {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "limit must not be greater than 50"))
+		return
+	}
+	limit := int(query.Limit)
+	if limit == 0 {
+		limit = defaultAutocompleteLimit
+	}
+
+	filter, err := validateGeospatialFilter(model.GeospatialFilterParams{Levels: query.Levels})
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	// latlng biases the suggestions here, unlike in /v1/q it does not filter them.
+	opts := model.AutocompleteOptions{ParentID: query.ParentID, Lang: query.Lang}
+	if query.LatLng != "" {
+		lat, lng, err := parseLatLng(query.LatLng)
+		if err != nil {
+			c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+			return
+		}
+		opts.Point = &model.LatLng{Lat: lat, Lng: lng}
+	}
+
+	data, err := h.geospatialService.Autocomplete(ctx, query.Q, *filter, opts, limit)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
+}
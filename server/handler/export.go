@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/export"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+)
+
+func (h *Handler) ExportCreate(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var params model.ExportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	if !export.IsSupported(params.Format) {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, fmt.Sprintf("format must be one of %s", strings.Join(export.Formats, ", "))))
+		return
+	}
+
+	var query model.GeospatialFilterParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	filter, err := validateGeospatialFilter(query)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	job, err := h.exportService.CreateJob(ctx, *filter, params.Format)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusAccepted().StatusCode, result.SetData(job))
+}
+
+func (h *Handler) ExportDetail(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	job, err := h.exportService.GetJob(ctx, c.Param("id"))
+	if err != nil {
+		if err == service.ErrExportJobNotFound {
+			c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+			return
+		}
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(job))
+}
+
+func (h *Handler) ExportDownload(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	job, err := h.exportService.GetJob(ctx, c.Param("id"))
+	if err != nil {
+		if err == service.ErrExportJobNotFound {
+			c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+			return
+		}
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	if job.Status != constant.ExportStatusCompleted {
+		c.JSON(result.APIStatusConflict().StatusCode, result.SetError(response.ErrConflict, fmt.Sprintf("export job is %s", job.Status)))
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType(job.Format))
+	c.FileAttachment(job.FilePath, fmt.Sprintf("geospatial.%s", job.Format))
+}
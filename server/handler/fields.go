@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+// regionShape is the sparse fieldset and the related data a client asked regions in.
+type regionShape struct {
+	fields  []string
+	include []string
+}
+
+// alwaysRendered are rendered whatever the fields, they identify a region or describe how it
+// was found and named rather than the region itself.
+var alwaysRendered = []string{"id", "official_name", "score", "match", "matched_name", "overlap_km2", "overlap_km", "overlap_pct", "border_km"}
+
+// parseRegionShape parses the comma separated fields and include parameters.
+func parseRegionShape(fields, include string) (*regionShape, error) {
+	shape := regionShape{}
+
+	if fields != "" {
+		for _, field := range strings.Split(fields, ",") {
+			field = strings.TrimSpace(field)
+			if _, ok := model.GeospatialFields[field]; !ok {
+				names := make([]string, 0, len(model.GeospatialFields))
+				for name := range model.GeospatialFields {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				return nil, fmt.Errorf("cannot select field %q, fields are %s", field, strings.Join(names, ", "))
+			}
+			shape.fields = append(shape.fields, field)
+		}
+	}
+
+	if include != "" {
+		for _, name := range strings.Split(include, ",") {
+			name = strings.TrimSpace(name)
+			if !containsString(model.GeospatialIncludes, name) {
+				return nil, fmt.Errorf("cannot include %q, includes are %s", name, strings.Join(model.GeospatialIncludes, ", "))
+			}
+			if !containsString(shape.include, name) {
+				shape.include = append(shape.include, name)
+			}
+		}
+	}
+
+	return &shape, nil
+}
+
+func (s *regionShape) includes(name string) bool {
+	return containsString(s.include, name)
+}
+
+// columns returns the columns to read for the fields, the columns regions are sorted by and
+// the key columns, or nil for every column when no fields were picked.
+func (s *regionShape) columns(sorts []pagination.ParamSort) []string {
+	if len(s.fields) == 0 {
+		return nil
+	}
+
+	columns := append([]string{}, model.GeospatialKeyColumns...)
+	add := func(column string) {
+		if !containsString(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	for _, field := range s.fields {
+		for _, column := range model.GeospatialFields[field] {
+			add(column)
+		}
+	}
+	for _, s := range sorts {
+		if s.Column != "distance_km" {
+			add(s.Column)
+		}
+	}
+	return columns
+}
+
+// render returns geospatials as rendered with only the picked fields, the related data and
+// the nested child, or geospatials itself when no fields were picked.
+func (s *regionShape) render(geospatials []model.Geospatial) (interface{}, error) {
+	if len(s.fields) == 0 {
+		return geospatials, nil
+	}
+
+	rendered := make([]map[string]json.RawMessage, 0, len(geospatials))
+	for i := range geospatials {
+		r, err := s.renderOne(&geospatials[i])
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, r)
+	}
+	return rendered, nil
+}
+
+func (s *regionShape) renderOne(g *model.Geospatial) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	rendered := make(map[string]json.RawMessage)
+	for key, value := range all {
+		if containsString(s.fields, key) || containsString(s.include, key) || containsString(alwaysRendered, key) {
+			rendered[key] = value
+		}
+	}
+
+	if g.Child != nil {
+		child, err := s.renderOne(g.Child)
+		if err != nil {
+			return nil, err
+		}
+		if rendered["child"], err = json.Marshal(child); err != nil {
+			return nil, err
+		}
+	}
+	return rendered, nil
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
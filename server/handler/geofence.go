@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+)
+
+const (
+	// maxGeofencePositions caps the positions of one request.
+	maxGeofencePositions = 1000
+	// maxObjectIDLength is the length of the object_id column.
+	maxObjectIDLength = 255
+)
+
+// parseGeofenceGeometry reads the GeoJSON polygon of a fence.
+func parseGeofenceGeometry(data []byte) (geom.T, error) {
+	errMsg := "geometry must be a GeoJSON Polygon or MultiPolygon"
+
+	var t geom.T
+	if err := geojson.Unmarshal(data, &t); err != nil {
+		return nil, errors.New(errMsg)
+	}
+	switch t.(type) {
+	case *geom.Polygon, *geom.MultiPolygon:
+	default:
+		return nil, errors.New(errMsg)
+	}
+
+	// The positions are checked like those of an intersection query.
+	return parseIntersectsGeometry(data)
+}
+
+func validatePositions(positions []model.GeofencePosition) error {
+	if len(positions) == 0 {
+		return errors.New("positions must not be empty")
+	}
+	if len(positions) > maxGeofencePositions {
+		return fmt.Errorf("positions must not have more than %d items", maxGeofencePositions)
+	}
+
+	for i, p := range positions {
+		if p.ObjectID == "" || len(p.ObjectID) > maxObjectIDLength {
+			return fmt.Errorf("positions[%d].objectId must be 1 to %d characters", i, maxObjectIDLength)
+		}
+		if p.Lat == nil || p.Lng == nil || !(*p.Lat >= -90 && *p.Lat <= 90) || !(*p.Lng >= -180 && *p.Lng <= 180) {
+			return fmt.Errorf("positions[%d] must have a latitude and a longitude", i)
+		}
+	}
+	return nil
+}
+
+// GeofenceCreate registers a fence, a region or a polygon.
+func (h *Handler) GeofenceCreate(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var body model.GeofenceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.Warn(ctx, "failed to bindJSON", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	hasGeometry := len(body.Geometry) != 0 && string(body.Geometry) != "null"
+	if (body.RegionID != 0) == hasGeometry {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "a fence must have either a regionId or a geometry"))
+		return
+	}
+	if len(body.Name) > 255 {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "name must not be longer than 255 characters"))
+		return
+	}
+
+	geofence := model.Geofence{Name: body.Name, DwellSeconds: body.DwellSeconds}
+	var t geom.T
+	if body.RegionID != 0 {
+		geofence.GeospatialID = &body.RegionID
+	} else {
+		if body.Name == "" {
+			c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "name is required for a geometry"))
+			return
+		}
+
+		var err error
+		if t, err = parseGeofenceGeometry(body.Geometry); err != nil {
+			c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+			return
+		}
+	}
+
+	data, err := h.geofenceService.Create(ctx, geofence, t)
+	if err == service.ErrGeospatialNotFound {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusCreated().StatusCode, result.SetData(data))
+}
+
+// GeofenceList returns every fence.
+func (h *Handler) GeofenceList(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	data, err := h.geofenceService.List(ctx)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
+}
+
+// GeofenceDelete deletes a fence.
+func (h *Handler) GeofenceDelete(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "id must be an integer"))
+		return
+	}
+
+	err = h.geofenceService.Delete(ctx, uint(id))
+	if err == service.ErrGeofenceNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result)
+}
+
+// GeofencePositions takes positions of tracked objects and returns the enter, exit and dwell
+// events they cause.
+func (h *Handler) GeofencePositions(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var body model.GeofencePositionsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.Warn(ctx, "failed to bindJSON", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	if err := validatePositions(body.Positions); err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	data, err := h.geofenceService.Track(ctx, body.Positions, body.Emit)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
+}
+
+// GeofenceObject returns the last known position of a tracked object and the fences it is in.
+func (h *Handler) GeofenceObject(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	data, err := h.geofenceService.Object(ctx, c.Param("objectId"))
+	if err == service.ErrGeofenceObjectNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
+}
@@ -3,7 +3,9 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -11,40 +13,104 @@ import (
 	"github.com/si-bas/go-rest-geospatial/domain/model"
 	"github.com/si-bas/go-rest-geospatial/pkg/logger"
 	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
 	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
 	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+	"github.com/twpayne/go-geom"
 	"github.com/twpayne/go-geom/encoding/geojson"
 )
 
-func validateGeospatialFilter(query model.GeospatialFilterParams) (*model.GeospatialFilter, error) {
-	filter := model.GeospatialFilter{
-		Name: query.Name,
+func parseLatLng(value string) (float64, float64, error) {
+	errMsg := "latlng must contain two float values, divided by commas"
+
+	latLng := strings.Split(value, ",")
+	if len(latLng) != 2 {
+		return 0, 0, errors.New(errMsg)
 	}
 
-	if query.LatLng != "" {
-		errMsg := "latlng must contain two float values, divided by commas"
+	fLat, err := strconv.ParseFloat(latLng[0], 64)
+	if err != nil {
+		return 0, 0, errors.New(errMsg)
+	}
 
-		latLng := strings.Split(query.LatLng, ",")
-		if len(latLng) != 2 {
-			return nil, errors.New(errMsg)
-		}
+	fLng, err := strconv.ParseFloat(latLng[1], 64)
+	if err != nil {
+		return 0, 0, errors.New(errMsg)
+	}
+
+	return fLat, fLng, nil
+}
+
+func parseBbox(value string) ([]float64, error) {
+	errMsg := "bbox must contain four float values minLng,minLat,maxLng,maxLat, divided by commas"
+
+	values := strings.Split(value, ",")
+	if len(values) != 4 {
+		return nil, errors.New(errMsg)
+	}
 
-		fLat, err := strconv.ParseFloat(latLng[0], 64)
+	var bbox []float64
+	for _, str := range values {
+		fVal, err := strconv.ParseFloat(str, 64)
 		if err != nil {
 			return nil, errors.New(errMsg)
 		}
+		bbox = append(bbox, fVal)
+	}
+
+	if bbox[0] > bbox[2] || bbox[1] > bbox[3] {
+		return nil, errors.New(errMsg)
+	}
+
+	return bbox, nil
+}
+
+func validateGeospatialFilter(query model.GeospatialFilterParams) (*model.GeospatialFilter, error) {
+	filter := model.GeospatialFilter{
+		Name: query.Name,
+	}
 
-		fLng, err := strconv.ParseFloat(latLng[1], 64)
+	if query.LatLng != "" {
+		fLat, fLng, err := parseLatLng(query.LatLng)
 		if err != nil {
-			return nil, errors.New(errMsg)
+			return nil, err
 		}
 
-		filter.Lat = fLat
-		filter.Lng = fLng
+		filter.Lat = &fLat
+		filter.Lng = &fLng
 
 		filter.Nested = query.Nested
 	}
 
+	if query.Near != "" {
+		lat, lng, err := parseLatLng(query.Near)
+		if err != nil || !(lat >= -90 && lat <= 90) || !(lng >= -180 && lng <= 180) {
+			return nil, errors.New("near must be a latitude and a longitude, divided by commas")
+		}
+		filter.Near = &model.LatLng{Lat: lat, Lng: lng}
+	}
+
+	if query.MinArea < 0 || query.MaxArea < 0 {
+		return nil, errors.New("minArea and maxArea must not be negative")
+	}
+
+	if query.MaxArea > 0 && query.MinArea > query.MaxArea {
+		return nil, errors.New("minArea must not be greater than maxArea")
+	}
+
+	filter.MinAreaKm2 = query.MinArea
+	filter.MaxAreaKm2 = query.MaxArea
+
+	if query.Bbox != "" {
+		bbox, err := parseBbox(query.Bbox)
+		if err != nil {
+			return nil, err
+		}
+		filter.Bbox = bbox
+	}
+
 	if query.Types != "" {
 		filter.Types = strings.Split(query.Types, ",")
 	}
@@ -108,8 +174,42 @@ func (h *Handler) GeospatialList(c *gin.Context) {
 		return
 	}
 
-	if filter.Lat != 0 && filter.Lng != 0 {
+	shape, err := parseRegionShape(query.Fields, query.Include)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	filter.Columns = shape.columns(nil)
+
+	if query.Q != "" {
+		param := pagination.Param{Limit: query.Limit}
+		data, err := h.geospatialService.Search(ctx, query.Q, *filter, int(param.GetLimit()))
+		if err == nil {
+			data, err = h.geospatialService.Localize(ctx, data, query.Lang)
+		}
+		if err == nil {
+			data, err = h.geospatialService.Include(ctx, data, shape.include)
+		}
+		if err != nil {
+			c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+			return
+		}
+
+		h.renderRegions(c, result, shape, data, nil)
+		return
+	}
+
+	// Only the regions returned are read with their geometry, not every search candidate.
+	filter.WithGeometry = shape.includes(model.IncludeGeometry)
+
+	if filter.Point() != nil {
 		data, err := h.geospatialService.List(ctx, *filter)
+		if err == nil {
+			data, err = h.geospatialService.Localize(ctx, data, query.Lang)
+		}
+		if err == nil {
+			data, err = h.geospatialService.Include(ctx, data, shape.include)
+		}
 		if err != nil {
 			c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
 			return
@@ -123,48 +223,465 @@ func (h *Handler) GeospatialList(c *gin.Context) {
 				}
 			}
 			root := h.geospatialService.BuildTree(data, rootLevel)
-			c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(root))
+			if root == nil || len(shape.fields) == 0 {
+				c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(root))
+				return
+			}
+
+			rendered, err := shape.renderOne(root)
+			if err != nil {
+				c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+				return
+			}
+			c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(rendered))
 			return
 		}
 
-		c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
+		h.renderRegions(c, result, shape, data, nil)
 		return
 	}
 
-	var sortBys []pagination.ParamSort
-	if len(query.Sort) > 0 {
-		for k, v := range query.Sort {
-			sortBys = append(sortBys, pagination.ParamSort{
-				Column: k,
-				Order:  v,
-			})
-		}
+	sortBys, err := parseGeospatialSort(query.Sort, filter)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	filter.Columns = shape.columns(sortBys)
+
+	// A cursor parameter, even an empty one for the first page, switches to cursor mode.
+	_, useCursor := c.GetQuery("cursor")
+	if useCursor && query.Page != 0 {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "page and cursor must not be combined"))
+		return
 	}
 
 	data, meta, err := h.geospatialService.ListPaginate(ctx, *filter, pagination.Param{
-		Limit: query.Limit,
-		Page:  query.Page,
+		Limit:     query.Limit,
+		Page:      query.Page,
+		Sort:      sortBys,
+		UseCursor: useCursor,
+		Cursor:    query.Cursor,
+		SkipTotal: query.WithTotal != nil && !*query.WithTotal,
+	})
+	if err == nil {
+		data, err = h.geospatialService.Localize(ctx, data, query.Lang)
+	}
+	if err == nil {
+		data, err = h.geospatialService.Include(ctx, data, shape.include)
+	}
+	if errors.Is(err, pagination.ErrInvalidCursor) || errors.Is(err, pagination.ErrInvalidSort) {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	h.renderRegions(c, result, shape, data, meta)
+}
+
+// parseGeospatialSort parses the sort parameter, sorting by distance needs a near point.
+func parseGeospatialSort(value string, filter *model.GeospatialFilter) ([]pagination.ParamSort, error) {
+	sortBys, err := pagination.ParseSort(value, model.GeospatialSortFields)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sortBys {
+		if s.Column == "distance_km" && filter.Near == nil {
+			return nil, errors.New("sorting by distance needs near=lat,lng")
+		}
+	}
+	return sortBys, nil
+}
+
+// maxIntersectsVertices caps the size of the geometry an intersection query accepts.
+const maxIntersectsVertices = 10000
+
+// parseIntersectsGeometry reads the GeoJSON geometry of an intersection query, a polygon or a
+// line in longitudes and latitudes.
+func parseIntersectsGeometry(data json.RawMessage) (geom.T, error) {
+	errMsg := "geometry must be a GeoJSON Polygon, MultiPolygon, LineString or MultiLineString"
+
+	if len(data) == 0 {
+		return nil, errors.New("geometry is required")
+	}
+
+	var t geom.T
+	if err := geojson.Unmarshal(data, &t); err != nil {
+		return nil, errors.New(errMsg)
+	}
+
+	switch t.(type) {
+	case *geom.Polygon, *geom.MultiPolygon, *geom.LineString, *geom.MultiLineString:
+	default:
+		return nil, errors.New(errMsg)
+	}
+
+	coords := t.FlatCoords()
+	if len(coords) == 0 {
+		return nil, errors.New("geometry must not be empty")
+	}
+	if len(coords)/t.Stride() > maxIntersectsVertices {
+		return nil, fmt.Errorf("geometry must not have more than %d positions", maxIntersectsVertices)
+	}
+	for i := 0; i < len(coords); i += t.Stride() {
+		if !(coords[i] >= -180 && coords[i] <= 180) || !(coords[i+1] >= -90 && coords[i+1] <= 90) {
+			return nil, errors.New("geometry positions must be a longitude and a latitude")
+		}
+	}
+
+	return t, nil
+}
+
+// validateBodyFilter checks the filter fields of a request body like validateGeospatialFilter
+// checks the query parameters of the list.
+func validateBodyFilter(filter model.GeospatialFilter) (*model.GeospatialFilter, error) {
+	// Nesting only applies to a point query of the list.
+	filter.Nested = false
+
+	if filter.Near != nil && (!(filter.Near.Lat >= -90 && filter.Near.Lat <= 90) || !(filter.Near.Lng >= -180 && filter.Near.Lng <= 180)) {
+		return nil, errors.New("near must be a latitude and a longitude")
+	}
+
+	if filter.MinAreaKm2 < 0 || filter.MaxAreaKm2 < 0 {
+		return nil, errors.New("minArea and maxArea must not be negative")
+	}
+
+	if filter.MaxAreaKm2 > 0 && filter.MinAreaKm2 > filter.MaxAreaKm2 {
+		return nil, errors.New("minArea must not be greater than maxArea")
+	}
+
+	if len(filter.Bbox) != 0 && (len(filter.Bbox) != 4 || filter.Bbox[0] > filter.Bbox[2] || filter.Bbox[1] > filter.Bbox[3]) {
+		return nil, errors.New("bbox must contain four float values minLng,minLat,maxLng,maxLat")
+	}
+
+	if filter.Country != "" {
+		country, err := validateCountry(filter.Country)
+		if err != nil {
+			return nil, err
+		}
+		filter.Country = country
+	}
+
+	return &filter, nil
+}
+
+// GeospatialIntersects returns the regions sharing a point with a posted polygon or line,
+// optionally with how much of it lies in each.
+func (h *Handler) GeospatialIntersects(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var body model.GeospatialIntersectsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.Warn(ctx, "failed to bindJSON", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	t, err := parseIntersectsGeometry(body.Geometry)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	filter, err := validateBodyFilter(body.GeospatialFilter)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	shape, err := parseRegionShape(body.Fields, body.Include)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	sortBys, err := parseGeospatialSort(body.Sort, filter)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	filter.Columns = shape.columns(sortBys)
+	filter.WithGeometry = shape.includes(model.IncludeGeometry)
+
+	data, meta, err := h.geospatialService.Intersecting(ctx, t, *filter, pagination.Param{
+		Limit: body.Limit,
+		Page:  body.Page,
 		Sort:  sortBys,
+	}, body.Overlap)
+	if err == nil {
+		data, err = h.geospatialService.Localize(ctx, data, body.Lang)
+	}
+	if err == nil {
+		data, err = h.geospatialService.Include(ctx, data, shape.include)
+	}
+	if errors.Is(err, pagination.ErrInvalidSort) {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	h.renderRegions(c, result, shape, data, meta)
+}
+
+// GeospatialUnion dissolves the regions picked by ids or a filter into one area, and saves
+// it as a custom area when asked to.
+func (h *Handler) GeospatialUnion(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var body model.GeospatialUnionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.Warn(ctx, "failed to bindJSON", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	filter, err := validateBodyFilter(body.GeospatialFilter)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	// Without any filter every region would be dissolved.
+	if reflect.DeepEqual(*filter, model.GeospatialFilter{}) {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "regions must be picked by ids or a filter"))
+		return
+	}
+
+	saveAs := strings.TrimSpace(body.SaveAs)
+	if len(saveAs) > 255 {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "saveAs must not be longer than 255 characters"))
+		return
+	}
+
+	// Dissolving is a read, saving the union writes a region.
+	if saveAs != "" && !constant.HasRole(shared.GetContextValueAsString(ctx, constant.UserRole), constant.RoleImporter) {
+		c.JSON(result.APIStatusForbidden().StatusCode, result.SetError(response.ErrForbiddenResource, "the importer role is required to save a union"))
+		return
+	}
+
+	union, err := h.geospatialService.Union(ctx, *filter, saveAs)
+	if err == service.ErrGeospatialNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err == service.ErrTooManyRegions || err == service.ErrInvalidCustomName {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(union))
+}
+
+// renderRegions responds with data in the shape the client asked for, and with meta when
+// paginated.
+func (h *Handler) renderRegions(c *gin.Context, result *response.JSONResponse, shape *regionShape, data []model.Geospatial, meta *pagination.Param) {
+	rendered, err := shape.render(data)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	result.SetData(rendered)
+	if meta != nil {
+		result.SetMeta(meta)
+	}
+	c.JSON(result.APIStatusSuccess().StatusCode, result)
+}
+
+func (h *Handler) GeospatialDetail(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "id must be an integer"))
+		return
+	}
+
+	var query model.GeospatialDetailParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	shape, err := parseRegionShape(query.Fields, query.Include)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	geospatial, err := h.geospatialService.Get(ctx, uint(id), model.GeospatialFilter{
+		Columns:      shape.columns(nil),
+		WithGeometry: shape.includes(model.IncludeGeometry),
+	})
+	if err == service.ErrGeospatialNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	var data []model.Geospatial
+	if err == nil {
+		data, err = h.geospatialService.Localize(ctx, []model.Geospatial{*geospatial}, query.Lang)
+	}
+	if err == nil {
+		data, err = h.geospatialService.Include(ctx, data, shape.include)
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	if len(shape.fields) == 0 {
+		c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(&data[0]))
+		return
+	}
+
+	rendered, err := shape.renderOne(&data[0])
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(rendered))
+}
+
+// GeospatialDelete removes a custom area, regions imported from GADM cannot be removed.
+func (h *Handler) GeospatialDelete(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "id must be an integer"))
+		return
+	}
+
+	err = h.geospatialService.DeleteCustom(ctx, uint(id))
+	if err == service.ErrGeospatialNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err == service.ErrNotCustomArea {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result)
+}
+
+// GeospatialNeighbors returns the regions of the same level bordering a region, longest
+// border first.
+func (h *Handler) GeospatialNeighbors(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "id must be an integer"))
+		return
+	}
+
+	var query model.GeospatialDetailParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	shape, err := parseRegionShape(query.Fields, query.Include)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	data, err := h.geospatialService.Neighbors(ctx, uint(id), model.GeospatialFilter{
+		Columns:      shape.columns(nil),
+		WithGeometry: shape.includes(model.IncludeGeometry),
 	})
+	if err == service.ErrGeospatialNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err == nil {
+		data, err = h.geospatialService.Localize(ctx, data, query.Lang)
+	}
+	if err == nil {
+		data, err = h.geospatialService.Include(ctx, data, shape.include)
+	}
 	if err != nil {
 		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
 		return
 	}
 
-	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data).SetMeta(meta))
+	h.renderRegions(c, result, shape, data, nil)
+}
+
+// validateGeospatialScope turns the scope of the metadata endpoints into a filter. A country
+// scope includes the country itself, a parent scope only the regions below the parent.
+func validateGeospatialScope(query model.GeospatialScopeParams) (*model.GeospatialFilter, error) {
+	filter := model.GeospatialFilter{AncestorID: query.ParentID}
+
+	if query.Country != "" {
+		country, err := validateCountry(query.Country)
+		if err != nil {
+			return nil, err
+		}
+		filter.Country = country
+	}
+
+	return &filter, nil
+}
+
+// validateCountry returns the upper cased GADM country code.
+func validateCountry(value string) (string, error) {
+	country := strings.ToUpper(value)
+	if len(country) != 3 || strings.IndexFunc(country, func(r rune) bool {
+		return (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	}) >= 0 {
+		return "", errors.New("country must be a three letter GADM country code, e.g. IDN")
+	}
+	return country, nil
 }
 
 func (h *Handler) GeospatialTypes(c *gin.Context) {
 	ctx := c.Request.Context()
 	result := response.NewJSONResponse(ctx)
 
-	data, err := h.geospatialService.GetTypes(ctx)
+	var query model.GeospatialScopeParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	filter, err := validateGeospatialScope(query)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	data, err := h.geospatialService.GetTypes(ctx, *filter)
 	if err != nil {
 		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
 		return
 	}
 	if data == nil {
-		data = make([]string, 0)
+		data = make([]model.GeospatialType, 0)
 	}
 
 	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
@@ -174,13 +691,26 @@ func (h *Handler) GeospatialLevels(c *gin.Context) {
 	ctx := c.Request.Context()
 	result := response.NewJSONResponse(ctx)
 
-	data, err := h.geospatialService.GetLevels(ctx)
+	var query model.GeospatialScopeParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	filter, err := validateGeospatialScope(query)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	data, err := h.geospatialService.GetLevels(ctx, *filter)
 	if err != nil {
 		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
 		return
 	}
 	if data == nil {
-		data = make([]uint, 0)
+		data = make([]model.GeospatialLevel, 0)
 	}
 
 	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
@@ -190,6 +720,13 @@ func (h *Handler) GeospatialImport(c *gin.Context) {
 	ctx := c.Request.Context()
 	result := response.NewJSONResponse(ctx)
 
+	if layerName := c.PostForm("layer"); layerName != "" {
+		if err := validateLayerName(layerName); err != nil {
+			c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+			return
+		}
+	}
+
 	file, err := c.FormFile("file")
 	if err != nil {
 		logger.Warn(ctx, "failed to get file from request", tag.Err(err))
@@ -219,6 +756,12 @@ func (h *Handler) GeospatialImport(c *gin.Context) {
 		return
 	}
 
+	// A layer name imports the features into that layer rather than as GADM regions.
+	if layerName := c.PostForm("layer"); layerName != "" {
+		h.importLayer(c, result, layerName, &features)
+		return
+	}
+
 	if err := h.geospatialService.CreateFromFeatureCollection(ctx, &features); err != nil {
 		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
 		return
@@ -4,12 +4,27 @@ import "github.com/si-bas/go-rest-geospatial/service"
 
 type Handler struct {
 	geospatialService service.GeospatialService
+	exportService     service.ExportService
+	layerService      service.LayerService
+	geofenceService   service.GeofenceService
+	webhookService    service.WebhookService
+	authService       service.AuthService
 }
 
 func New(
 	geospatialService service.GeospatialService,
+	exportService service.ExportService,
+	layerService service.LayerService,
+	geofenceService service.GeofenceService,
+	webhookService service.WebhookService,
+	authService service.AuthService,
 ) *Handler {
 	return &Handler{
 		geospatialService: geospatialService,
+		exportService:     exportService,
+		layerService:      layerService,
+		geofenceService:   geofenceService,
+		webhookService:    webhookService,
+		authService:       authService,
 	}
 }
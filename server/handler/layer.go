@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+	"github.com/twpayne/go-geom/encoding/geojson"
+)
+
+// maxLayerNameLength caps the length of a layer name.
+const maxLayerNameLength = 100
+
+func validateLayerName(name string) error {
+	if name == "" || len(name) > maxLayerNameLength || strings.IndexFunc(name, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_'
+	}) >= 0 {
+		return fmt.Errorf("layer must be 1 to %d letters, digits, dashes or underscores", maxLayerNameLength)
+	}
+	return nil
+}
+
+// parseLayerInclude reads the include parameter of the features of a layer, which may only
+// ask for their geometry.
+func parseLayerInclude(value string) (bool, error) {
+	switch value {
+	case "":
+		return false, nil
+	case model.IncludeGeometry:
+		return true, nil
+	}
+	return false, errors.New("include must be geometry")
+}
+
+func (h *Handler) renderLayerFeatures(c *gin.Context, result *response.JSONResponse, data []model.LayerFeature, meta *pagination.Param, err error) {
+	if err == service.ErrLayerNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	result.SetData(data)
+	result.SetMeta(meta)
+	c.JSON(result.APIStatusSuccess().StatusCode, result)
+}
+
+// importLayer imports features into the layer with the given name and returns the layer.
+func (h *Handler) importLayer(c *gin.Context, result *response.JSONResponse, name string, features *geojson.FeatureCollection) {
+	layer, err := h.layerService.CreateFromFeatureCollection(c.Request.Context(), name, features)
+	if errors.Is(err, service.ErrInvalidLayerFeature) {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(layer))
+}
+
+// LayerList returns every layer and the number of its features.
+func (h *Handler) LayerList(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	layers, err := h.layerService.List(ctx)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(layers))
+}
+
+// LayerDelete deletes a layer and its features.
+func (h *Handler) LayerDelete(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	err := h.layerService.Delete(ctx, c.Param("name"))
+	if err == service.ErrLayerNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result)
+}
+
+// LayerFeatures returns the features of a layer, filtered by a point or a bbox like the list
+// of regions.
+func (h *Handler) LayerFeatures(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var query model.LayerFeatureParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	var filter model.LayerFeatureFilter
+	if query.LatLng != "" {
+		lat, lng, err := parseLatLng(query.LatLng)
+		if err != nil {
+			c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+			return
+		}
+		filter.Point = &model.LatLng{Lat: lat, Lng: lng}
+	}
+
+	if query.Bbox != "" {
+		bbox, err := parseBbox(query.Bbox)
+		if err != nil {
+			c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+			return
+		}
+		filter.Bbox = bbox
+	}
+
+	withGeometry, err := parseLayerInclude(query.Include)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+	filter.WithGeometry = withGeometry
+
+	data, meta, err := h.layerService.Features(ctx, c.Param("name"), filter, pagination.Param{
+		Limit:     query.Limit,
+		Page:      query.Page,
+		SkipTotal: query.WithTotal != nil && !*query.WithTotal,
+	})
+	h.renderLayerFeatures(c, result, data, meta, err)
+}
+
+// LayerIntersects returns the features of a layer sharing a point with a posted polygon or
+// line.
+func (h *Handler) LayerIntersects(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var body model.LayerIntersectsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.Warn(ctx, "failed to bindJSON", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	t, err := parseIntersectsGeometry(body.Geometry)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	if len(body.Bbox) != 0 && (len(body.Bbox) != 4 || body.Bbox[0] > body.Bbox[2] || body.Bbox[1] > body.Bbox[3]) {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, "bbox must contain four float values minLng,minLat,maxLng,maxLat"))
+		return
+	}
+
+	withGeometry, err := parseLayerInclude(body.Include)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	data, meta, err := h.layerService.Intersecting(ctx, c.Param("name"), t, model.LayerFeatureFilter{
+		Bbox:         body.Bbox,
+		WithGeometry: withGeometry,
+	}, pagination.Param{
+		Limit: body.Limit,
+		Page:  body.Page,
+	})
+	h.renderLayerFeatures(c, result, data, meta, err)
+}
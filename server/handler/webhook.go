@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+)
+
+// maxWebhookURLLength is the length of the url column.
+const maxWebhookURLLength = 2048
+
+func validateWebhook(req model.WebhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if len(req.URL) > maxWebhookURLLength {
+		return fmt.Errorf("url must not be longer than %d characters", maxWebhookURLLength)
+	}
+
+	for _, e := range req.Events {
+		if !containsString(constant.EventTypes, e) {
+			return fmt.Errorf("events must be some of %s", strings.Join(constant.EventTypes, ", "))
+		}
+	}
+	return nil
+}
+
+func parseWebhookID(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, errors.New("id must be an integer")
+	}
+	return uint(id), nil
+}
+
+// WebhookCreate registers a webhook, the response holds the secret signing its deliveries.
+func (h *Handler) WebhookCreate(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	var body model.WebhookRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.Warn(ctx, "failed to bindJSON", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	if err := validateWebhook(body); err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	data, err := h.webhookService.Create(ctx, body)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusCreated().StatusCode, result.SetData(data))
+}
+
+// WebhookList returns every webhook.
+func (h *Handler) WebhookList(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	data, err := h.webhookService.List(ctx)
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result.SetData(data))
+}
+
+// WebhookDelete deletes a webhook.
+func (h *Handler) WebhookDelete(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	id, err := parseWebhookID(c)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	err = h.webhookService.Delete(ctx, id)
+	if err == service.ErrWebhookNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(result.APIStatusSuccess().StatusCode, result)
+}
+
+// WebhookDeliveries returns the delivery log of a webhook, the latest first.
+func (h *Handler) WebhookDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := response.NewJSONResponse(ctx)
+
+	id, err := parseWebhookID(c)
+	if err != nil {
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	var query model.WebhookDeliveryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn(ctx, "failed to bindQuery", tag.Err(err))
+		c.JSON(result.APIStatusBadRequest().StatusCode, result.SetError(response.ErrBadRequest, err.Error()))
+		return
+	}
+
+	data, meta, err := h.webhookService.Deliveries(ctx, id, pagination.Param{Limit: query.Limit, Page: query.Page})
+	if err == service.ErrWebhookNotFound {
+		c.JSON(result.APIStatusNotFound().StatusCode, result.SetError(response.ErrNotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+		return
+	}
+
+	result.SetData(data)
+	result.SetMeta(meta)
+	c.JSON(result.APIStatusSuccess().StatusCode, result)
+}
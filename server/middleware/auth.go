@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+)
+
+// Authenticate reads the bearer token of the Authorization header, an API key or a JWT, and
+// puts the user and the role it was issued to in the request context. A request without the
+// header goes on anonymously, one with invalid credentials is rejected. When rateLimitService
+// is set, the invalid credentials of a client IP are limited like its anonymous requests and
+// the client gets a 429 without its credentials being looked up once it has run out.
+func Authenticate(authService service.AuthService, rateLimitService service.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(constant.AuthorizationHeader)
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		result := response.NewJSONResponse(ctx)
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			unauthorized(c, result, "authorization must be a bearer token")
+			return
+		}
+
+		if rateLimitService != nil {
+			if limit := rateLimitService.AllowAuth(c.ClientIP()); !limit.Allowed {
+				tooManyRequests(c, result, limit)
+				return
+			}
+		}
+
+		principal, err := authService.Authenticate(ctx, strings.TrimSpace(token))
+		if err == service.ErrInvalidCredentials {
+			if rateLimitService != nil {
+				rateLimitService.FailAuth(c.ClientIP())
+			}
+			unauthorized(c, result, err.Error())
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(result.APIInternalServerError().StatusCode, result.SetError(response.ErrInternalServerError, err.Error()))
+			return
+		}
+
+		ctx = context.WithValue(ctx, constant.UserID, principal.UserID)
+		ctx = context.WithValue(ctx, constant.UserRole, principal.Role)
+		ctx = context.WithValue(ctx, constant.UserRateLimit, principal.RateLimit)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequireRole rejects the requests not made with role or a more privileged one.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		result := response.NewJSONResponse(ctx)
+
+		userRole := shared.GetContextValueAsString(ctx, constant.UserRole)
+		if userRole == "" {
+			unauthorized(c, result, "authentication is required")
+			return
+		}
+		if !constant.HasRole(userRole, role) {
+			c.AbortWithStatusJSON(result.APIStatusForbidden().StatusCode, result.SetError(response.ErrForbiddenResource, "the "+role+" role is required"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func unauthorized(c *gin.Context, result *response.JSONResponse, msg string) {
+	c.Header("WWW-Authenticate", `Bearer realm="geospatial"`)
+	c.AbortWithStatusJSON(result.APIStatusUnauthorized().StatusCode, result.SetError(response.ErrUnauthorized, msg))
+}
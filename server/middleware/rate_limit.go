@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/si-bas/go-rest-geospatial/pkg/ratelimit"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+)
+
+// RateLimit spends the cost of the route of every request for its client, the API key or JWT
+// subject it was authenticated as or its IP, and rejects it with a 429 when the client has
+// run out. The state of the limit is sent in the RateLimit-* headers.
+func RateLimit(rateLimitService service.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		limit := rateLimitService.Take(ctx, c.Request.Method+" "+c.FullPath(), c.ClientIP())
+		if limit.Limit > 0 {
+			setRateLimitHeaders(c, limit)
+		}
+		if !limit.Allowed {
+			tooManyRequests(c, response.NewJSONResponse(ctx), limit)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func tooManyRequests(c *gin.Context, result *response.JSONResponse, limit ratelimit.Result) {
+	c.Header(constant.RetryAfterHeader, strconv.Itoa(int(limit.RetryAfter/time.Second)))
+	c.AbortWithStatusJSON(result.APIStatusTooManyRequests().StatusCode, result.SetError(response.ErrTooManyRequests,
+		fmt.Sprintf("rate limit exceeded, retry in %s", limit.RetryAfter)))
+}
+
+func setRateLimitHeaders(c *gin.Context, limit ratelimit.Result) {
+	c.Header(constant.RateLimitLimitHeader, strconv.Itoa(limit.Limit))
+	c.Header(constant.RateLimitRemainingHeader, strconv.Itoa(limit.Remaining))
+	c.Header(constant.RateLimitResetHeader, strconv.Itoa(int(limit.Reset/time.Second)))
+	c.Header(constant.RateLimitPolicyHeader, limit.Policy)
+}
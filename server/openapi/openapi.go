@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+)
+
+// Spec is the OpenAPI 3 document of the HTTP API. Every route of server.Start must be in it,
+// a test checks they match.
+//
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed index.html
+var indexHTML []byte
+
+// SpecHandler serves Spec.
+func SpecHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", Spec)
+}
+
+// DocsHandler serves Swagger UI over Spec, it is routed as a catch-all on filepath. The
+// assets are bundled in the binary, the UI works offline.
+func DocsHandler(c *gin.Context) {
+	file := c.Param("filepath")
+	if file == "/" || file == "/index.html" {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", indexHTML)
+		return
+	}
+
+	c.FileFromFS(file, swaggerFiles.HTTP)
+}
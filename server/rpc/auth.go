@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authenticate reads the bearer token of the authorization metadata like the Authenticate
+// middleware reads the header, and requires role when it is set. A call without credentials
+// goes on anonymously when no role is required. Invalid credentials are limited per client IP
+// by rateLimitService when it is set, like they are over HTTP.
+func authenticate(ctx context.Context, authService service.AuthService, role string, rateLimitService service.RateLimitService) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(constant.AuthorizationHeader); len(values) > 0 {
+			header = values[0]
+		}
+	}
+	if header == "" {
+		if role != "" {
+			return nil, status.Error(codes.Unauthenticated, "authentication is required")
+		}
+		return ctx, nil
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	if rateLimitService != nil {
+		if limit := rateLimitService.AllowAuth(clientIP(ctx)); !limit.Allowed {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %s", limit.RetryAfter)
+		}
+	}
+
+	principal, err := authService.Authenticate(ctx, strings.TrimSpace(token))
+	if err == service.ErrInvalidCredentials {
+		if rateLimitService != nil {
+			rateLimitService.FailAuth(clientIP(ctx))
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if role != "" && !constant.HasRole(principal.Role, role) {
+		return nil, status.Errorf(codes.PermissionDenied, "the %s role is required", role)
+	}
+
+	ctx = context.WithValue(ctx, constant.UserID, principal.UserID)
+	ctx = context.WithValue(ctx, constant.UserRole, principal.Role)
+	return context.WithValue(ctx, constant.UserRateLimit, principal.RateLimit), nil
+}
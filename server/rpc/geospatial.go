@@ -0,0 +1,403 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/server/rpc/geospatialv1"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxBatchReversePoints caps the points of one BatchReverse.
+	maxBatchReversePoints = 1000
+	// maxListLimit caps the regions of one List page, StreamList sends more.
+	maxListLimit = 1000
+	// streamBatch is how many regions a stream reads at once.
+	streamBatch = 500
+)
+
+// GeospatialServer serves the regions of geospatialService over gRPC.
+type GeospatialServer struct {
+	geospatialv1.UnimplementedGeospatialServiceServer
+
+	geospatialService service.GeospatialService
+}
+
+func NewGeospatialServer(geospatialService service.GeospatialService) *GeospatialServer {
+	return &GeospatialServer{geospatialService: geospatialService}
+}
+
+func (s *GeospatialServer) List(ctx context.Context, req *geospatialv1.ListRequest) (*geospatialv1.ListResponse, error) {
+	filter, sortBys, err := validateListRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Cursor != nil && req.Page != 0 {
+		return nil, status.Error(codes.InvalidArgument, "page and cursor must not be combined")
+	}
+
+	limit := req.Limit
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	data, meta, err := s.geospatialService.ListPaginate(ctx, *filter, pagination.Param{
+		Limit:     uint(limit),
+		Page:      uint(req.Page),
+		Sort:      sortBys,
+		UseCursor: req.Cursor != nil,
+		Cursor:    req.GetCursor(),
+		SkipTotal: req.SkipTotal,
+	})
+	if err == nil {
+		data, err = s.complete(ctx, data, req.Lang, req.WithGeometry, req.WithParent)
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &geospatialv1.ListResponse{
+		Regions: toRegions(data),
+		Meta: &geospatialv1.Meta{
+			Limit:      uint32(meta.Limit),
+			Page:       uint32(meta.Page),
+			TotalRows:  meta.TotalRows,
+			TotalPages: uint32(meta.TotalPages),
+			NextCursor: meta.NextCursor,
+		},
+	}, nil
+}
+
+func (s *GeospatialServer) StreamList(req *geospatialv1.ListRequest, stream geospatialv1.GeospatialService_StreamListServer) error {
+	filter, sortBys, err := validateListRequest(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.stream(stream.Context(), *filter, sortBys, req.Lang, req.WithParent, stream.Send)
+}
+
+// stream sends every region matching filter, read streamBatch at a time by cursor.
+func (s *GeospatialServer) stream(ctx context.Context, filter model.GeospatialFilter, sortBys []pagination.ParamSort, lang string, withParent bool, send func(*geospatialv1.Region) error) error {
+	param := pagination.Param{Limit: streamBatch, Sort: sortBys, UseCursor: true, SkipTotal: true}
+	for {
+		data, meta, err := s.geospatialService.ListPaginate(ctx, filter, param)
+		if err == nil {
+			data, err = s.complete(ctx, data, lang, filter.WithGeometry, withParent)
+		}
+		if err != nil {
+			return statusError(err)
+		}
+
+		for i := range data {
+			if err := send(toRegion(&data[i])); err != nil {
+				return err
+			}
+		}
+		if meta.NextCursor == "" {
+			return nil
+		}
+		param.Cursor = meta.NextCursor
+	}
+}
+
+func (s *GeospatialServer) Reverse(ctx context.Context, req *geospatialv1.ReverseRequest) (*geospatialv1.ReverseResponse, error) {
+	if err := validatePoint(req.Point); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "point: "+err.Error())
+	}
+
+	return s.reverse(ctx, req.Point, req.Levels, req.Types, req.Lang, req.WithGeometry, req.WithParent)
+}
+
+func (s *GeospatialServer) BatchReverse(ctx context.Context, req *geospatialv1.BatchReverseRequest) (*geospatialv1.BatchReverseResponse, error) {
+	if len(req.Points) == 0 || len(req.Points) > maxBatchReversePoints {
+		return nil, status.Errorf(codes.InvalidArgument, "points must contain 1 to %d points", maxBatchReversePoints)
+	}
+	for i, p := range req.Points {
+		if err := validatePoint(p); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "points[%d]: %s", i, err)
+		}
+	}
+
+	results := make([]*geospatialv1.ReverseResponse, 0, len(req.Points))
+	for _, p := range req.Points {
+		result, err := s.reverse(ctx, p, req.Levels, req.Types, req.Lang, req.WithGeometry, req.WithParent)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return &geospatialv1.BatchReverseResponse{Results: results}, nil
+}
+
+// reverse returns the regions containing point, the lowest level first.
+func (s *GeospatialServer) reverse(ctx context.Context, point *geospatialv1.LatLng, levels []uint32, types []string, lang string, withGeometry, withParent bool) (*geospatialv1.ReverseResponse, error) {
+	data, err := s.geospatialService.List(ctx, model.GeospatialFilter{
+		Lat:          &point.Lat,
+		Lng:          &point.Lng,
+		Levels:       toUints(levels),
+		Types:        types,
+		WithGeometry: withGeometry,
+	})
+	if err == nil {
+		data, err = s.complete(ctx, data, lang, withGeometry, withParent)
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	sort.SliceStable(data, func(i, j int) bool { return data[i].Level < data[j].Level })
+	return &geospatialv1.ReverseResponse{Regions: toRegions(data)}, nil
+}
+
+func (s *GeospatialServer) GetRegion(ctx context.Context, req *geospatialv1.GetRegionRequest) (*geospatialv1.Region, error) {
+	geospatial, err := s.geospatialService.Get(ctx, uint(req.Id), model.GeospatialFilter{WithGeometry: req.WithGeometry})
+	var data []model.Geospatial
+	if err == nil {
+		data, err = s.complete(ctx, []model.Geospatial{*geospatial}, req.Lang, req.WithGeometry, req.WithParent)
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toRegion(&data[0]), nil
+}
+
+func (s *GeospatialServer) Children(req *geospatialv1.ChildrenRequest, stream geospatialv1.GeospatialService_ChildrenServer) error {
+	ctx := stream.Context()
+	if _, err := s.geospatialService.Get(ctx, uint(req.Id), model.GeospatialFilter{Columns: []string{"id"}}); err != nil {
+		return statusError(err)
+	}
+
+	filter := model.GeospatialFilter{ParentIds: []uint{uint(req.Id)}, WithGeometry: req.WithGeometry}
+	return s.stream(ctx, filter, nil, req.Lang, false, stream.Send)
+}
+
+func (s *GeospatialServer) Types(ctx context.Context, req *geospatialv1.ScopeRequest) (*geospatialv1.TypesResponse, error) {
+	filter, err := validateScope(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data, err := s.geospatialService.GetTypes(ctx, *filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	res := &geospatialv1.TypesResponse{Types: make([]*geospatialv1.RegionType, 0, len(data))}
+	for _, t := range data {
+		regionType := &geospatialv1.RegionType{EngType: t.EngType, Count: t.Count, Countries: toCountries(t.Countries)}
+		for _, l := range t.Levels {
+			regionType.Levels = append(regionType.Levels, &geospatialv1.TypeLevel{Level: uint32(l.Level), Count: l.Count, LocalTypes: l.LocalTypes})
+		}
+		res.Types = append(res.Types, regionType)
+	}
+	return res, nil
+}
+
+func (s *GeospatialServer) Levels(ctx context.Context, req *geospatialv1.ScopeRequest) (*geospatialv1.LevelsResponse, error) {
+	filter, err := validateScope(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data, err := s.geospatialService.GetLevels(ctx, *filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	res := &geospatialv1.LevelsResponse{Levels: make([]*geospatialv1.Level, 0, len(data))}
+	for _, l := range data {
+		level := &geospatialv1.Level{Level: uint32(l.Level), Count: l.Count, Countries: toCountries(l.Countries)}
+		for _, t := range l.Types {
+			level.Types = append(level.Types, &geospatialv1.LevelType{Type: t.Type, EngType: t.EngType, Count: t.Count})
+		}
+		for _, e := range l.Examples {
+			level.Examples = append(level.Examples, &geospatialv1.LevelExample{Id: uint64(e.ID), Name: e.Name, Type: e.Type, AreaKm2: e.AreaKm2})
+		}
+		res.Levels = append(res.Levels, level)
+	}
+	return res, nil
+}
+
+// complete localizes data to lang and adds the geometry and the parent when asked for.
+func (s *GeospatialServer) complete(ctx context.Context, data []model.Geospatial, lang string, withGeometry, withParent bool) ([]model.Geospatial, error) {
+	var include []string
+	if withGeometry {
+		include = append(include, model.IncludeGeometry)
+	}
+	if withParent {
+		include = append(include, model.IncludeParent)
+	}
+
+	data, err := s.geospatialService.Localize(ctx, data, lang)
+	if err != nil {
+		return nil, err
+	}
+	return s.geospatialService.Include(ctx, data, include)
+}
+
+// statusError returns the gRPC status of an error of the service.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrGeospatialNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, pagination.ErrInvalidCursor), errors.Is(err, pagination.ErrInvalidSort):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func validateListRequest(req *geospatialv1.ListRequest) (*model.GeospatialFilter, []pagination.ParamSort, error) {
+	filter := model.GeospatialFilter{
+		Name:         req.Name,
+		Types:        req.Types,
+		Levels:       toUints(req.Levels),
+		ParentIds:    toUints(req.ParentIds),
+		ExcludedIds:  toUints(req.ExcludedIds),
+		MinAreaKm2:   req.MinArea,
+		MaxAreaKm2:   req.MaxArea,
+		WithGeometry: req.WithGeometry,
+	}
+
+	if req.Near != nil {
+		if err := validatePoint(req.Near); err != nil {
+			return nil, nil, errors.New("near: " + err.Error())
+		}
+		filter.Near = &model.LatLng{Lat: req.Near.Lat, Lng: req.Near.Lng}
+	}
+
+	if req.MinArea < 0 || req.MaxArea < 0 {
+		return nil, nil, errors.New("min_area and max_area must not be negative")
+	}
+	if req.MaxArea > 0 && req.MinArea > req.MaxArea {
+		return nil, nil, errors.New("min_area must not be greater than max_area")
+	}
+
+	if len(req.Bbox) > 0 {
+		if len(req.Bbox) != 4 || req.Bbox[0] > req.Bbox[2] || req.Bbox[1] > req.Bbox[3] {
+			return nil, nil, errors.New("bbox must contain four values min lng, min lat, max lng, max lat")
+		}
+		filter.Bbox = req.Bbox
+	}
+
+	sortBys, err := pagination.ParseSort(req.Sort, model.GeospatialSortFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range sortBys {
+		if s.Column == "distance_km" && filter.Near == nil {
+			return nil, nil, errors.New("sorting by distance needs a near point")
+		}
+	}
+
+	return &filter, sortBys, nil
+}
+
+// validatePoint checks the range of p.
+func validatePoint(p *geospatialv1.LatLng) error {
+	if p == nil {
+		return errors.New("lat and lng are required")
+	}
+	if !(p.Lat >= -90 && p.Lat <= 90) || !(p.Lng >= -180 && p.Lng <= 180) {
+		return errors.New("lat must be within -90 and 90, lng within -180 and 180")
+	}
+	return nil
+}
+
+// validateScope turns the scope of the metadata calls into a filter, like the REST API.
+func validateScope(req *geospatialv1.ScopeRequest) (*model.GeospatialFilter, error) {
+	filter := model.GeospatialFilter{AncestorID: uint(req.ParentId)}
+
+	if req.Country != "" {
+		country := strings.ToUpper(req.Country)
+		if len(country) != 3 || strings.IndexFunc(country, func(r rune) bool {
+			return (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+		}) >= 0 {
+			return nil, errors.New("country must be a three letter GADM country code, e.g. IDN")
+		}
+		filter.Country = country
+	}
+
+	return &filter, nil
+}
+
+func toRegions(data []model.Geospatial) []*geospatialv1.Region {
+	regions := make([]*geospatialv1.Region, 0, len(data))
+	for i := range data {
+		regions = append(regions, toRegion(&data[i]))
+	}
+	return regions
+}
+
+func toRegion(g *model.Geospatial) *geospatialv1.Region {
+	region := &geospatialv1.Region{
+		Id:           uint64(g.ID),
+		Name:         g.Name,
+		Type:         g.Type,
+		EngType:      g.EngType,
+		Level:        uint32(g.Level),
+		AreaKm2:      g.AreaKm2,
+		PerimeterKm:  g.PerimeterKm,
+		DistanceKm:   g.DistanceKm,
+		Centroid:     toLatLng(g.Centroid()),
+		LabelPoint:   toLatLng(g.LabelPoint()),
+		Bbox:         g.Bbox(),
+		Geometry:     string(g.Shape),
+		OfficialName: g.OfficialName,
+	}
+	if g.Parent != nil {
+		region.Parent = &geospatialv1.RegionRef{
+			Id:      uint64(g.Parent.ID),
+			Name:    g.Parent.Name,
+			Type:    g.Parent.Type,
+			EngType: g.Parent.EngType,
+			Level:   uint32(g.Parent.Level),
+		}
+	}
+	if !g.CreatedAt.IsZero() {
+		region.CreatedAt = timestamppb.New(g.CreatedAt)
+	}
+	if !g.UpdatedAt.IsZero() {
+		region.UpdatedAt = timestamppb.New(g.UpdatedAt)
+	}
+	return region
+}
+
+func toLatLng(p *model.LatLng) *geospatialv1.LatLng {
+	if p == nil {
+		return nil
+	}
+	return &geospatialv1.LatLng{Lat: p.Lat, Lng: p.Lng}
+}
+
+func toCountries(countries []model.GeospatialCountry) []*geospatialv1.Country {
+	res := make([]*geospatialv1.Country, 0, len(countries))
+	for _, c := range countries {
+		res = append(res, &geospatialv1.Country{Code: c.Code, Name: c.Name})
+	}
+	return res
+}
+
+func toUints[T uint32 | uint64](values []T) []uint {
+	if len(values) == 0 {
+		return nil
+	}
+	res := make([]uint, 0, len(values))
+	for _, v := range values {
+		res = append(res, uint(v))
+	}
+	return res
+}
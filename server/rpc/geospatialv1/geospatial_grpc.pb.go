@@ -0,0 +1,439 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: geospatial/v1/geospatial.proto
+
+package geospatialv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	GeospatialService_List_FullMethodName         = "/geospatial.v1.GeospatialService/List"
+	GeospatialService_StreamList_FullMethodName   = "/geospatial.v1.GeospatialService/StreamList"
+	GeospatialService_Reverse_FullMethodName      = "/geospatial.v1.GeospatialService/Reverse"
+	GeospatialService_BatchReverse_FullMethodName = "/geospatial.v1.GeospatialService/BatchReverse"
+	GeospatialService_GetRegion_FullMethodName    = "/geospatial.v1.GeospatialService/GetRegion"
+	GeospatialService_Children_FullMethodName     = "/geospatial.v1.GeospatialService/Children"
+	GeospatialService_Types_FullMethodName        = "/geospatial.v1.GeospatialService/Types"
+	GeospatialService_Levels_FullMethodName       = "/geospatial.v1.GeospatialService/Levels"
+)
+
+// GeospatialServiceClient is the client API for GeospatialService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GeospatialServiceClient interface {
+	// List returns a page of the regions matching the filter, like GET /v1/q.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// StreamList sends every region matching the filter, ignoring limit, page and cursor.
+	StreamList(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (GeospatialService_StreamListClient, error)
+	// Reverse returns the regions containing a point, the country first, like GET /v1/q?latlng=.
+	Reverse(ctx context.Context, in *ReverseRequest, opts ...grpc.CallOption) (*ReverseResponse, error)
+	// BatchReverse reverse geocodes up to 1000 points, the results are in the order of the points.
+	BatchReverse(ctx context.Context, in *BatchReverseRequest, opts ...grpc.CallOption) (*BatchReverseResponse, error)
+	// GetRegion returns a region by its id, like GET /v1/regions/:id.
+	GetRegion(ctx context.Context, in *GetRegionRequest, opts ...grpc.CallOption) (*Region, error)
+	// Children sends every direct child of a region.
+	Children(ctx context.Context, in *ChildrenRequest, opts ...grpc.CallOption) (GeospatialService_ChildrenClient, error)
+	// Types returns the normalized region types, like GET /v1/types.
+	Types(ctx context.Context, in *ScopeRequest, opts ...grpc.CallOption) (*TypesResponse, error)
+	// Levels returns the levels of the hierarchy, like GET /v1/levels.
+	Levels(ctx context.Context, in *ScopeRequest, opts ...grpc.CallOption) (*LevelsResponse, error)
+}
+
+type geospatialServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGeospatialServiceClient(cc grpc.ClientConnInterface) GeospatialServiceClient {
+	return &geospatialServiceClient{cc}
+}
+
+func (c *geospatialServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, GeospatialService_List_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geospatialServiceClient) StreamList(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (GeospatialService_StreamListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GeospatialService_ServiceDesc.Streams[0], GeospatialService_StreamList_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &geospatialServiceStreamListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GeospatialService_StreamListClient interface {
+	Recv() (*Region, error)
+	grpc.ClientStream
+}
+
+type geospatialServiceStreamListClient struct {
+	grpc.ClientStream
+}
+
+func (x *geospatialServiceStreamListClient) Recv() (*Region, error) {
+	m := new(Region)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *geospatialServiceClient) Reverse(ctx context.Context, in *ReverseRequest, opts ...grpc.CallOption) (*ReverseResponse, error) {
+	out := new(ReverseResponse)
+	err := c.cc.Invoke(ctx, GeospatialService_Reverse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geospatialServiceClient) BatchReverse(ctx context.Context, in *BatchReverseRequest, opts ...grpc.CallOption) (*BatchReverseResponse, error) {
+	out := new(BatchReverseResponse)
+	err := c.cc.Invoke(ctx, GeospatialService_BatchReverse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geospatialServiceClient) GetRegion(ctx context.Context, in *GetRegionRequest, opts ...grpc.CallOption) (*Region, error) {
+	out := new(Region)
+	err := c.cc.Invoke(ctx, GeospatialService_GetRegion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geospatialServiceClient) Children(ctx context.Context, in *ChildrenRequest, opts ...grpc.CallOption) (GeospatialService_ChildrenClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GeospatialService_ServiceDesc.Streams[1], GeospatialService_Children_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &geospatialServiceChildrenClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GeospatialService_ChildrenClient interface {
+	Recv() (*Region, error)
+	grpc.ClientStream
+}
+
+type geospatialServiceChildrenClient struct {
+	grpc.ClientStream
+}
+
+func (x *geospatialServiceChildrenClient) Recv() (*Region, error) {
+	m := new(Region)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *geospatialServiceClient) Types(ctx context.Context, in *ScopeRequest, opts ...grpc.CallOption) (*TypesResponse, error) {
+	out := new(TypesResponse)
+	err := c.cc.Invoke(ctx, GeospatialService_Types_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geospatialServiceClient) Levels(ctx context.Context, in *ScopeRequest, opts ...grpc.CallOption) (*LevelsResponse, error) {
+	out := new(LevelsResponse)
+	err := c.cc.Invoke(ctx, GeospatialService_Levels_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GeospatialServiceServer is the server API for GeospatialService service.
+// All implementations must embed UnimplementedGeospatialServiceServer
+// for forward compatibility
+type GeospatialServiceServer interface {
+	// List returns a page of the regions matching the filter, like GET /v1/q.
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// StreamList sends every region matching the filter, ignoring limit, page and cursor.
+	StreamList(*ListRequest, GeospatialService_StreamListServer) error
+	// Reverse returns the regions containing a point, the country first, like GET /v1/q?latlng=.
+	Reverse(context.Context, *ReverseRequest) (*ReverseResponse, error)
+	// BatchReverse reverse geocodes up to 1000 points, the results are in the order of the points.
+	BatchReverse(context.Context, *BatchReverseRequest) (*BatchReverseResponse, error)
+	// GetRegion returns a region by its id, like GET /v1/regions/:id.
+	GetRegion(context.Context, *GetRegionRequest) (*Region, error)
+	// Children sends every direct child of a region.
+	Children(*ChildrenRequest, GeospatialService_ChildrenServer) error
+	// Types returns the normalized region types, like GET /v1/types.
+	Types(context.Context, *ScopeRequest) (*TypesResponse, error)
+	// Levels returns the levels of the hierarchy, like GET /v1/levels.
+	Levels(context.Context, *ScopeRequest) (*LevelsResponse, error)
+	mustEmbedUnimplementedGeospatialServiceServer()
+}
+
+// UnimplementedGeospatialServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedGeospatialServiceServer struct {
+}
+
+func (UnimplementedGeospatialServiceServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedGeospatialServiceServer) StreamList(*ListRequest, GeospatialService_StreamListServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamList not implemented")
+}
+func (UnimplementedGeospatialServiceServer) Reverse(context.Context, *ReverseRequest) (*ReverseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reverse not implemented")
+}
+func (UnimplementedGeospatialServiceServer) BatchReverse(context.Context, *BatchReverseRequest) (*BatchReverseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchReverse not implemented")
+}
+func (UnimplementedGeospatialServiceServer) GetRegion(context.Context, *GetRegionRequest) (*Region, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegion not implemented")
+}
+func (UnimplementedGeospatialServiceServer) Children(*ChildrenRequest, GeospatialService_ChildrenServer) error {
+	return status.Errorf(codes.Unimplemented, "method Children not implemented")
+}
+func (UnimplementedGeospatialServiceServer) Types(context.Context, *ScopeRequest) (*TypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Types not implemented")
+}
+func (UnimplementedGeospatialServiceServer) Levels(context.Context, *ScopeRequest) (*LevelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Levels not implemented")
+}
+func (UnimplementedGeospatialServiceServer) mustEmbedUnimplementedGeospatialServiceServer() {}
+
+// UnsafeGeospatialServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GeospatialServiceServer will
+// result in compilation errors.
+type UnsafeGeospatialServiceServer interface {
+	mustEmbedUnimplementedGeospatialServiceServer()
+}
+
+func RegisterGeospatialServiceServer(s grpc.ServiceRegistrar, srv GeospatialServiceServer) {
+	s.RegisterService(&GeospatialService_ServiceDesc, srv)
+}
+
+func _GeospatialService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeospatialServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeospatialService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeospatialServiceServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeospatialService_StreamList_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GeospatialServiceServer).StreamList(m, &geospatialServiceStreamListServer{stream})
+}
+
+type GeospatialService_StreamListServer interface {
+	Send(*Region) error
+	grpc.ServerStream
+}
+
+type geospatialServiceStreamListServer struct {
+	grpc.ServerStream
+}
+
+func (x *geospatialServiceStreamListServer) Send(m *Region) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GeospatialService_Reverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeospatialServiceServer).Reverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeospatialService_Reverse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeospatialServiceServer).Reverse(ctx, req.(*ReverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeospatialService_BatchReverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchReverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeospatialServiceServer).BatchReverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeospatialService_BatchReverse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeospatialServiceServer).BatchReverse(ctx, req.(*BatchReverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeospatialService_GetRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeospatialServiceServer).GetRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeospatialService_GetRegion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeospatialServiceServer).GetRegion(ctx, req.(*GetRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeospatialService_Children_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChildrenRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GeospatialServiceServer).Children(m, &geospatialServiceChildrenServer{stream})
+}
+
+type GeospatialService_ChildrenServer interface {
+	Send(*Region) error
+	grpc.ServerStream
+}
+
+type geospatialServiceChildrenServer struct {
+	grpc.ServerStream
+}
+
+func (x *geospatialServiceChildrenServer) Send(m *Region) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GeospatialService_Types_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScopeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeospatialServiceServer).Types(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeospatialService_Types_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeospatialServiceServer).Types(ctx, req.(*ScopeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeospatialService_Levels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScopeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeospatialServiceServer).Levels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeospatialService_Levels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeospatialServiceServer).Levels(ctx, req.(*ScopeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GeospatialService_ServiceDesc is the grpc.ServiceDesc for GeospatialService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GeospatialService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "geospatial.v1.GeospatialService",
+	HandlerType: (*GeospatialServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _GeospatialService_List_Handler,
+		},
+		{
+			MethodName: "Reverse",
+			Handler:    _GeospatialService_Reverse_Handler,
+		},
+		{
+			MethodName: "BatchReverse",
+			Handler:    _GeospatialService_BatchReverse_Handler,
+		},
+		{
+			MethodName: "GetRegion",
+			Handler:    _GeospatialService_GetRegion_Handler,
+		},
+		{
+			MethodName: "Types",
+			Handler:    _GeospatialService_Types_Handler,
+		},
+		{
+			MethodName: "Levels",
+			Handler:    _GeospatialService_Levels_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamList",
+			Handler:       _GeospatialService_StreamList_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Children",
+			Handler:       _GeospatialService_Children_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "geospatial/v1/geospatial.proto",
+}
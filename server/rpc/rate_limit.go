@@ -0,0 +1,52 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimit spends the cost of method for the client of ctx like the RateLimit middleware does
+// for HTTP requests. It returns the ratelimit-* headers to send, and a ResourceExhausted error
+// when the client has run out.
+func rateLimit(ctx context.Context, rateLimitService service.RateLimitService, method string) (metadata.MD, error) {
+	if rateLimitService == nil {
+		return nil, nil
+	}
+
+	limit := rateLimitService.Take(ctx, method, clientIP(ctx))
+	md := metadata.MD{}
+	if limit.Limit > 0 {
+		md.Set(strings.ToLower(constant.RateLimitLimitHeader), strconv.Itoa(limit.Limit))
+		md.Set(strings.ToLower(constant.RateLimitRemainingHeader), strconv.Itoa(limit.Remaining))
+		md.Set(strings.ToLower(constant.RateLimitResetHeader), strconv.Itoa(int(limit.Reset/time.Second)))
+		md.Set(strings.ToLower(constant.RateLimitPolicyHeader), limit.Policy)
+	}
+	if !limit.Allowed {
+		md.Set(strings.ToLower(constant.RetryAfterHeader), strconv.Itoa(int(limit.RetryAfter/time.Second)))
+		return md, status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %s", limit.RetryAfter)
+	}
+
+	return md, nil
+}
+
+// clientIP returns the IP address of the peer of ctx.
+func clientIP(ctx context.Context) string {
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	return ip
+}
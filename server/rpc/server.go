@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	logCtx "github.com/si-bas/go-rest-geospatial/pkg/logger/context"
+	"github.com/si-bas/go-rest-geospatial/server/rpc/geospatialv1"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer returns the gRPC server of the regions of geospatialService. Calls are
+// authenticated by authService and must be made with role, or any calls when it is empty.
+// They are rate limited by rateLimitService when it is set.
+func NewServer(geospatialService service.GeospatialService, authService service.AuthService, role string, rateLimitService service.RateLimitService) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx, err := authenticate(injectContext(ctx), authService, role, rateLimitService)
+			if err != nil {
+				return nil, err
+			}
+			md, err := rateLimit(ctx, rateLimitService, info.FullMethod)
+			if len(md) > 0 {
+				grpc.SetHeader(ctx, md)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := authenticate(injectContext(ss.Context()), authService, role, rateLimitService)
+			if err != nil {
+				return err
+			}
+			md, err := rateLimit(ctx, rateLimitService, info.FullMethod)
+			if len(md) > 0 {
+				ss.SetHeader(md)
+			}
+			if err != nil {
+				return err
+			}
+			return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		}),
+	)
+	geospatialv1.RegisterGeospatialServiceServer(s, NewGeospatialServer(geospatialService))
+	reflection.Register(s)
+
+	return s
+}
+
+// injectContext adds the request id of the call, or a new one, to ctx like the
+// InjectContext middleware does for HTTP requests.
+func injectContext(ctx context.Context) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(constant.XRequestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	ctx = logCtx.InjectRequestID(ctx, requestID)
+	return context.WithValue(ctx, constant.XRequestIDHeader, requestID)
+}
+
+// contextStream is a server stream with another context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,8 +12,12 @@ import (
 	"github.com/si-bas/go-rest-geospatial/domain/repository"
 	"github.com/si-bas/go-rest-geospatial/pkg/gorm"
 	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/ratelimit"
+	"github.com/si-bas/go-rest-geospatial/server/gql"
 	"github.com/si-bas/go-rest-geospatial/server/handler"
 	"github.com/si-bas/go-rest-geospatial/server/middleware"
+	"github.com/si-bas/go-rest-geospatial/server/openapi"
+	"github.com/si-bas/go-rest-geospatial/server/rpc"
 	"github.com/si-bas/go-rest-geospatial/service"
 	"github.com/si-bas/go-rest-geospatial/shared/constant"
 )
@@ -25,31 +31,136 @@ func New() *HTTPServer {
 }
 
 func (s *HTTPServer) Start() {
-	h := initHandler()
+	services := initServices()
+
+	if config.Config.App.GRPCPort > 0 {
+		go startGRPC(config.Config.App.GRPCPort, services)
+	}
+
+	router := NewRouter(services)
+	err := router.Run(fmt.Sprintf(":%d", config.Config.App.Port))
+	if err != nil {
+		logger.Error(context.Background(), "failed to run router", err)
+	}
+}
+
+// NewRouter returns the routes of the HTTP API over services. A route added here must be
+// described in server/openapi/openapi.json too.
+func NewRouter(services Services) *gin.Engine {
+	h := handler.New(
+		services.Geospatial,
+		services.Export,
+		services.Layer,
+		services.Geofence,
+		services.Webhook,
+		services.Auth,
+	)
 
 	if config.Config.App.Env == constant.EnvProduction {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	router := gin.Default()
+	// Clients are told apart by their IP for rate limiting, only trusted proxies may forward it.
+	if err := router.SetTrustedProxies(config.Config.RateLimit.TrustedProxies); err != nil {
+		panic("error set trusted proxies, err=" + err.Error())
+	}
 
 	router.Use(middleware.CORS())
 	router.Use(middleware.InjectContext())
+	router.Use(middleware.Authenticate(services.Auth, services.RateLimit))
 	router.GET("/healthcheck", h.HealthCheck)
+	router.GET("/openapi.json", openapi.SpecHandler)
+	router.GET("/docs/*filepath", openapi.DocsHandler)
+
+	// Reads are public unless Auth.ProtectReads is set, exports always need the reader role,
+	// writes need the importer role and the webhooks and keys the admin role.
+	var readRoles []gin.HandlerFunc
+	if config.Config.Auth.ProtectReads {
+		readRoles = append(readRoles, middleware.RequireRole(constant.RoleReader))
+	}
+	// Everything but the health check and the docs is rate limited.
+	api := router.Group("")
+	if services.RateLimit != nil {
+		api.Use(middleware.RateLimit(services.RateLimit))
+	}
+	read := api.Group("", readRoles...)
 
-	groupV1 := router.Group("/v1")
+	graphqlHandler := gql.Handler(services.Geospatial)
+	read.GET("/graphql", graphqlHandler)
+	read.POST("/graphql", graphqlHandler)
 
-	groupV1.GET("/q", h.GeospatialList)
-	groupV1.GET("/types", h.GeospatialTypes)
-	groupV1.GET("/levels", h.GeospatialLevels)
-	groupV1.POST("/import", h.GeospatialImport)
+	readV1 := read.Group("/v1")
+	readV1.GET("/q", h.GeospatialList)
+	readV1.GET("/regions/:id", h.GeospatialDetail)
+	readV1.GET("/regions/:id/neighbors", h.GeospatialNeighbors)
+	readV1.POST("/query/intersects", h.GeospatialIntersects)
+	readV1.POST("/geometry/union", h.GeospatialUnion)
+	readV1.GET("/autocomplete", h.Autocomplete)
+	readV1.GET("/types", h.GeospatialTypes)
+	readV1.GET("/levels", h.GeospatialLevels)
+	readV1.GET("/exports/:id", h.ExportDetail)
+	readV1.GET("/exports/:id/download", h.ExportDownload)
+	readV1.GET("/layers", h.LayerList)
+	readV1.GET("/layers/:name/features", h.LayerFeatures)
+	readV1.POST("/layers/:name/query/intersects", h.LayerIntersects)
+	readV1.GET("/geofences", h.GeofenceList)
+	readV1.GET("/geofences/objects/:objectId", h.GeofenceObject)
 
-	err := router.Run(fmt.Sprintf(":%d", config.Config.App.Port))
+	// An export keeps a job and a file on the server, anonymous clients cannot start one.
+	exportV1 := api.Group("/v1", middleware.RequireRole(constant.RoleReader))
+	exportV1.POST("/exports", h.ExportCreate)
+
+	importV1 := api.Group("/v1", middleware.RequireRole(constant.RoleImporter))
+	importV1.POST("/import", h.GeospatialImport)
+	importV1.DELETE("/regions/:id", h.GeospatialDelete)
+	importV1.DELETE("/layers/:name", h.LayerDelete)
+	importV1.POST("/geofences", h.GeofenceCreate)
+	importV1.DELETE("/geofences/:id", h.GeofenceDelete)
+	importV1.POST("/geofences/positions", h.GeofencePositions)
+
+	adminV1 := api.Group("/v1", middleware.RequireRole(constant.RoleAdmin))
+	adminV1.GET("/webhooks", h.WebhookList)
+	adminV1.POST("/webhooks", h.WebhookCreate)
+	adminV1.DELETE("/webhooks/:id", h.WebhookDelete)
+	adminV1.GET("/webhooks/:id/deliveries", h.WebhookDeliveries)
+	adminV1.GET("/keys", h.APIKeyList)
+	adminV1.POST("/keys", h.APIKeyCreate)
+	adminV1.DELETE("/keys/:id", h.APIKeyRevoke)
+	adminV1.PUT("/keys/:id/limit", h.APIKeyLimit)
+
+	return router
+}
+
+// startGRPC serves the gRPC API on port next to the HTTP server, its calls are reads.
+func startGRPC(port int, services Services) {
+	ctx := context.Background()
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
-		logger.Error(context.Background(), "failed to run router", err)
+		logger.Error(ctx, "failed to listen for grpc", err)
+		return
+	}
+
+	var role string
+	if config.Config.Auth.ProtectReads {
+		role = constant.RoleReader
 	}
+	if err := rpc.NewServer(services.Geospatial, services.Auth, role, services.RateLimit).Serve(listener); err != nil {
+		logger.Error(ctx, "failed to serve grpc", err)
+	}
+}
+
+// Services are the services the HTTP and the gRPC server share.
+type Services struct {
+	Geospatial service.GeospatialService
+	Export     service.ExportService
+	Layer      service.LayerService
+	Geofence   service.GeofenceService
+	Webhook    service.WebhookService
+	Auth       service.AuthService
+	RateLimit  service.RateLimitService
 }
 
-func initHandler() *handler.Handler {
+func initServices() Services {
 	var err error
 	config.TimeLocation, err = time.LoadLocation(config.Config.App.Timezone)
 	if err != nil {
@@ -58,18 +169,105 @@ func initHandler() *handler.Handler {
 
 	logger.InitLogger()
 
-	// TODO: init DB
-	db := gorm.ConnectDB()
-
 	// TODO: init repositories
-	geospatialRepo := repository.NewGeospatialRepository(db)
+	repos := InitRepositories()
 
 	// TODO: init pkgs
 
 	// TODO: init services
-	geospatialService := service.NewGeospatialService(geospatialRepo)
+	geospatialService := service.NewGeospatialService(repos.Geospatial, repos.Event)
+	exportConcurrency := uint(defaultExportConcurrency)
+	if config.Config.Export.Concurrency > 0 {
+		exportConcurrency = uint(config.Config.Export.Concurrency)
+	}
+	exportService := service.NewExportService(repos.Geospatial, exportConcurrency)
+	// Finished export jobs and their files are removed once they expire.
+	go exportService.Run(context.Background(), milliseconds(config.Config.Export.TTL, defaultExportTTL))
+	layerService := service.NewLayerService(repos.Layer)
+	geofenceService := service.NewGeofenceService(repos.Geofence, repos.Geospatial, service.NewLogEmitter())
 
-	return handler.New(
-		geospatialService,
-	)
+	webhookConfig := config.Config.Webhook
+	maxAttempts := uint(defaultWebhookMaxAttempts)
+	if webhookConfig.MaxAttempts > 0 {
+		maxAttempts = uint(webhookConfig.MaxAttempts)
+	}
+	client := &http.Client{Timeout: milliseconds(webhookConfig.Timeout, defaultWebhookTimeout)}
+	webhookService := service.NewWebhookService(repos.Event, client, maxAttempts)
+	// The outbox is dispatched for as long as the server runs.
+	go webhookService.Run(context.Background(), milliseconds(webhookConfig.Interval, defaultWebhookInterval))
+
+	rateLimit := ratelimit.Limit{
+		Rate:       config.Config.RateLimit.Rate,
+		Burst:      config.Config.RateLimit.Burst,
+		DailyQuota: config.Config.RateLimit.DailyQuota,
+	}
+
+	return Services{
+		Geospatial: geospatialService,
+		Export:     exportService,
+		Layer:      layerService,
+		Geofence:   geofenceService,
+		Webhook:    webhookService,
+		Auth:       service.NewAuthService(repos.APIKey, config.Config.Auth.JWTSecret, config.Config.Auth.JWTIssuer),
+		RateLimit:  service.NewRateLimitService(rateLimit, config.Config.RateLimit.Costs),
+	}
+}
+
+const (
+	defaultExportTTL         = 24 * time.Hour
+	defaultExportConcurrency = 2
+
+	defaultWebhookInterval    = 5 * time.Second
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxAttempts = 8
+)
+
+// milliseconds returns ms milliseconds, or fallback when ms is not set.
+func milliseconds(ms int, fallback time.Duration) time.Duration {
+	if ms <= 0 {
+		return fallback
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// InitGeospatialRepository opens the storage backend chosen by Db.Driver.
+func InitGeospatialRepository() repository.GeospatialRepository {
+	return InitRepositories().Geospatial
+}
+
+// Repositories are the repositories of one storage backend.
+type Repositories struct {
+	Geospatial repository.GeospatialRepository
+	Layer      repository.LayerRepository
+	Geofence   repository.GeofenceRepository
+	Event      repository.EventRepository
+	APIKey     repository.APIKeyRepository
+}
+
+// InitRepositories opens the storage backend chosen by Db.Driver, layers, geofences, the
+// outbox and the API keys are stored next to the regions.
+func InitRepositories() Repositories {
+	if config.Config.Db.Driver == constant.DbDriverMemory {
+		eventRepo := repository.NewEventMemoryRepository()
+		geospatialRepo, err := repository.NewGeospatialMemoryRepository(config.Config.Db.Path, eventRepo)
+		if err != nil {
+			panic("error loading in-memory dataset, err=" + err.Error())
+		}
+		return Repositories{
+			Geospatial: geospatialRepo,
+			Layer:      repository.NewLayerMemoryRepository(),
+			Geofence:   repository.NewGeofenceMemoryRepository(),
+			Event:      eventRepo,
+			APIKey:     repository.NewAPIKeyMemoryRepository(),
+		}
+	}
+
+	db := gorm.ConnectDB()
+	return Repositories{
+		Geospatial: repository.NewGeospatialRepository(db),
+		Layer:      repository.NewLayerRepository(db),
+		Geofence:   repository.NewGeofenceRepository(db),
+		Event:      repository.NewEventRepository(db),
+		APIKey:     repository.NewAPIKeyRepository(db),
+	}
 }
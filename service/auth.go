@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+)
+
+var (
+	// ErrInvalidCredentials is returned for an unknown or revoked API key and for a JWT that
+	// does not verify, has expired or has no known role.
+	ErrInvalidCredentials = errors.New("invalid or expired credentials")
+	// ErrAPIKeyNotFound is returned for an API key id that does not exist.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidRole is returned for a role that is not one of constant.Roles.
+	ErrInvalidRole = fmt.Errorf("role must be one of %s", strings.Join(constant.Roles, ", "))
+	// ErrInvalidRateLimit is returned for a rate limit with a negative field.
+	ErrInvalidRateLimit = errors.New("rate, burst and daily_quota must not be negative")
+)
+
+// apiKeyPrefixLength is the length of the start of a key kept to tell keys apart.
+const apiKeyPrefixLength = 12
+
+type AuthService interface {
+	CreateKey(context.Context, string, string, model.RateLimit) (*model.APIKey, error)
+	ListKeys(context.Context) ([]model.APIKey, error)
+	RevokeKey(context.Context, uint) error
+	SetKeyLimit(context.Context, uint, model.RateLimit) (*model.APIKey, error)
+	Authenticate(context.Context, string) (*model.Principal, error)
+}
+
+type authImpl struct {
+	apiKeyRepo repository.APIKeyRepository
+	jwtSecret  []byte
+	jwtIssuer  string
+}
+
+// NewAuthService returns the service managing API keys and authenticating API keys and JWTs.
+// JWTs are verified as HS256 with jwtSecret and must be issued by jwtIssuer when it is set,
+// they are rejected when there is no secret.
+func NewAuthService(apiKeyRepo repository.APIKeyRepository, jwtSecret, jwtIssuer string) AuthService {
+	return &authImpl{
+		apiKeyRepo: apiKeyRepo,
+		jwtSecret:  []byte(jwtSecret),
+		jwtIssuer:  jwtIssuer,
+	}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func isRole(role string) bool {
+	for _, r := range constant.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidRateLimit(limit model.RateLimit) bool {
+	return (limit.Rate == nil || *limit.Rate >= 0) &&
+		(limit.Burst == nil || *limit.Burst >= 0) &&
+		(limit.DailyQuota == nil || *limit.DailyQuota >= 0)
+}
+
+// CreateKey creates a key named name with role and limit, the key itself is only returned here.
+func (s *authImpl) CreateKey(ctx context.Context, name, role string, limit model.RateLimit) (*model.APIKey, error) {
+	if !isRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if !isValidRateLimit(limit) {
+		return nil, ErrInvalidRateLimit
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	key := constant.APIKeyPrefix + hex.EncodeToString(secret)
+
+	apiKey := &model.APIKey{
+		Name:   name,
+		Prefix: key[:apiKeyPrefixLength],
+		Hash:   hashAPIKey(key),
+		Role:   role,
+		Limit:  limit,
+	}
+	if err := s.apiKeyRepo.CreateAPIKey(ctx, apiKey); err != nil {
+		logger.Error(ctx, "failed to create api key", err)
+		return nil, err
+	}
+	apiKey.Key = key
+
+	return apiKey, nil
+}
+
+// ListKeys returns every key, the revoked ones too.
+func (s *authImpl) ListKeys(ctx context.Context) ([]model.APIKey, error) {
+	keys, err := s.apiKeyRepo.GetAPIKeys(ctx, nil)
+	if err != nil {
+		logger.Error(ctx, "failed to get api keys", err)
+		return nil, err
+	}
+	if keys == nil {
+		keys = make([]model.APIKey, 0)
+	}
+
+	return keys, nil
+}
+
+// RevokeKey revokes the key with the given id, requests made with it are rejected from now on.
+func (s *authImpl) RevokeKey(ctx context.Context, id uint) error {
+	keys, err := s.apiKeyRepo.GetAPIKeys(ctx, []uint{id})
+	if err != nil {
+		logger.Error(ctx, "failed to get api key", err)
+		return err
+	}
+	if len(keys) == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	if err := s.apiKeyRepo.RevokeAPIKey(ctx, id, time.Now()); err != nil {
+		logger.Error(ctx, "failed to revoke api key", err)
+		return err
+	}
+	return nil
+}
+
+// SetKeyLimit replaces the rate limit of the key with the given id, the fields left empty go
+// back to the default. It applies to the requests authenticated from now on.
+func (s *authImpl) SetKeyLimit(ctx context.Context, id uint, limit model.RateLimit) (*model.APIKey, error) {
+	if !isValidRateLimit(limit) {
+		return nil, ErrInvalidRateLimit
+	}
+
+	keys, err := s.apiKeyRepo.GetAPIKeys(ctx, []uint{id})
+	if err != nil {
+		logger.Error(ctx, "failed to get api key", err)
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	if err := s.apiKeyRepo.UpdateAPIKeyLimit(ctx, id, limit); err != nil {
+		logger.Error(ctx, "failed to update api key limit", err)
+		return nil, err
+	}
+	keys[0].Limit = limit
+
+	return &keys[0], nil
+}
+
+// authClaims are the claims of a JWT, the subject identifies the user.
+type authClaims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Authenticate returns who token was issued to, token being an API key or a JWT.
+func (s *authImpl) Authenticate(ctx context.Context, token string) (*model.Principal, error) {
+	if strings.HasPrefix(token, constant.APIKeyPrefix) {
+		return s.authenticateKey(ctx, token)
+	}
+	return s.authenticateJWT(ctx, token)
+}
+
+func (s *authImpl) authenticateKey(ctx context.Context, key string) (*model.Principal, error) {
+	apiKey, err := s.apiKeyRepo.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		logger.Error(ctx, "failed to get api key", err)
+		return nil, err
+	}
+	if apiKey == nil || apiKey.RevokedAt != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &model.Principal{UserID: fmt.Sprintf("key:%d", apiKey.ID), Role: apiKey.Role, RateLimit: apiKey.Limit}, nil
+}
+
+func (s *authImpl) authenticateJWT(ctx context.Context, token string) (*model.Principal, error) {
+	if len(s.jwtSecret) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if s.jwtIssuer != "" {
+		options = append(options, jwt.WithIssuer(s.jwtIssuer))
+	}
+
+	var claims authClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.jwtSecret, nil
+	}, options...)
+	if err != nil {
+		logger.Warn(ctx, "failed to verify jwt", tag.Err(err))
+		return nil, ErrInvalidCredentials
+	}
+	if claims.Subject == "" || !isRole(claims.Role) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &model.Principal{UserID: "jwt:" + claims.Subject, Role: claims.Role}, nil
+}
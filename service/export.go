@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/export"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	logCtx "github.com/si-bas/go-rest-geospatial/pkg/logger/context"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+var ErrExportJobNotFound = errors.New("export job not found")
+
+// exportBatch is how many regions an export reads at a time, with their geometry.
+const exportBatch = 100
+
+type ExportService interface {
+	Export(context.Context, model.GeospatialFilter, string, string) (int, error)
+	CreateJob(context.Context, model.GeospatialFilter, string) (*model.ExportJob, error)
+	GetJob(context.Context, string) (*model.ExportJob, error)
+	Run(context.Context, time.Duration)
+	Expire(context.Context, time.Time) int
+}
+
+type exportImpl struct {
+	geospatialRepo repository.GeospatialRepository
+	// slots holds a token per running job, the jobs past its capacity wait pending.
+	slots chan struct{}
+
+	mu   sync.RWMutex
+	jobs map[string]*model.ExportJob
+}
+
+// NewExportService returns an ExportService running at most concurrency jobs at once.
+func NewExportService(geospatialRepo repository.GeospatialRepository, concurrency uint) ExportService {
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	return &exportImpl{
+		geospatialRepo: geospatialRepo,
+		slots:          make(chan struct{}, concurrency),
+		jobs:           make(map[string]*model.ExportJob),
+	}
+}
+
+// Export writes every region matching filter to path and returns the number of rows written.
+// Regions are read exportBatch at a time by cursor and written as they come.
+func (s *exportImpl) Export(ctx context.Context, filter model.GeospatialFilter, format string, path string) (int, error) {
+	if !export.IsSupported(format) {
+		return 0, export.ErrUnsupportedFormat
+	}
+
+	w, err := export.Create(ctx, format, path)
+	if err != nil {
+		logger.Error(ctx, "failed to create export file", err, tag.Tag{Key: "format", Value: format})
+		return 0, err
+	}
+
+	filter.WithGeometry = true
+	param := pagination.Param{
+		Limit:     exportBatch,
+		Sort:      []pagination.ParamSort{{Column: "level", Order: pagination.OrderAsc}, {Column: "id", Order: pagination.OrderAsc}},
+		UseCursor: true,
+		SkipTotal: true,
+	}
+	rows := 0
+	for {
+		geospatials, meta, err := s.geospatialRepo.GetPaginate(ctx, filter, param)
+		if err != nil {
+			logger.Error(ctx, "failed to get geospatial data for export", err)
+			w.Close()
+			return 0, err
+		}
+
+		if err := w.Write(ctx, geospatials); err != nil {
+			logger.Error(ctx, "failed to write export file", err, tag.Tag{Key: "format", Value: format})
+			w.Close()
+			return 0, err
+		}
+		rows += len(geospatials)
+
+		if meta.NextCursor == "" {
+			break
+		}
+		param.Cursor = meta.NextCursor
+	}
+
+	if err := w.Close(); err != nil {
+		logger.Error(ctx, "failed to write export file", err, tag.Tag{Key: "format", Value: format})
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+// CreateJob registers an export job and runs it in the background, the returned job
+// can be polled with GetJob until its status is completed or failed.
+func (s *exportImpl) CreateJob(ctx context.Context, filter model.GeospatialFilter, format string) (*model.ExportJob, error) {
+	if !export.IsSupported(format) {
+		return nil, export.ErrUnsupportedFormat
+	}
+
+	dir := config.Config.Export.Dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		logger.Error(ctx, "failed to create export directory", err)
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	job := &model.ExportJob{
+		ID:        id,
+		Format:    format,
+		Status:    constant.ExportStatusPending,
+		Filter:    filter,
+		FilePath:  filepath.Join(dir, "geospatial-"+id+"."+format),
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.jobs[id] = job
+	created := s.snapshot(job)
+	s.mu.Unlock()
+
+	// The request context is cancelled as soon as the response is sent, keep only its logging tags.
+	jobCtx := logCtx.InjectRequestID(context.Background(), logCtx.GetTagValue(ctx, tag.RequestIDKey))
+	go s.run(jobCtx, id)
+
+	return created, nil
+}
+
+func (s *exportImpl) GetJob(ctx context.Context, id string) (*model.ExportJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrExportJobNotFound
+	}
+
+	return s.snapshot(job), nil
+}
+
+// Run expires the jobs finished more than ttl ago until ctx is done.
+func (s *exportImpl) Run(ctx context.Context, ttl time.Duration) {
+	interval := time.Minute
+	if ttl < interval {
+		interval = ttl
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Expire(ctx, now.Add(-ttl))
+		}
+	}
+}
+
+// Expire drops the jobs finished before before and removes their files. Pending and
+// running jobs are kept. It returns the number of jobs dropped.
+func (s *exportImpl) Expire(ctx context.Context, before time.Time) int {
+	s.mu.Lock()
+	var expired []*model.ExportJob
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(before) {
+			expired = append(expired, job)
+			delete(s.jobs, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, job := range expired {
+		if err := os.Remove(job.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Error(ctx, "failed to remove export file", err, tag.Tag{Key: "path", Value: job.FilePath})
+		}
+	}
+
+	return len(expired)
+}
+
+func (s *exportImpl) run(ctx context.Context, id string) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	s.update(id, func(job *model.ExportJob) {
+		job.Status = constant.ExportStatusRunning
+	})
+
+	s.mu.RLock()
+	job := *s.jobs[id]
+	s.mu.RUnlock()
+
+	rows, err := s.Export(ctx, job.Filter, job.Format, job.FilePath)
+
+	s.update(id, func(job *model.ExportJob) {
+		finishedAt := time.Now()
+		job.FinishedAt = &finishedAt
+		job.Rows = rows
+		job.Status = constant.ExportStatusCompleted
+		if err != nil {
+			job.Status = constant.ExportStatusFailed
+			job.Error = err.Error()
+		}
+	})
+}
+
+func (s *exportImpl) update(id string, fn func(*model.ExportJob)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+func (s *exportImpl) snapshot(job *model.ExportJob) *model.ExportJob {
+	copied := *job
+	return &copied
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+	"github.com/twpayne/go-geom/encoding/wkt"
+)
+
+var (
+	// ErrGeofenceNotFound is returned for a fence id that does not exist.
+	ErrGeofenceNotFound = errors.New("geofence not found")
+	// ErrGeofenceObjectNotFound is returned for an object no position was posted for.
+	ErrGeofenceObjectNotFound = errors.New("tracked object not found")
+)
+
+// maxTrackAttempts is how often the positions of an object are applied when other positions
+// of the same object are saved in the meantime.
+const maxTrackAttempts = 5
+
+// GeofenceEmitter publishes the events detected from posted positions, next to returning
+// them.
+type GeofenceEmitter interface {
+	Emit(context.Context, []model.GeofenceEvent) error
+}
+
+type logEmitter struct{}
+
+// NewLogEmitter returns the emitter writing every event to the log.
+func NewLogEmitter() GeofenceEmitter {
+	return logEmitter{}
+}
+
+func (logEmitter) Emit(ctx context.Context, events []model.GeofenceEvent) error {
+	for _, e := range events {
+		logger.Info(ctx, "geofence event",
+			tag.Tag{Key: "type", Value: e.Type},
+			tag.Tag{Key: "object_id", Value: e.ObjectID},
+			tag.Tag{Key: "geofence_id", Value: fmt.Sprint(e.GeofenceID)},
+			tag.Tag{Key: "at", Value: e.At.Format(time.RFC3339)},
+		)
+	}
+	return nil
+}
+
+type GeofenceService interface {
+	Create(context.Context, model.Geofence, geom.T) (*model.Geofence, error)
+	List(context.Context) ([]model.Geofence, error)
+	Delete(context.Context, uint) error
+	Object(context.Context, string) (*model.GeofenceObjectStatus, error)
+	Track(context.Context, []model.GeofencePosition, bool) (*model.GeofenceTracking, error)
+}
+
+type geofenceImpl struct {
+	geofenceRepo   repository.GeofenceRepository
+	geospatialRepo repository.GeospatialRepository
+	emitter        GeofenceEmitter
+}
+
+func NewGeofenceService(geofenceRepo repository.GeofenceRepository, geospatialRepo repository.GeospatialRepository, emitter GeofenceEmitter) GeofenceService {
+	return &geofenceImpl{
+		geofenceRepo:   geofenceRepo,
+		geospatialRepo: geospatialRepo,
+		emitter:        emitter,
+	}
+}
+
+// Create registers geofence, the region of its GeospatialID or the polygon t. A region fence
+// follows the region, it is checked against its current boundary.
+func (s *geofenceImpl) Create(ctx context.Context, geofence model.Geofence, t geom.T) (*model.Geofence, error) {
+	if geofence.GeospatialID != nil {
+		regions, err := s.geospatialRepo.Get(ctx, model.GeospatialFilter{IDs: []uint{*geofence.GeospatialID}, Columns: []string{"id", "name"}})
+		if err != nil {
+			logger.Error(ctx, "failed to get geospatial data by id", err)
+			return nil, err
+		}
+		if len(regions) == 0 {
+			return nil, ErrGeospatialNotFound
+		}
+		if geofence.Name == "" {
+			geofence.Name = regions[0].Name
+		}
+	} else {
+		mp := geo.ToMultiPolygon(t)
+		wktString, err := wkt.Marshal(mp)
+		if err != nil {
+			return nil, err
+		}
+
+		b := mp.Bounds()
+		minLng, minLat, maxLng, maxLat := b.Min(0), b.Min(1), b.Max(0), b.Max(1)
+		geofence.Geometry = wktString
+		geofence.BboxMinLng, geofence.BboxMinLat = &minLng, &minLat
+		geofence.BboxMaxLng, geofence.BboxMaxLat = &maxLng, &maxLat
+	}
+
+	if err := s.geofenceRepo.CreateGeofence(ctx, &geofence); err != nil {
+		logger.Error(ctx, "failed to create geofence", err)
+		return nil, err
+	}
+
+	geofences, err := s.geofenceRepo.GetGeofences(ctx, []uint{geofence.ID})
+	if err != nil {
+		logger.Error(ctx, "failed to get geofence by id", err)
+		return nil, err
+	}
+	if len(geofences) == 0 {
+		return nil, ErrGeofenceNotFound
+	}
+	if err := setGeofenceShapes(geofences); err != nil {
+		return nil, err
+	}
+
+	return &geofences[0], nil
+}
+
+// List returns every fence, polygon fences with their geometry as GeoJSON.
+func (s *geofenceImpl) List(ctx context.Context) ([]model.Geofence, error) {
+	geofences, err := s.geofenceRepo.GetGeofences(ctx, nil)
+	if err != nil {
+		logger.Error(ctx, "failed to get geofences", err)
+		return nil, err
+	}
+	if err := setGeofenceShapes(geofences); err != nil {
+		return nil, err
+	}
+
+	return geofences, nil
+}
+
+func setGeofenceShapes(geofences []model.Geofence) error {
+	for i := range geofences {
+		g := &geofences[i]
+		if g.Geometry == "" {
+			continue
+		}
+
+		t, err := wkt.Unmarshal(g.Geometry)
+		if err != nil {
+			return err
+		}
+		if g.Shape, err = geojson.Marshal(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes the fence with the given id, objects in it leave it without an exit event.
+func (s *geofenceImpl) Delete(ctx context.Context, id uint) error {
+	geofences, err := s.geofenceRepo.GetGeofences(ctx, []uint{id})
+	if err != nil {
+		logger.Error(ctx, "failed to get geofence by id", err)
+		return err
+	}
+	if len(geofences) == 0 {
+		return ErrGeofenceNotFound
+	}
+
+	if err := s.geofenceRepo.DeleteGeofence(ctx, id); err != nil {
+		logger.Error(ctx, "failed to delete geofence", err)
+		return err
+	}
+
+	return nil
+}
+
+// Object returns the last known position of the object with the given id and the fences it
+// is in.
+func (s *geofenceImpl) Object(ctx context.Context, objectID string) (*model.GeofenceObjectStatus, error) {
+	object, states, err := s.geofenceRepo.GetObject(ctx, objectID)
+	if err != nil {
+		logger.Error(ctx, "failed to get tracked object", err)
+		return nil, err
+	}
+	if object == nil {
+		return nil, ErrGeofenceObjectNotFound
+	}
+
+	if states == nil {
+		states = []model.GeofenceState{}
+	}
+	return &model.GeofenceObjectStatus{GeofenceObject: *object, Fences: states}, nil
+}
+
+// Track applies positions to the fences their objects are in and returns the events this
+// detects, ordered by object and time: entering a fence, exiting it, and dwelling in it for
+// its dwell time, once per stay. A position older than the last one of its object is ignored,
+// so is a position without a latitude or a longitude. With emit the events are also handed to the emitter.
+func (s *geofenceImpl) Track(ctx context.Context, positions []model.GeofencePosition, emit bool) (*model.GeofenceTracking, error) {
+	now := time.Now()
+	byObject := make(map[string][]model.GeofencePosition)
+	var objectIds []string
+	for _, p := range positions {
+		if p.At == nil {
+			at := now
+			p.At = &at
+		}
+		if _, ok := byObject[p.ObjectID]; !ok {
+			objectIds = append(objectIds, p.ObjectID)
+		}
+		byObject[p.ObjectID] = append(byObject[p.ObjectID], p)
+	}
+	sort.Strings(objectIds)
+
+	tracking := &model.GeofenceTracking{Events: []model.GeofenceEvent{}}
+	for _, objectID := range objectIds {
+		ignored, events, err := s.track(ctx, objectID, byObject[objectID])
+		if err != nil {
+			return nil, err
+		}
+		tracking.Ignored += ignored
+		tracking.Events = append(tracking.Events, events...)
+	}
+
+	if emit && len(tracking.Events) > 0 {
+		if err := s.emitter.Emit(ctx, tracking.Events); err != nil {
+			logger.Error(ctx, "failed to emit geofence events", err)
+			return nil, err
+		}
+	}
+
+	return tracking, nil
+}
+
+// track applies the positions of one object in the order of their time. When positions of
+// the same object are tracked concurrently, the object is read again and the positions are
+// applied again on top of the other update.
+func (s *geofenceImpl) track(ctx context.Context, objectID string, positions []model.GeofencePosition) (int, []model.GeofenceEvent, error) {
+	sort.SliceStable(positions, func(i, j int) bool { return positions[i].At.Before(*positions[j].At) })
+
+	for attempt := 1; ; attempt++ {
+		ignored, events, err := s.trackOnce(ctx, objectID, positions)
+		if err == repository.ErrGeofenceObjectChanged && attempt < maxTrackAttempts {
+			continue
+		}
+		return ignored, events, err
+	}
+}
+
+func (s *geofenceImpl) trackOnce(ctx context.Context, objectID string, positions []model.GeofencePosition) (int, []model.GeofenceEvent, error) {
+	object, states, err := s.geofenceRepo.GetObject(ctx, objectID)
+	if err != nil {
+		logger.Error(ctx, "failed to get tracked object", err)
+		return 0, nil, err
+	}
+	var version uint
+	if object != nil {
+		version = object.Version
+	}
+
+	names := make(map[uint]string)
+	var ignored int
+	var events []model.GeofenceEvent
+	for _, p := range positions {
+		if object != nil && p.At.Before(object.SeenAt) || p.Lat == nil || p.Lng == nil {
+			ignored++
+			continue
+		}
+		lat, lng := *p.Lat, *p.Lng
+
+		containing, err := s.containing(ctx, lat, lng)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		event := func(eventType string, geofenceID uint) model.GeofenceEvent {
+			return model.GeofenceEvent{Type: eventType, ObjectID: objectID, GeofenceID: geofenceID, Lat: lat, Lng: lng, At: *p.At}
+		}
+
+		inside := make(map[uint]model.Geofence, len(containing))
+		for _, g := range containing {
+			inside[g.ID] = g
+			names[g.ID] = g.Name
+		}
+
+		var next []model.GeofenceState
+		entered := make(map[uint]bool, len(states))
+		for _, state := range states {
+			entered[state.GeofenceID] = true
+			g, ok := inside[state.GeofenceID]
+			if !ok {
+				events = append(events, event(constant.GeofenceEventExit, state.GeofenceID))
+				continue
+			}
+			if g.DwellSeconds > 0 && !state.Dwelled && p.At.Sub(state.EnteredAt) >= time.Duration(g.DwellSeconds)*time.Second {
+				state.Dwelled = true
+				events = append(events, event(constant.GeofenceEventDwell, g.ID))
+			}
+			next = append(next, state)
+		}
+		for _, g := range containing {
+			if !entered[g.ID] {
+				events = append(events, event(constant.GeofenceEventEnter, g.ID))
+				next = append(next, model.GeofenceState{ObjectID: objectID, GeofenceID: g.ID, EnteredAt: *p.At})
+			}
+		}
+
+		states = next
+		object = &model.GeofenceObject{ObjectID: objectID, Lat: lat, Lng: lng, SeenAt: *p.At, Version: version}
+	}
+
+	if len(positions) == ignored {
+		return ignored, nil, nil
+	}
+	if err := s.geofenceRepo.SaveObject(ctx, *object, states); err != nil {
+		if err == repository.ErrGeofenceObjectChanged {
+			return 0, nil, err
+		}
+		logger.Error(ctx, "failed to save tracked object", err)
+		return 0, nil, err
+	}
+
+	if err := s.nameEvents(ctx, events, names); err != nil {
+		return 0, nil, err
+	}
+	return ignored, events, nil
+}
+
+// containing returns the fences the point lat, lng is in: the fences of the regions containing
+// it, found like the latlng filter of the list finds them, and the polygon fences containing
+// it, so a point on a border is outside both kinds alike.
+func (s *geofenceImpl) containing(ctx context.Context, lat, lng float64) ([]model.Geofence, error) {
+	regions, err := s.geospatialRepo.Get(ctx, model.GeospatialFilter{Lat: &lat, Lng: &lng, Columns: []string{"id"}})
+	if err != nil {
+		logger.Error(ctx, "failed to get geospatial data by point", err)
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(regions))
+	for _, r := range regions {
+		ids = append(ids, r.ID)
+	}
+
+	geofences, err := s.geofenceRepo.GetContaining(ctx, lat, lng, ids)
+	if err != nil {
+		logger.Error(ctx, "failed to get geofences by point", err)
+		return nil, err
+	}
+
+	return geofences, nil
+}
+
+// nameEvents sets the fence names of events, from names or, for fences an object exited
+// and never was seen inside of by this request, from the repository.
+func (s *geofenceImpl) nameEvents(ctx context.Context, events []model.GeofenceEvent, names map[uint]string) error {
+	var missing []uint
+	for _, e := range events {
+		if _, ok := names[e.GeofenceID]; !ok {
+			missing = append(missing, e.GeofenceID)
+		}
+	}
+
+	if len(missing) > 0 {
+		geofences, err := s.geofenceRepo.GetGeofences(ctx, missing)
+		if err != nil {
+			logger.Error(ctx, "failed to get geofences by id", err)
+			return err
+		}
+		for _, g := range geofences {
+			names[g.ID] = g.Name
+		}
+	}
+
+	for i := range events {
+		events[i].GeofenceName = names[events[i].GeofenceID]
+	}
+	return nil
+}
@@ -2,35 +2,134 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
 
 	"github.com/si-bas/go-rest-geospatial/domain/model"
 	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/gadm"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
 	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/pkg/search"
 	"github.com/si-bas/go-rest-geospatial/shared"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
 	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/twpayne/go-geom"
 	"github.com/twpayne/go-geom/encoding/geojson"
 	"github.com/twpayne/go-geom/encoding/wkt"
 	"gorm.io/gorm"
 )
 
+// ErrGeospatialNotFound is returned for a region id that does not exist.
+var ErrGeospatialNotFound = errors.New("region not found")
+
+// maxUnionRegions caps how many regions Union dissolves at once.
+const maxUnionRegions = 5000
+
+var (
+	// ErrTooManyRegions is returned by Union for more than maxUnionRegions regions.
+	ErrTooManyRegions = fmt.Errorf("cannot dissolve more than %d regions at once", maxUnionRegions)
+	// ErrInvalidCustomName is returned by Union for a name without a letter or a digit.
+	ErrInvalidCustomName = errors.New("name must contain a letter or a digit")
+	// ErrNotCustomArea is returned by DeleteCustom for a region imported from GADM.
+	ErrNotCustomArea = errors.New("only custom areas can be removed")
+)
+
 type GeospatialService interface {
+	Get(context.Context, uint, model.GeospatialFilter) (*model.Geospatial, error)
+	Neighbors(context.Context, uint, model.GeospatialFilter) ([]model.Geospatial, error)
+	Ancestors(context.Context, []model.Geospatial) (map[uint][]model.Geospatial, error)
 	List(context.Context, model.GeospatialFilter) ([]model.Geospatial, error)
 	ListPaginate(context.Context, model.GeospatialFilter, pagination.Param) ([]model.Geospatial, *pagination.Param, error)
-	GetTypes(context.Context) ([]string, error)
-	GetLevels(context.Context) ([]uint, error)
+	Intersecting(context.Context, geom.T, model.GeospatialFilter, pagination.Param, bool) ([]model.Geospatial, *pagination.Param, error)
+	Union(context.Context, model.GeospatialFilter, string) (*model.GeospatialUnion, error)
+	DeleteCustom(context.Context, uint) error
+	GetTypes(context.Context, model.GeospatialFilter) ([]model.GeospatialType, error)
+	GetLevels(context.Context, model.GeospatialFilter) ([]model.GeospatialLevel, error)
+	Search(context.Context, string, model.GeospatialFilter, int) ([]model.Geospatial, error)
+	Autocomplete(context.Context, string, model.GeospatialFilter, model.AutocompleteOptions, int) ([]model.Suggestion, error)
+	Localize(context.Context, []model.Geospatial, string) ([]model.Geospatial, error)
+	Include(context.Context, []model.Geospatial, []string) ([]model.Geospatial, error)
 	CreateFromFeatureCollection(context.Context, *geojson.FeatureCollection) error
 	BuildTree([]model.Geospatial, uint) *model.Geospatial
 }
 
 type geospatialImpl struct {
 	geospatialRepo repository.GeospatialRepository
+	eventRepo      repository.EventRepository
 }
 
-func NewGeospatialService(geospatialRepo repository.GeospatialRepository) GeospatialService {
+// NewGeospatialService returns the service of the regions, their changes are written to the
+// outbox of eventRepo.
+func NewGeospatialService(geospatialRepo repository.GeospatialRepository, eventRepo repository.EventRepository) GeospatialService {
 	return &geospatialImpl{
 		geospatialRepo: geospatialRepo,
+		eventRepo:      eventRepo,
+	}
+}
+
+// Get returns the region with the given id, read like filter reads regions.
+func (s *geospatialImpl) Get(ctx context.Context, id uint, filter model.GeospatialFilter) (*model.Geospatial, error) {
+	filter.IDs = []uint{id}
+	geospatials, err := s.geospatialRepo.Get(ctx, filter)
+	if err != nil {
+		logger.Error(ctx, "failed to get geospatial data by id", err)
+		return nil, err
+	}
+	if len(geospatials) == 0 {
+		return nil, ErrGeospatialNotFound
 	}
+
+	return &geospatials[0], nil
+}
+
+// Neighbors returns the regions of the same level bordering the region with the given id,
+// read like filter reads regions, longest border first.
+func (s *geospatialImpl) Neighbors(ctx context.Context, id uint, filter model.GeospatialFilter) ([]model.Geospatial, error) {
+	if _, err := s.Get(ctx, id, model.GeospatialFilter{Columns: []string{"id"}}); err != nil {
+		return nil, err
+	}
+
+	neighbors, err := s.geospatialRepo.GetNeighbors(ctx, id)
+	if err != nil {
+		logger.Error(ctx, "failed to get neighbors of geospatial data", err)
+		return nil, err
+	}
+	if len(neighbors) == 0 {
+		return []model.Geospatial{}, nil
+	}
+
+	filter.IDs = make([]uint, 0, len(neighbors))
+	for _, n := range neighbors {
+		filter.IDs = append(filter.IDs, n.NeighborID)
+	}
+	geospatials, err := s.geospatialRepo.Get(ctx, filter)
+	if err != nil {
+		logger.Error(ctx, "failed to get geospatial data by id", err)
+		return nil, err
+	}
+
+	byID := make(map[uint]model.Geospatial, len(geospatials))
+	for _, g := range geospatials {
+		byID[g.ID] = g
+	}
+	result := make([]model.Geospatial, 0, len(neighbors))
+	for _, n := range neighbors {
+		g, ok := byID[n.NeighborID]
+		if !ok {
+			continue
+		}
+		borderKm := n.BorderKm
+		g.BorderKm = &borderKm
+		result = append(result, g)
+	}
+
+	return result, nil
 }
 
 func (s *geospatialImpl) List(ctx context.Context, filter model.GeospatialFilter) ([]model.Geospatial, error) {
@@ -49,7 +148,7 @@ func (s *geospatialImpl) List(ctx context.Context, filter model.GeospatialFilter
 func (s *geospatialImpl) ListPaginate(ctx context.Context, filter model.GeospatialFilter, query pagination.Param) ([]model.Geospatial, *pagination.Param, error) {
 	geospatials, meta, err := s.geospatialRepo.GetPaginate(ctx, filter, query)
 	if err != nil {
-		if err != gorm.ErrRecordNotFound {
+		if err != gorm.ErrRecordNotFound && err != pagination.ErrInvalidCursor && err != pagination.ErrInvalidSort {
 			logger.Error(ctx, "failed to get geospatial data with filter", err)
 		}
 
@@ -59,8 +158,263 @@ func (s *geospatialImpl) ListPaginate(ctx context.Context, filter model.Geospati
 	return geospatials, meta, nil
 }
 
-func (s *geospatialImpl) GetTypes(ctx context.Context) ([]string, error) {
-	types, err := s.geospatialRepo.GetTypes(ctx)
+// Intersecting returns a page of the regions matching filter that share a point with t, a
+// polygon or a line. With overlap every region also gets how much of t lies inside it, as an
+// area for a polygon or a length for a line, and as a percentage of t.
+func (s *geospatialImpl) Intersecting(ctx context.Context, t geom.T, filter model.GeospatialFilter, query pagination.Param, overlap bool) ([]model.Geospatial, *pagination.Param, error) {
+	intersects, err := wkt.Marshal(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter.Intersects = intersects
+	filter.WithGeometry = filter.WithGeometry || overlap
+
+	geospatials, meta, err := s.ListPaginate(ctx, filter, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if overlap {
+		if err := measureOverlap(geospatials, t); err != nil {
+			logger.Error(ctx, "failed to measure overlap of geospatial data", err)
+			return nil, nil, err
+		}
+	}
+
+	return geospatials, meta, nil
+}
+
+// measureOverlap sets how much of t lies in each of geospatials, which are read with their
+// geometry.
+func measureOverlap(geospatials []model.Geospatial, t geom.T) error {
+	input := geo.ToMultiPolygon(t)
+	var total float64
+	if input != nil {
+		total = geo.GeodesicArea(input)
+	} else {
+		total = geo.LineLength(t)
+	}
+
+	for i := range geospatials {
+		g := &geospatials[i]
+		region, err := wkt.Unmarshal(g.Geometry)
+		if err != nil {
+			return err
+		}
+		mp := geo.ToMultiPolygon(region)
+		if mp == nil {
+			continue
+		}
+
+		var covered float64
+		if input != nil {
+			covered = geo.IntersectionArea(mp, input)
+			km2 := covered / 1e6
+			g.OverlapKm2 = &km2
+		} else {
+			covered = geo.IntersectionLength(mp, t)
+			km := covered / 1000
+			g.OverlapKm = &km
+		}
+		// A geometry may be covered by more than its size by rounding along shared edges.
+		pct := 0.0
+		if total > 0 {
+			pct = math.Min(100*covered/total, 100)
+		}
+		g.OverlapPct = &pct
+	}
+
+	return nil
+}
+
+// Union dissolves the regions matching filter into one area. Regions below another matching
+// region are covered by it and left out. With a name the union is saved as a custom area, at
+// the level of the highest region dissolved and below their parent when they share one, and
+// can be queried like any other region.
+func (s *geospatialImpl) Union(ctx context.Context, filter model.GeospatialFilter, saveAs string) (*model.GeospatialUnion, error) {
+	var gadmID string
+	if saveAs != "" {
+		slug := strings.ReplaceAll(search.Fold(saveAs), " ", "-")
+		if slug == "" {
+			return nil, ErrInvalidCustomName
+		}
+		gadmID = gadm.CustomIDPrefix + slug
+	}
+
+	filter.Columns = []string{"id", "gadm_id", "parent_gadm_id", "level"}
+	filter.WithGeometry = false
+	regions, err := s.geospatialRepo.Get(ctx, filter)
+	if err != nil {
+		logger.Error(ctx, "failed to get geospatial data with filter", err)
+		return nil, err
+	}
+	if len(regions) == 0 {
+		return nil, ErrGeospatialNotFound
+	}
+	if len(regions) > maxUnionRegions {
+		return nil, ErrTooManyRegions
+	}
+
+	regions = outermostRegions(regions)
+	ids := make([]uint, 0, len(regions))
+	for _, g := range regions {
+		ids = append(ids, g.ID)
+	}
+	withGeometry, err := s.geospatialRepo.GetWithGeometry(ctx, model.GeospatialFilter{IDs: ids, Columns: []string{"id"}})
+	if err != nil {
+		logger.Error(ctx, "failed to get geospatial data by id", err)
+		return nil, err
+	}
+
+	mps := make([]*geom.MultiPolygon, 0, len(withGeometry))
+	for _, g := range withGeometry {
+		t, err := wkt.Unmarshal(g.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		if mp := geo.ToMultiPolygon(t); mp != nil {
+			mps = append(mps, mp)
+		}
+	}
+
+	dissolved := geo.Dissolve(mps)
+	shape, err := geojson.Marshal(dissolved)
+	if err != nil {
+		return nil, err
+	}
+	attrs := geo.Measure(dissolved)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	union := &model.GeospatialUnion{
+		Geometry:    shape,
+		AreaKm2:     attrs.AreaKm2,
+		PerimeterKm: attrs.PerimeterKm,
+		Bbox:        []float64{attrs.Bounds.Min(0), attrs.Bounds.Min(1), attrs.Bounds.Max(0), attrs.Bounds.Max(1)},
+		RegionIDs:   ids,
+	}
+
+	if gadmID == "" {
+		return union, nil
+	}
+
+	geometry, err := wkt.Marshal(dissolved)
+	if err != nil {
+		return nil, err
+	}
+	custom := model.Geospatial{
+		GadmID:       gadmID,
+		ParentGadmID: commonParent(regions),
+		Name:         saveAs,
+		Type:         "Custom",
+		EngType:      constant.EngtypeCustom,
+		Level:        highestLevel(regions),
+		Geometry:     geometry,
+	}
+	gadm.SetGeometryAttributes(&custom, attrs)
+	events, _, _, err := s.regionEvents(ctx, []model.Geospatial{custom})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.geospatialRepo.UpsertBulk(ctx, []model.Geospatial{custom}, events...); err != nil {
+		logger.Error(ctx, "failed to save custom area", err)
+		return nil, err
+	}
+	if err := s.geospatialRepo.IndexNeighbors(ctx); err != nil {
+		logger.Error(ctx, "failed to index neighbors of geospatial data", err)
+		return nil, err
+	}
+
+	refs, err := s.geospatialRepo.GetByGadmIds(ctx, []string{gadmID})
+	if err != nil {
+		logger.Error(ctx, "failed to get geospatial data by gadm id", err)
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, ErrGeospatialNotFound
+	}
+	if union.Saved, err = s.Get(ctx, refs[0].ID, model.GeospatialFilter{}); err != nil {
+		return nil, err
+	}
+
+	return union, nil
+}
+
+// DeleteCustom removes the custom area with the given id and writes its retired event to the
+// outbox. Regions imported from GADM are only replaced by imports, they cannot be removed.
+func (s *geospatialImpl) DeleteCustom(ctx context.Context, id uint) error {
+	custom, err := s.Get(ctx, id, model.GeospatialFilter{})
+	if err != nil {
+		return err
+	}
+	if custom.EngType != constant.EngtypeCustom {
+		return ErrNotCustomArea
+	}
+
+	event, err := regionEvent(constant.EventRegionRetired, *custom)
+	if err != nil {
+		return err
+	}
+	if err := s.geospatialRepo.Delete(ctx, []uint{id}, event); err != nil {
+		logger.Error(ctx, "failed to delete custom area", err)
+		return err
+	}
+
+	return nil
+}
+
+// outermostRegions leaves out the regions below another one of regions.
+func outermostRegions(regions []model.Geospatial) []model.Geospatial {
+	stems := make(map[string]bool, len(regions))
+	for _, g := range regions {
+		stems[gadm.Stem(g.GadmID)] = true
+	}
+
+	outermost := make([]model.Geospatial, 0, len(regions))
+	for _, g := range regions {
+		below := false
+		for stem := gadm.Stem(g.GadmID); !below; {
+			i := strings.LastIndexByte(stem, '.')
+			if i < 0 {
+				break
+			}
+			stem = stem[:i]
+			below = stems[stem]
+		}
+		if !below {
+			outermost = append(outermost, g)
+		}
+	}
+	return outermost
+}
+
+// commonParent is the parent GADM id regions share, or empty when they do not share one.
+func commonParent(regions []model.Geospatial) string {
+	parent := regions[0].ParentGadmID
+	for _, g := range regions[1:] {
+		if g.ParentGadmID != parent {
+			return ""
+		}
+	}
+	return parent
+}
+
+func highestLevel(regions []model.Geospatial) uint {
+	level := regions[0].Level
+	for _, g := range regions[1:] {
+		if g.Level < level {
+			level = g.Level
+		}
+	}
+	return level
+}
+
+// levelExamples is how many example regions GetLevels returns per level.
+const levelExamples = 3
+
+// GetTypes returns the normalized types of the regions matching filter in catalogue order,
+// each with its region count per level, the local types it covers there and the countries
+// it occurs in.
+func (s *geospatialImpl) GetTypes(ctx context.Context, filter model.GeospatialFilter) ([]model.GeospatialType, error) {
+	counts, err := s.geospatialRepo.GetTypes(ctx, filter)
 	if err != nil {
 		if err != gorm.ErrRecordNotFound {
 			logger.Error(ctx, "failed to get geospatial types", err)
@@ -69,51 +423,535 @@ func (s *geospatialImpl) GetTypes(ctx context.Context) ([]string, error) {
 		return nil, err
 	}
 
+	countries, err := s.countries(ctx, counts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The counts are ordered by level, then by type, so the rows of a type at a level and of
+	// a local type within it are adjacent.
+	var types []model.GeospatialType
+	typeCountries := make(map[string][]string)
+	byEngType := make(map[string]int)
+	for _, c := range counts {
+		i, ok := byEngType[c.EngType]
+		if !ok {
+			i = len(types)
+			byEngType[c.EngType] = i
+			types = append(types, model.GeospatialType{EngType: c.EngType})
+		}
+		t := &types[i]
+		t.Count += c.Count
+		typeCountries[c.EngType] = append(typeCountries[c.EngType], c.Country)
+
+		if n := len(t.Levels); n == 0 || t.Levels[n-1].Level != c.Level {
+			t.Levels = append(t.Levels, model.GeospatialTypeLevel{Level: c.Level})
+		}
+		level := &t.Levels[len(t.Levels)-1]
+		level.Count += c.Count
+		if n := len(level.LocalTypes); n == 0 || level.LocalTypes[n-1] != c.Type {
+			level.LocalTypes = append(level.LocalTypes, c.Type)
+		}
+	}
+
+	for i := range types {
+		types[i].Countries = pickCountries(countries, typeCountries[types[i].EngType])
+	}
+	sort.SliceStable(types, func(i, j int) bool {
+		return engtypeRank(types[i].EngType) < engtypeRank(types[j].EngType)
+	})
+
 	return types, nil
 }
 
-func (s *geospatialImpl) GetLevels(ctx context.Context) ([]uint, error) {
-	levels, err := s.geospatialRepo.GetLevels(ctx)
+// engtypeRank is the position of engType in constant.Engtypes, unknown types go last.
+func engtypeRank(engType string) int {
+	for i, t := range constant.Engtypes {
+		if t == engType {
+			return i
+		}
+	}
+	return len(constant.Engtypes)
+}
+
+// GetLevels returns the levels of the regions matching filter, each with its region count,
+// its types, the countries it occurs in and its largest regions as examples.
+func (s *geospatialImpl) GetLevels(ctx context.Context, filter model.GeospatialFilter) ([]model.GeospatialLevel, error) {
+	counts, err := s.geospatialRepo.GetTypes(ctx, filter)
 	if err != nil {
 		if err != gorm.ErrRecordNotFound {
-			logger.Error(ctx, "failed to get geospatial types", err)
+			logger.Error(ctx, "failed to get geospatial levels", err)
 		}
 
 		return nil, err
 	}
 
+	countries, err := s.countries(ctx, counts)
+	if err != nil {
+		return nil, err
+	}
+
+	var levels []model.GeospatialLevel
+	var levelCountries [][]string
+	for _, c := range counts {
+		if n := len(levels); n == 0 || levels[n-1].Level != c.Level {
+			levels = append(levels, model.GeospatialLevel{Level: c.Level})
+			levelCountries = append(levelCountries, nil)
+		}
+		level := &levels[len(levels)-1]
+		level.Count += c.Count
+		levelCountries[len(levels)-1] = append(levelCountries[len(levels)-1], c.Country)
+
+		if n := len(level.Types); n == 0 || level.Types[n-1].EngType != c.EngType || level.Types[n-1].Type != c.Type {
+			level.Types = append(level.Types, model.GeospatialLevelType{Type: c.Type, EngType: c.EngType})
+		}
+		level.Types[len(level.Types)-1].Count += c.Count
+	}
+
+	for i := range levels {
+		levels[i].Countries = pickCountries(countries, levelCountries[i])
+
+		levelFilter := filter
+		levelFilter.Levels = []uint{levels[i].Level}
+		examples, err := s.geospatialRepo.GetExamples(ctx, levelFilter, levelExamples)
+		if err != nil {
+			logger.Error(ctx, "failed to get geospatial level examples", err)
+			return nil, err
+		}
+
+		levels[i].Examples = make([]model.GeospatialExample, 0, len(examples))
+		for _, g := range examples {
+			levels[i].Examples = append(levels[i].Examples, model.GeospatialExample{ID: g.ID, Name: g.Name, Type: g.Type, AreaKm2: g.AreaKm2})
+		}
+	}
+
 	return levels, nil
 }
 
-func (s *geospatialImpl) CreateFromFeatureCollection(ctx context.Context, fc *geojson.FeatureCollection) error {
-	var geospatials []model.Geospatial
-	for _, f := range fc.Features {
+// countries returns the countries counts occur in by code, named after their level 1 region.
+func (s *geospatialImpl) countries(ctx context.Context, counts []model.GeospatialTypeCount) (map[string]model.GeospatialCountry, error) {
+	countries := make(map[string]model.GeospatialCountry)
+	var codes []string
+	for _, c := range counts {
+		if _, ok := countries[c.Country]; !ok {
+			countries[c.Country] = model.GeospatialCountry{Code: c.Country}
+			codes = append(codes, c.Country)
+		}
+	}
+	if len(codes) == 0 {
+		return countries, nil
+	}
+
+	regions, err := s.geospatialRepo.GetByGadmIds(ctx, codes)
+	if err != nil {
+		logger.Error(ctx, "failed to get countries", err)
+		return nil, err
+	}
+	for _, g := range regions {
+		if g.Level == 1 {
+			countries[g.GadmID] = model.GeospatialCountry{Code: g.GadmID, Name: g.Name}
+		}
+	}
+
+	return countries, nil
+}
+
+// pickCountries returns the distinct countries of codes, ordered by code.
+func pickCountries(countries map[string]model.GeospatialCountry, codes []string) []model.GeospatialCountry {
+	seen := make(map[string]bool)
+	picked := make([]model.GeospatialCountry, 0, len(codes))
+	for _, code := range codes {
+		if !seen[code] {
+			seen[code] = true
+			picked = append(picked, countries[code])
+		}
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].Code < picked[j].Code })
+	return picked
+}
+
+func (s *geospatialImpl) Search(ctx context.Context, query string, filter model.GeospatialFilter, limit int) ([]model.Geospatial, error) {
+	geospatials, err := s.geospatialRepo.Search(ctx, query, filter, limit)
+	if err != nil {
+		logger.Error(ctx, "failed to search geospatial data by name", err)
+		return nil, err
+	}
+
+	return geospatials, nil
+}
+
+const (
+	// autocompleteCandidates is how many search results per suggestion are reordered by the bias.
+	autocompleteCandidates = 3
+	// autocompleteBias is the most a bias adds to a score, enough to reorder matches of a kind.
+	autocompleteBias = 0.1
+	// autocompleteBiasKm is the distance at which the point bias has dropped to a third.
+	autocompleteBiasKm = 100
+)
+
+func (s *geospatialImpl) Autocomplete(ctx context.Context, query string, filter model.GeospatialFilter, opts model.AutocompleteOptions, limit int) ([]model.Suggestion, error) {
+	matches, err := s.geospatialRepo.Search(ctx, query, filter, limit*autocompleteCandidates)
+	if err != nil {
+		logger.Error(ctx, "failed to search geospatial data for autocomplete", err)
+		return nil, err
+	}
+
+	ancestors, err := s.ancestors(ctx, matches)
+	if err != nil {
+		logger.Error(ctx, "failed to get ancestors of autocomplete suggestions", err)
+		return nil, err
+	}
+
+	if opts.Lang != "" {
+		if matches, err = s.Localize(ctx, matches, opts.Lang); err != nil {
+			return nil, err
+		}
+
+		var parents []model.Geospatial
+		for _, parent := range ancestors {
+			parents = append(parents, parent)
+		}
+		if parents, err = s.Localize(ctx, parents, opts.Lang); err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			ancestors[parent.GadmID] = parent
+		}
+	}
+
+	suggestions := make([]model.Suggestion, 0, len(matches))
+	for _, g := range matches {
+		suggestion := model.Suggestion{
+			ID:         g.ID,
+			Name:       g.Name,
+			Type:       g.Type,
+			Level:      g.Level,
+			Match:      g.Match,
+			LabelPoint: g.LabelPoint(),
+			Ancestors:  make([]model.Ancestor, 0),
+		}
+		if g.Score != nil {
+			suggestion.Score = *g.Score
+		}
+
+		label := []string{g.Name}
+		inParent := opts.ParentID != 0 && g.ID == opts.ParentID
+		parent, ok := ancestors[g.ParentGadmID]
+		for depth := 0; ok && depth <= gadm.MaxLevel; depth++ {
+			suggestion.Ancestors = append(suggestion.Ancestors, model.Ancestor{
+				ID:    parent.ID,
+				Name:  parent.Name,
+				Type:  parent.Type,
+				Level: parent.Level,
+			})
+			// Countries are left out of the label, like a postal address would.
+			if parent.Level > 1 {
+				label = append(label, parent.Name)
+			}
+			if parent.ID == opts.ParentID {
+				inParent = true
+			}
+			parent, ok = ancestors[parent.ParentGadmID]
+		}
+		suggestion.Label = strings.Join(label, ", ")
+
+		if inParent {
+			suggestion.Score += autocompleteBias
+		}
+		if opts.Point != nil {
+			if point := g.LabelPoint(); point != nil {
+				km := geo.Haversine(opts.Point.Lng, opts.Point.Lat, point.Lng, point.Lat) / 1000
+				suggestion.Score += autocompleteBias * math.Exp(-km/autocompleteBiasKm)
+			}
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+// Localize replaces the name of every region that has an official, or else a variant, name in
+// lang, keeping the original as OfficialName. "zh" also matches names tagged "zh-Hant".
+func (s *geospatialImpl) Localize(ctx context.Context, geospatials []model.Geospatial, lang string) ([]model.Geospatial, error) {
+	if lang == "" || len(geospatials) == 0 {
+		return geospatials, nil
+	}
+
+	var ids []uint
+	for _, g := range geospatials {
+		ids = append(ids, g.ID)
+	}
+
+	names, err := s.geospatialRepo.GetNames(ctx, ids)
+	if err != nil {
+		logger.Error(ctx, "failed to get alternate names of geospatial data", err)
+		return nil, err
+	}
+
+	preferred := make(map[uint]model.GeospatialName)
+	for _, name := range names {
+		if name.Kind == constant.NameKindHistorical || !matchesLanguage(name.Language, lang) {
+			continue
+		}
+		if current, ok := preferred[name.GeospatialID]; ok && current.Kind == constant.NameKindOfficial {
+			continue
+		}
+		preferred[name.GeospatialID] = name
+	}
+
+	localized := make([]model.Geospatial, len(geospatials))
+	for i, g := range geospatials {
+		if name, ok := preferred[g.ID]; ok && name.Name != g.Name {
+			g.OfficialName = g.Name
+			g.Name = name.Name
+		}
+		localized[i] = g
+	}
+
+	return localized, nil
+}
+
+// refColumns are the columns of model.GeospatialRef, and the parent GADM id to nest by.
+var refColumns = []string{"id", "gadm_id", "parent_gadm_id", "name", "type", "eng_type", "level"}
+
+// Include adds the related data named by include to every region: its geometry as GeoJSON,
+// its parent or its children. Regions read without their geometry get it from the
+// repository.
+func (s *geospatialImpl) Include(ctx context.Context, geospatials []model.Geospatial, include []string) ([]model.Geospatial, error) {
+	if len(include) == 0 || len(geospatials) == 0 {
+		return geospatials, nil
+	}
+
+	included := make([]model.Geospatial, len(geospatials))
+	copy(included, geospatials)
+
+	for _, name := range include {
+		var err error
+		switch name {
+		case model.IncludeGeometry:
+			err = s.includeGeometry(ctx, included)
+		case model.IncludeParent:
+			err = s.includeParent(ctx, included)
+		case model.IncludeChildren:
+			err = s.includeChildren(ctx, included)
+		}
+		if err != nil {
+			logger.Error(ctx, "failed to include "+name+" of geospatial data", err)
+			return nil, err
+		}
+	}
+
+	return included, nil
+}
+
+func (s *geospatialImpl) includeGeometry(ctx context.Context, geospatials []model.Geospatial) error {
+	var missing []uint
+	for _, g := range geospatials {
+		if g.Geometry == "" {
+			missing = append(missing, g.ID)
+		}
+	}
+
+	geometries := make(map[uint]string)
+	if len(missing) > 0 {
+		withGeometry, err := s.geospatialRepo.GetWithGeometry(ctx, model.GeospatialFilter{IDs: missing, Columns: []string{"id"}})
+		if err != nil {
+			return err
+		}
+		for _, g := range withGeometry {
+			geometries[g.ID] = g.Geometry
+		}
+	}
+
+	for i := range geospatials {
+		g := &geospatials[i]
+		if g.Geometry == "" {
+			g.Geometry = geometries[g.ID]
+		}
+		if g.Geometry == "" {
+			continue
+		}
+
+		t, err := wkt.Unmarshal(g.Geometry)
+		if err != nil {
+			return err
+		}
+		if g.Shape, err = geojson.Marshal(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-		// Get level
-		var level int
-		for i := 0; i <= 4; i++ {
-			if f.Properties[fmt.Sprintf("GID_%d", i)] == nil {
+func (s *geospatialImpl) includeParent(ctx context.Context, geospatials []model.Geospatial) error {
+	var gadmIds []string
+	for _, g := range geospatials {
+		if g.ParentGadmID != "" {
+			gadmIds = append(gadmIds, g.ParentGadmID)
+		}
+	}
+
+	parents, err := s.geospatialRepo.GetByGadmIds(ctx, gadmIds)
+	if err != nil {
+		return err
+	}
+	byGadmID := make(map[string]model.GeospatialRef)
+	for _, parent := range parents {
+		byGadmID[parent.GadmID] = parent.Ref()
+	}
+
+	for i := range geospatials {
+		if parent, ok := byGadmID[geospatials[i].ParentGadmID]; ok {
+			geospatials[i].Parent = &parent
+		}
+	}
+
+	return nil
+}
+
+func (s *geospatialImpl) includeChildren(ctx context.Context, geospatials []model.Geospatial) error {
+	var ids []uint
+	for _, g := range geospatials {
+		ids = append(ids, g.ID)
+	}
+
+	children, err := s.geospatialRepo.Get(ctx, model.GeospatialFilter{ParentIds: ids, Columns: refColumns})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].ID < children[j].ID
+	})
+	byParent := make(map[string][]model.GeospatialRef)
+	for _, child := range children {
+		byParent[child.ParentGadmID] = append(byParent[child.ParentGadmID], child.Ref())
+	}
+
+	for i := range geospatials {
+		geospatials[i].Children = byParent[geospatials[i].GadmID]
+	}
+
+	return nil
+}
+
+func matchesLanguage(language, lang string) bool {
+	if strings.EqualFold(language, lang) {
+		return true
+	}
+	base, _, _ := strings.Cut(language, "-")
+	return language != "" && strings.EqualFold(base, lang)
+}
+
+// Ancestors returns the regions above each of geospatials by its id, the country first. The
+// ancestors of all of geospatials are read together, one query per level.
+func (s *geospatialImpl) Ancestors(ctx context.Context, geospatials []model.Geospatial) (map[uint][]model.Geospatial, error) {
+	byGadmID, err := s.ancestors(ctx, geospatials)
+	if err != nil {
+		logger.Error(ctx, "failed to get ancestors of geospatial data", err)
+		return nil, err
+	}
+
+	// The ancestors were read by GADM id with their key columns only.
+	var ids []uint
+	for _, a := range byGadmID {
+		ids = append(ids, a.ID)
+	}
+	full := make(map[uint]model.Geospatial, len(ids))
+	if len(ids) > 0 {
+		regions, err := s.geospatialRepo.Get(ctx, model.GeospatialFilter{IDs: ids})
+		if err != nil {
+			logger.Error(ctx, "failed to get geospatial data by id", err)
+			return nil, err
+		}
+		for _, r := range regions {
+			full[r.ID] = r
+		}
+	}
+
+	res := make(map[uint][]model.Geospatial, len(geospatials))
+	for _, g := range geospatials {
+		chain := []model.Geospatial{}
+		parentID := g.ParentGadmID
+		for i := 0; i <= gadm.MaxLevel && parentID != ""; i++ {
+			parent, ok := byGadmID[parentID]
+			if !ok {
 				break
 			}
+			parentID = parent.ParentGadmID
+			if f, ok := full[parent.ID]; ok {
+				parent = f
+			}
+			chain = append([]model.Geospatial{parent}, chain...)
+		}
+		res[g.ID] = chain
+	}
 
-			level = i
+	return res, nil
+}
+
+// ancestors returns every region above geospatials by GADM id, fetched one level per query.
+func (s *geospatialImpl) ancestors(ctx context.Context, geospatials []model.Geospatial) (map[string]model.Geospatial, error) {
+	ancestors := make(map[string]model.Geospatial)
+	queued := make(map[string]bool)
+
+	var gadmIds []string
+	enqueue := func(gadmID string) {
+		if gadmID != "" && !queued[gadmID] {
+			queued[gadmID] = true
+			gadmIds = append(gadmIds, gadmID)
 		}
+	}
+	for _, g := range geospatials {
+		enqueue(g.ParentGadmID)
+	}
 
-		wktEncoder := wkt.NewEncoder()
-		mpStr, _ := wktEncoder.Encode(f.Geometry)
+	for i := 0; i <= gadm.MaxLevel && len(gadmIds) > 0; i++ {
+		parents, err := s.geospatialRepo.GetByGadmIds(ctx, gadmIds)
+		if err != nil {
+			return nil, err
+		}
 
-		geospatial := model.Geospatial{
-			GadmID:   f.Properties[fmt.Sprintf("GID_%d", level)].(string),
-			Name:     f.Properties["COUNTRY"].(string),
-			Type:     "Country",
-			Level:    uint(level + 1),
-			Geometry: mpStr,
+		gadmIds = nil
+		for _, parent := range parents {
+			ancestors[parent.GadmID] = parent
+			enqueue(parent.ParentGadmID)
 		}
+	}
+
+	return ancestors, nil
+}
+
+// CreateFromFeatureCollection upserts the regions of fc. Every new or changed region is written
+// to the outbox as created or updated with its chunk, the import as completed or failed once it
+// is done.
+func (s *geospatialImpl) CreateFromFeatureCollection(ctx context.Context, fc *geojson.FeatureCollection) error {
+	summary := model.ImportEvent{Features: len(fc.Features)}
+	if err := s.createFromFeatureCollection(ctx, fc, &summary); err != nil {
+		summary.Error = err.Error()
+		addEvent(ctx, s.eventRepo, constant.EventImportFailed, summary)
+		return err
+	}
+
+	addEvent(ctx, s.eventRepo, constant.EventImportCompleted, summary)
+	return nil
+}
 
-		if level > 0 {
-			geospatial.ParentGadmID = f.Properties[fmt.Sprintf("GID_%d", level-1)].(string)
-			geospatial.Name = f.Properties[fmt.Sprintf("NAME_%d", level)].(string)
-			geospatial.Type = f.Properties[fmt.Sprintf("TYPE_%d", level)].(string)
+func (s *geospatialImpl) createFromFeatureCollection(ctx context.Context, fc *geojson.FeatureCollection, summary *model.ImportEvent) error {
+	var geospatials []model.Geospatial
+	for _, f := range fc.Features {
+		geospatial, err := gadm.ParseFeature(f)
+		if err != nil {
+			logger.Warn(ctx, "failed to parse feature", tag.Err(err))
+			return err
 		}
 
 		geospatials = append(geospatials, geospatial)
@@ -123,13 +961,21 @@ func (s *geospatialImpl) CreateFromFeatureCollection(ctx context.Context, fc *ge
 	geospatialChunks := shared.ChunkGeospatialData(geospatials, chunkSize)
 
 	var result []error
+	var created, updated int64
 	ch := make(chan error, len(geospatialChunks))
 	for _, c := range geospatialChunks {
 		go func(ctx context.Context, data []model.Geospatial, result chan<- error) {
-			if err := s.geospatialRepo.UpsertBulk(ctx, data); err != nil {
+			events, nCreated, nUpdated, err := s.regionEvents(ctx, data)
+			if err != nil {
 				result <- err
 				return
 			}
+			if err := s.geospatialRepo.UpsertBulk(ctx, data, events...); err != nil {
+				result <- err
+				return
+			}
+			atomic.AddInt64(&created, int64(nCreated))
+			atomic.AddInt64(&updated, int64(nUpdated))
 			result <- nil
 		}(ctx, c, ch)
 	}
@@ -146,10 +992,98 @@ func (s *geospatialImpl) CreateFromFeatureCollection(ctx context.Context, fc *ge
 			return err
 		}
 	}
+	summary.Created = int(created)
+	summary.Updated = int(updated)
+
+	// Neighbours are found once every chunk is written, they may border regions of another.
+	if err := s.geospatialRepo.IndexNeighbors(ctx); err != nil {
+		logger.Error(ctx, "failed to index neighbors of geospatial data", err)
+		return err
+	}
 
 	return nil
 }
 
+// regionEvents returns the events of upserting geospatials and how many regions are created
+// and updated. A region is created unless a region with its GADM id exists, which is updated
+// when one of its columns changes and left without an event otherwise.
+func (s *geospatialImpl) regionEvents(ctx context.Context, geospatials []model.Geospatial) ([]model.OutboxEvent, int, int, error) {
+	gadmIds := make([]string, 0, len(geospatials))
+	for _, g := range geospatials {
+		gadmIds = append(gadmIds, g.GadmID)
+	}
+	existing, err := s.geospatialRepo.GetByGadmIds(ctx, gadmIds)
+	if err != nil {
+		logger.Error(ctx, "failed to get geospatial data by gadm id", err)
+		return nil, 0, 0, err
+	}
+	stored := make(map[string]model.Geospatial, len(existing))
+	for _, e := range existing {
+		stored[e.GadmID] = e
+	}
+
+	var created, updated int
+	events := make([]model.OutboxEvent, 0, len(geospatials))
+	for _, g := range geospatials {
+		eventType := constant.EventRegionCreated
+		if e, ok := stored[g.GadmID]; !ok {
+			created++
+		} else if sameRegion(g, e) {
+			continue
+		} else {
+			eventType = constant.EventRegionUpdated
+			updated++
+		}
+
+		event, err := regionEvent(eventType, g)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		events = append(events, event)
+	}
+
+	return events, created, updated, nil
+}
+
+// sameRegion reports whether upserting g leaves the stored region e as it is. The geometry is
+// compared by its attributes, they are derived from it and read without it.
+func sameRegion(g, e model.Geospatial) bool {
+	engType := g.EngType
+	if engType == "" {
+		engType = constant.EngtypeOther
+	}
+	return g.ParentGadmID == e.ParentGadmID && g.Name == e.Name && g.Type == e.Type && engType == e.EngType && g.Level == e.Level &&
+		sameFloat(g.CentroidLat, e.CentroidLat) && sameFloat(g.CentroidLng, e.CentroidLng) &&
+		sameFloat(g.LabelLat, e.LabelLat) && sameFloat(g.LabelLng, e.LabelLng) &&
+		sameFloat(g.AreaKm2, e.AreaKm2) && sameFloat(g.PerimeterKm, e.PerimeterKm) &&
+		sameFloat(g.BboxMinLng, e.BboxMinLng) && sameFloat(g.BboxMinLat, e.BboxMinLat) &&
+		sameFloat(g.BboxMaxLng, e.BboxMaxLng) && sameFloat(g.BboxMaxLat, e.BboxMaxLat)
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// regionEvent returns the outbox event of type eventType about g.
+func regionEvent(eventType string, g model.Geospatial) (model.OutboxEvent, error) {
+	// The repository stores regions without an English type as other.
+	engType := g.EngType
+	if engType == "" {
+		engType = constant.EngtypeOther
+	}
+	return newEvent(eventType, model.RegionEvent{
+		GadmID:       g.GadmID,
+		ParentGadmID: g.ParentGadmID,
+		Name:         g.Name,
+		Type:         g.Type,
+		EngType:      engType,
+		Level:        g.Level,
+	})
+}
+
 func (s *geospatialImpl) BuildTree(geos []model.Geospatial, rootLevel uint) *model.Geospatial {
 	// Find the root node with the smallest level
 	var root *model.Geospatial
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+	"github.com/twpayne/go-geom/encoding/wkt"
+)
+
+// layerImportChunkSize is how many features of an import are written at once.
+const layerImportChunkSize = 1000
+
+var (
+	// ErrLayerNotFound is returned for a layer name that does not exist.
+	ErrLayerNotFound = errors.New("layer not found")
+	// ErrInvalidLayerFeature is returned by CreateFromFeatureCollection for a feature that is
+	// not a polygon.
+	ErrInvalidLayerFeature = errors.New("layer features must be a Polygon or a MultiPolygon")
+)
+
+type LayerService interface {
+	List(context.Context) ([]model.Layer, error)
+	Get(context.Context, string) (*model.Layer, error)
+	Delete(context.Context, string) error
+	Features(context.Context, string, model.LayerFeatureFilter, pagination.Param) ([]model.LayerFeature, *pagination.Param, error)
+	Intersecting(context.Context, string, geom.T, model.LayerFeatureFilter, pagination.Param) ([]model.LayerFeature, *pagination.Param, error)
+	CreateFromFeatureCollection(context.Context, string, *geojson.FeatureCollection) (*model.Layer, error)
+}
+
+type layerImpl struct {
+	layerRepo repository.LayerRepository
+}
+
+func NewLayerService(layerRepo repository.LayerRepository) LayerService {
+	return &layerImpl{
+		layerRepo: layerRepo,
+	}
+}
+
+func (s *layerImpl) List(ctx context.Context) ([]model.Layer, error) {
+	layers, err := s.layerRepo.GetLayers(ctx, nil)
+	if err != nil {
+		logger.Error(ctx, "failed to get layers", err)
+		return nil, err
+	}
+
+	return layers, nil
+}
+
+// Get returns the layer with the given name and the number of its features.
+func (s *layerImpl) Get(ctx context.Context, name string) (*model.Layer, error) {
+	layers, err := s.layerRepo.GetLayers(ctx, []string{name})
+	if err != nil {
+		logger.Error(ctx, "failed to get layer by name", err)
+		return nil, err
+	}
+	if len(layers) == 0 {
+		return nil, ErrLayerNotFound
+	}
+
+	return &layers[0], nil
+}
+
+// Delete deletes the layer with the given name and its features.
+func (s *layerImpl) Delete(ctx context.Context, name string) error {
+	layer, err := s.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.layerRepo.DeleteLayer(ctx, layer.ID); err != nil {
+		logger.Error(ctx, "failed to delete layer", err)
+		return err
+	}
+
+	return nil
+}
+
+// Features returns a page of the features of the layer with the given name matching filter,
+// with their geometry as GeoJSON when filter asks for it.
+func (s *layerImpl) Features(ctx context.Context, name string, filter model.LayerFeatureFilter, query pagination.Param) ([]model.LayerFeature, *pagination.Param, error) {
+	layer, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter.LayerID = layer.ID
+	features, meta, err := s.layerRepo.GetFeaturesPaginate(ctx, filter, query)
+	if err != nil {
+		logger.Error(ctx, "failed to get layer features", err)
+		return nil, nil, err
+	}
+
+	for i := range features {
+		f := &features[i]
+		if f.Geometry == "" {
+			continue
+		}
+
+		t, err := wkt.Unmarshal(f.Geometry)
+		if err != nil {
+			return nil, nil, err
+		}
+		if f.Shape, err = geojson.Marshal(t); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return features, meta, nil
+}
+
+// Intersecting returns a page of the features of the layer with the given name sharing a
+// point with t and matching filter.
+func (s *layerImpl) Intersecting(ctx context.Context, name string, t geom.T, filter model.LayerFeatureFilter, query pagination.Param) ([]model.LayerFeature, *pagination.Param, error) {
+	wktString, err := wkt.Marshal(t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter.Intersects = wktString
+	return s.Features(ctx, name, filter, query)
+}
+
+// CreateFromFeatureCollection imports fc into the layer with the given name, created when
+// there is none. A feature replaces the feature of the layer with the same GeoJSON id, when
+// it has one, and keeps its properties as they are.
+func (s *layerImpl) CreateFromFeatureCollection(ctx context.Context, name string, fc *geojson.FeatureCollection) (*model.Layer, error) {
+	var features []model.LayerFeature
+	positions := make(map[string]int)
+	for i, f := range fc.Features {
+		feature, err := parseLayerFeature(f)
+		if err != nil {
+			logger.Warn(ctx, "failed to parse layer feature", tag.Err(err))
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+
+		// A feature id given twice keeps the last feature, a single insert cannot write a
+		// row twice.
+		if feature.FeatureID != nil {
+			if pos, ok := positions[*feature.FeatureID]; ok {
+				features[pos] = feature
+				continue
+			}
+			positions[*feature.FeatureID] = len(features)
+		}
+		features = append(features, feature)
+	}
+
+	layer, err := s.layerRepo.UpsertLayer(ctx, name)
+	if err != nil {
+		logger.Error(ctx, "failed to upsert layer", err)
+		return nil, err
+	}
+
+	for start := 0; start < len(features); start += layerImportChunkSize {
+		end := start + layerImportChunkSize
+		if end > len(features) {
+			end = len(features)
+		}
+
+		chunk := features[start:end]
+		for i := range chunk {
+			chunk[i].LayerID = layer.ID
+		}
+		if err := s.layerRepo.UpsertFeatures(ctx, chunk); err != nil {
+			logger.Error(ctx, "failed to upsert layer features", err)
+			return nil, err
+		}
+	}
+
+	return s.Get(ctx, name)
+}
+
+// parseLayerFeature converts a GeoJSON feature into a layer feature. The name is the "name"
+// property, when it is a string, every property is kept.
+func parseLayerFeature(f *geojson.Feature) (model.LayerFeature, error) {
+	mp := geo.ToMultiPolygon(f.Geometry)
+	if mp == nil || mp.NumPolygons() == 0 {
+		return model.LayerFeature{}, ErrInvalidLayerFeature
+	}
+
+	wktString, err := wkt.Marshal(mp)
+	if err != nil {
+		return model.LayerFeature{}, err
+	}
+
+	properties := f.Properties
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	propertiesJSON, err := json.Marshal(properties)
+	if err != nil {
+		return model.LayerFeature{}, err
+	}
+
+	feature := model.LayerFeature{
+		Properties: string(propertiesJSON),
+		Geometry:   wktString,
+	}
+	if name, ok := f.Properties["name"].(string); ok {
+		feature.Name = name
+	}
+	if f.ID != "" {
+		id := f.ID
+		feature.FeatureID = &id
+	}
+
+	attrs := geo.Measure(mp)
+	minLng, minLat := attrs.Bounds.Min(0), attrs.Bounds.Min(1)
+	maxLng, maxLat := attrs.Bounds.Max(0), attrs.Bounds.Max(1)
+	feature.AreaKm2 = &attrs.AreaKm2
+	feature.BboxMinLng, feature.BboxMinLat = &minLng, &minLat
+	feature.BboxMaxLng, feature.BboxMaxLat = &maxLng, &maxLat
+
+	return feature, nil
+}
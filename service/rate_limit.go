@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/ratelimit"
+	"github.com/si-bas/go-rest-geospatial/shared"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+)
+
+// DefaultRouteCosts are the tokens of the routes doing more than a lookup, keyed like
+// RateLimit.Costs. Every other route takes 1.
+var DefaultRouteCosts = map[string]int{
+	"GET /graphql":                                  2,
+	"POST /graphql":                                 2,
+	"POST /v1/query/intersects":                     5,
+	"POST /v1/geometry/union":                       10,
+	"POST /v1/layers/:name/query/intersects":        5,
+	"POST /v1/exports":                              20,
+	"POST /v1/import":                               20,
+	"POST /v1/geofences/positions":                  10,
+	"/geospatial.v1.GeospatialService/StreamList":   5,
+	"/geospatial.v1.GeospatialService/BatchReverse": 10,
+	"/geospatial.v1.GeospatialService/Children":     5,
+}
+
+type RateLimitService interface {
+	Take(context.Context, string, string) ratelimit.Result
+	AllowAuth(string) ratelimit.Result
+	FailAuth(string)
+}
+
+type rateLimitImpl struct {
+	limiter  *ratelimit.Limiter
+	defaults ratelimit.Limit
+	costs    map[string]int
+}
+
+// NewRateLimitService returns the service limiting clients to defaults, or to the limit of
+// their API key, spending the DefaultRouteCosts overridden by costs.
+func NewRateLimitService(defaults ratelimit.Limit, costs map[string]int) RateLimitService {
+	merged := make(map[string]int, len(DefaultRouteCosts)+len(costs))
+	for route, cost := range DefaultRouteCosts {
+		merged[strings.ToLower(route)] = cost
+	}
+	for route, cost := range costs {
+		merged[strings.ToLower(route)] = cost
+	}
+
+	return &rateLimitImpl{
+		limiter:  ratelimit.New(),
+		defaults: defaults,
+		costs:    merged,
+	}
+}
+
+// Take spends the cost of route for who made the request of ctx: the API key or the JWT
+// subject it was authenticated as, clientIP otherwise.
+func (s *rateLimitImpl) Take(ctx context.Context, route, clientIP string) ratelimit.Result {
+	key := "ip:" + clientIP
+	if userID := shared.GetContextValueAsString(ctx, constant.UserID); userID != "" {
+		key = userID
+	}
+
+	limit := s.defaults
+	if override, ok := ctx.Value(constant.UserRateLimit).(model.RateLimit); ok {
+		if override.Rate != nil {
+			limit.Rate = *override.Rate
+		}
+		if override.Burst != nil {
+			limit.Burst = *override.Burst
+		}
+		if override.DailyQuota != nil {
+			limit.DailyQuota = *override.DailyQuota
+		}
+	}
+
+	cost, ok := s.costs[strings.ToLower(route)]
+	if !ok {
+		cost = 1
+	}
+
+	return s.limiter.Take(key, limit, cost)
+}
+
+// authFailureKey is the bucket of the failed authentications of clientIP, apart from the
+// bucket of its anonymous requests.
+func authFailureKey(clientIP string) string {
+	return "auth:" + clientIP
+}
+
+// AllowAuth reports whether clientIP may still try credentials. Every failed try spends a
+// token of the default limit, so a client sending bogus credentials is stopped before they
+// are looked up.
+func (s *rateLimitImpl) AllowAuth(clientIP string) ratelimit.Result {
+	return s.limiter.Peek(authFailureKey(clientIP), s.defaults, 1)
+}
+
+// FailAuth spends a token of clientIP for credentials that did not authenticate.
+func (s *rateLimitImpl) FailAuth(clientIP string) {
+	s.limiter.Take(authFailureKey(clientIP), s.defaults, 1)
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger"
+	"github.com/si-bas/go-rest-geospatial/pkg/logger/tag"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+// ErrWebhookNotFound is returned for a webhook id that does not exist.
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+const (
+	// dispatchBatch caps the events fanned out and the deliveries sent by one dispatch.
+	dispatchBatch = 100
+	// webhookBackoff is the delay before the first retry, it doubles with every failed attempt
+	// up to maxWebhookBackoff.
+	webhookBackoff    = 30 * time.Second
+	maxWebhookBackoff = time.Hour
+	// maxDeliveryError caps the error kept of a failed attempt, the length of its column.
+	maxDeliveryError = 1024
+)
+
+type WebhookService interface {
+	Create(context.Context, model.WebhookRequest) (*model.Webhook, error)
+	List(context.Context) ([]model.Webhook, error)
+	Delete(context.Context, uint) error
+	Deliveries(context.Context, uint, pagination.Param) ([]model.WebhookDelivery, *pagination.Param, error)
+	Dispatch(context.Context, time.Time) (int, error)
+	Run(context.Context, time.Duration)
+}
+
+type webhookImpl struct {
+	eventRepo   repository.EventRepository
+	client      *http.Client
+	maxAttempts uint
+}
+
+// NewWebhookService returns the service delivering the events of the outbox with client, a
+// delivery fails once maxAttempts attempts failed.
+func NewWebhookService(eventRepo repository.EventRepository, client *http.Client, maxAttempts uint) WebhookService {
+	return &webhookImpl{
+		eventRepo:   eventRepo,
+		client:      client,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Create registers a webhook with a new secret, which is only returned here.
+func (s *webhookImpl) Create(ctx context.Context, req model.WebhookRequest) (*model.Webhook, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	webhook := &model.Webhook{
+		URL:    req.URL,
+		Secret: hex.EncodeToString(secret),
+		Events: strings.Join(req.Events, ","),
+	}
+	if err := s.eventRepo.CreateWebhook(ctx, webhook); err != nil {
+		logger.Error(ctx, "failed to create webhook", err)
+		return nil, err
+	}
+	setEventTypes(webhook)
+
+	return webhook, nil
+}
+
+// List returns every webhook, without their secrets.
+func (s *webhookImpl) List(ctx context.Context) ([]model.Webhook, error) {
+	webhooks, err := s.eventRepo.GetWebhooks(ctx, nil)
+	if err != nil {
+		logger.Error(ctx, "failed to get webhooks", err)
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+		setEventTypes(&webhooks[i])
+	}
+	return webhooks, nil
+}
+
+func setEventTypes(webhook *model.Webhook) {
+	webhook.EventTypes = []string{}
+	if webhook.Events != "" {
+		webhook.EventTypes = strings.Split(webhook.Events, ",")
+	}
+}
+
+// Delete deletes the webhook with the given id, its pending deliveries are dropped.
+func (s *webhookImpl) Delete(ctx context.Context, id uint) error {
+	if _, err := s.webhook(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.eventRepo.DeleteWebhook(ctx, id); err != nil {
+		logger.Error(ctx, "failed to delete webhook", err)
+		return err
+	}
+
+	return nil
+}
+
+// Deliveries returns the delivery log of the webhook with the given id, the latest first.
+func (s *webhookImpl) Deliveries(ctx context.Context, id uint, param pagination.Param) ([]model.WebhookDelivery, *pagination.Param, error) {
+	if _, err := s.webhook(ctx, id); err != nil {
+		return nil, nil, err
+	}
+
+	deliveries, meta, err := s.eventRepo.GetDeliveriesPaginate(ctx, id, param)
+	if err != nil {
+		logger.Error(ctx, "failed to get webhook deliveries", err)
+		return nil, nil, err
+	}
+
+	return deliveries, meta, nil
+}
+
+func (s *webhookImpl) webhook(ctx context.Context, id uint) (*model.Webhook, error) {
+	webhooks, err := s.eventRepo.GetWebhooks(ctx, []uint{id})
+	if err != nil {
+		logger.Error(ctx, "failed to get webhook by id", err)
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, ErrWebhookNotFound
+	}
+
+	return &webhooks[0], nil
+}
+
+// Run dispatches the outbox every interval until ctx is done.
+func (s *webhookImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.Dispatch(ctx, now); err != nil {
+				logger.Error(ctx, "failed to dispatch webhooks", err)
+			}
+		}
+	}
+}
+
+// Dispatch creates the deliveries of new events and sends the deliveries due at now. A
+// delivery is retried with a growing delay until a receiver answers with a 2xx status or
+// maxAttempts attempts failed. It returns the number of deliveries attempted.
+func (s *webhookImpl) Dispatch(ctx context.Context, now time.Time) (int, error) {
+	for {
+		n, err := s.eventRepo.FanOut(ctx, now, dispatchBatch)
+		if err != nil {
+			return 0, err
+		}
+		if n < dispatchBatch {
+			break
+		}
+	}
+
+	// A claim outlives the timeout of every delivery it holds, sent one after the other.
+	until := now.Add(time.Duration(dispatchBatch+1) * s.client.Timeout)
+	if s.client.Timeout == 0 {
+		until = now.Add(maxWebhookBackoff)
+	}
+	deliveries, err := s.eventRepo.ClaimDeliveries(ctx, now, until, dispatchBatch)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	var eventIds, webhookIds []uint
+	for _, d := range deliveries {
+		eventIds = append(eventIds, d.EventID)
+		webhookIds = append(webhookIds, d.WebhookID)
+	}
+	events, err := s.eventRepo.GetEvents(ctx, eventIds)
+	if err != nil {
+		return 0, err
+	}
+	webhooks, err := s.eventRepo.GetWebhooks(ctx, webhookIds)
+	if err != nil {
+		return 0, err
+	}
+
+	eventByID := make(map[uint]model.OutboxEvent, len(events))
+	for _, e := range events {
+		eventByID[e.ID] = e
+	}
+	webhookByID := make(map[uint]model.Webhook, len(webhooks))
+	for _, w := range webhooks {
+		webhookByID[w.ID] = w
+	}
+
+	var attempted int
+	for _, d := range deliveries {
+		webhook, ok := webhookByID[d.WebhookID]
+		if !ok {
+			// Deleted since the claim, with its deliveries.
+			continue
+		}
+
+		statusCode, err := s.send(ctx, webhook, d, eventByID[d.EventID])
+		s.record(&d, statusCode, err, time.Now())
+		if err := s.eventRepo.SaveDelivery(ctx, d); err != nil {
+			return attempted, err
+		}
+		attempted++
+	}
+
+	return attempted, nil
+}
+
+// send posts event to webhook and returns the status code of the answer.
+func (s *webhookImpl) send(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery, event model.OutboxEvent) (int, error) {
+	body, err := json.Marshal(model.WebhookPayload{
+		ID:        event.ID,
+		Type:      event.Type,
+		CreatedAt: event.CreatedAt,
+		Data:      json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constant.WebhookEventHeader, event.Type)
+	req.Header.Set(constant.WebhookDeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(constant.WebhookTimestampHeader, timestamp)
+	req.Header.Set(constant.WebhookSignatureHeader, "sha256="+SignWebhook(webhook.Secret, timestamp, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver answered %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// record sets the outcome of an attempt of d made at now.
+func (s *webhookImpl) record(d *model.WebhookDelivery, statusCode int, err error, now time.Time) {
+	d.Attempts++
+	d.StatusCode = nil
+	if statusCode != 0 {
+		d.StatusCode = &statusCode
+	}
+
+	if err == nil {
+		d.Status = constant.WebhookDeliveryDelivered
+		d.Error = ""
+		d.DeliveredAt = &now
+		return
+	}
+
+	d.Error = err.Error()
+	if len(d.Error) > maxDeliveryError {
+		d.Error = d.Error[:maxDeliveryError]
+	}
+	if d.Attempts >= s.maxAttempts {
+		d.Status = constant.WebhookDeliveryFailed
+		return
+	}
+
+	backoff := maxWebhookBackoff
+	if d.Attempts < 8 {
+		backoff = webhookBackoff << (d.Attempts - 1)
+		if backoff > maxWebhookBackoff {
+			backoff = maxWebhookBackoff
+		}
+	}
+	d.NextAttemptAt = now.Add(backoff)
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of timestamp, a dot and body keyed with secret, the
+// signature of a delivery a receiver compares to the one it computes.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newEvent returns an outbox event of the given type with data as its payload.
+func newEvent(eventType string, data interface{}) (model.OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+	return model.OutboxEvent{Type: eventType, Payload: string(payload)}, nil
+}
+
+// addEvent writes an event that is not part of a change to the outbox. A failure is logged,
+// it must not hide the outcome the event reports.
+func addEvent(ctx context.Context, eventRepo repository.EventRepository, eventType string, data interface{}) {
+	event, err := newEvent(eventType, data)
+	if err == nil {
+		err = eventRepo.AddEvents(ctx, []model.OutboxEvent{event})
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to add event to outbox", err, tag.Tag{Key: "type", Value: eventType})
+	}
+}
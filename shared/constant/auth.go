@@ -0,0 +1,27 @@
+package constant
+
+// Roles of the clients of the API, each is granted what the roles before it are.
+const (
+	RoleReader   = "reader"
+	RoleImporter = "importer"
+	RoleAdmin    = "admin"
+)
+
+// Roles are the roles from the least to the most privileged.
+var Roles = []string{RoleReader, RoleImporter, RoleAdmin}
+
+// APIKeyPrefix starts every API key, it tells them apart from JWTs.
+const APIKeyPrefix = "gsk_"
+
+// HasRole reports whether role is granted what required is.
+func HasRole(role, required string) bool {
+	rank := func(r string) int {
+		for i, known := range Roles {
+			if known == r {
+				return i
+			}
+		}
+		return -1
+	}
+	return rank(required) >= 0 && rank(role) >= rank(required)
+}
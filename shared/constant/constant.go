@@ -3,5 +3,7 @@ package constant
 const (
 	UserID        = "UserID"
 	User          = "User"
+	UserRole      = "UserRole"
+	UserRateLimit = "UserRateLimit"
 	EnvProduction = "production"
 )
@@ -0,0 +1,8 @@
+package constant
+
+const (
+	DbDriverMySQL    = "mysql"
+	DbDriverPostgres = "postgres"
+	DbDriverSqlite   = "sqlite"
+	DbDriverMemory   = "memory"
+)
@@ -0,0 +1,11 @@
+package constant
+
+const (
+	ExportFormatGeoPackage = "gpkg"
+	ExportFormatFlatGeobuf = "fgb"
+
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
@@ -0,0 +1,8 @@
+package constant
+
+// Geofence event types.
+const (
+	GeofenceEventEnter = "enter"
+	GeofenceEventExit  = "exit"
+	GeofenceEventDwell = "dwell"
+)
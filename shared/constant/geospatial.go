@@ -1,8 +1,40 @@
 package constant
 
+// Normalized region types, what GADM's ENGTYPE_n values are mapped to. The local type, like
+// "Kabupaten" or "Kota", is kept as is next to it.
 const (
-	EngtypeProvince    = "PROVINCE"
-	EngtypeRegency     = "REGENCY"
-	EngtypeSubDistrict = "SUB-DISTRICT"
-	EngtypeSubVillage  = "VILLAGE"
+	EngtypeCountry      = "COUNTRY"
+	EngtypeState        = "STATE"
+	EngtypeProvince     = "PROVINCE"
+	EngtypeRegion       = "REGION"
+	EngtypeDepartment   = "DEPARTMENT"
+	EngtypeCounty       = "COUNTY"
+	EngtypeRegency      = "REGENCY"
+	EngtypeCity         = "CITY"
+	EngtypeMunicipality = "MUNICIPALITY"
+	EngtypeDistrict     = "DISTRICT"
+	EngtypeSubDistrict  = "SUB-DISTRICT"
+	EngtypeVillage      = "VILLAGE"
+	EngtypeWard         = "WARD"
+	EngtypeWaterBody    = "WATER-BODY"
+	EngtypeOther        = "OTHER"
+	// EngtypeCustom is the type of the areas clients save, like the union of regions.
+	EngtypeCustom = "CUSTOM"
+)
+
+// Engtypes is the catalogue of normalized region types, broadly from the largest to the smallest.
+var Engtypes = []string{
+	EngtypeCountry, EngtypeState, EngtypeProvince, EngtypeRegion, EngtypeDepartment, EngtypeCounty,
+	EngtypeRegency, EngtypeCity, EngtypeMunicipality, EngtypeDistrict, EngtypeSubDistrict,
+	EngtypeVillage, EngtypeWard, EngtypeWaterBody, EngtypeOther, EngtypeCustom,
+}
+
+const (
+	NameKindOfficial   = "official"
+	NameKindVariant    = "variant"
+	NameKindHistorical = "historical"
+
+	// NameLanguageLocal marks the name in the local script GADM ships as NL_NAME_n, whose
+	// language GADM does not record.
+	NameLanguageLocal = "local"
 )
@@ -7,3 +7,12 @@ const (
 	XRequestIDHeader              = "X-REQUEST-ID"
 	XRequestIDHeaderCtxKey ctxKey = "X-REQUEST-ID"
 )
+
+// Headers of the rate limit of a client.
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+)
@@ -16,6 +16,7 @@ const (
 	StatusInvalidAuthentication = http.StatusProxyAuthRequired
 	StatusNotFound              = http.StatusNotFound
 	StatusConflict              = http.StatusConflict
+	StatusTooManyRequests       = http.StatusTooManyRequests
 )
 
 var statusMap = map[int][]string{
@@ -30,6 +31,7 @@ var statusMap = map[int][]string{
 	StatusInvalidAuthentication: {"STATUS_INVALID_AUTHENTICATION", "The resource owner or authorization server denied the request"},
 	StatusNotFound:              {"STATUS_NOT_FOUND", "Not Found"},
 	StatusConflict:              {"STATUS_CONFLICT", "Data conflict"},
+	StatusTooManyRequests:       {"STATUS_TOO_MANY_REQUESTS", "Too many requests"},
 }
 
 func StatusCode(code int) string {
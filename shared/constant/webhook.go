@@ -0,0 +1,28 @@
+package constant
+
+// Types of the events written to the outbox and delivered to webhooks.
+const (
+	EventRegionCreated   = "region.created"
+	EventRegionUpdated   = "region.updated"
+	EventRegionRetired   = "region.retired"
+	EventImportCompleted = "import.completed"
+	EventImportFailed    = "import.failed"
+)
+
+// EventTypes are the event types a webhook can subscribe to.
+var EventTypes = []string{EventRegionCreated, EventRegionUpdated, EventRegionRetired, EventImportCompleted, EventImportFailed}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Headers of a webhook delivery. The signature is the hex HMAC-SHA256 of the timestamp, a
+// dot and the body, keyed with the secret of the webhook.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
@@ -0,0 +1,120 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidCursor = errors.New("cursor is invalid or does not match the sort")
+	ErrInvalidSort   = errors.New("sort is not supported")
+)
+
+// cursor is the position after the last row of a page: the values of the sort columns of
+// that row, the id last. The sort is kept to reject a cursor used with a different sort.
+type cursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// EncodeCursor returns the opaque cursor of a row sorted by columns with values.
+func EncodeCursor(columns []KeysetColumn, values []interface{}) string {
+	data, _ := json.Marshal(cursor{Sort: KeysetOrder(columns), Values: values})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor returns the values of an encoded cursor as decoded from JSON, numbers as
+// float64 and times as strings. An empty cursor is the first page and has no values.
+func DecodeCursor(encoded string, columns []KeysetColumn) ([]interface{}, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != KeysetOrder(columns) || len(c.Values) != len(columns) {
+		return nil, ErrInvalidCursor
+	}
+
+	return c.Values, nil
+}
+
+// KeysetColumn is a column rows are sorted by in cursor mode. NULL sorts before every value,
+// like MySQL and SQLite do and unlike PostgreSQL, so the order is spelled out for nullable columns.
+type KeysetColumn struct {
+	Column   string
+	Desc     bool
+	Nullable bool
+}
+
+// KeysetOrder returns the ORDER BY clause of columns.
+func KeysetOrder(columns []KeysetColumn) string {
+	var orders []string
+	for _, c := range columns {
+		switch {
+		case c.Nullable && c.Desc:
+			orders = append(orders, fmt.Sprintf("%s IS NULL ASC, %s DESC", c.Column, c.Column))
+		case c.Nullable:
+			orders = append(orders, fmt.Sprintf("%s IS NULL DESC, %s ASC", c.Column, c.Column))
+		case c.Desc:
+			orders = append(orders, c.Column+" "+OrderDesc)
+		default:
+			orders = append(orders, c.Column+" "+OrderAsc)
+		}
+	}
+	return strings.Join(orders, ", ")
+}
+
+// KeysetWhere returns the condition selecting the rows sorted after the row with values.
+// For columns a, b it is (a after) OR (a equal AND b after).
+func KeysetWhere(columns []KeysetColumn, values []interface{}) (string, []interface{}) {
+	var ors []string
+	var args []interface{}
+
+	var equals []string
+	var equalArgs []interface{}
+	for i, c := range columns {
+		after, afterArgs := keysetAfter(c, values[i])
+		if after != "" {
+			ors = append(ors, "("+strings.Join(append(append([]string{}, equals...), after), " AND ")+")")
+			args = append(append(args, equalArgs...), afterArgs...)
+		}
+
+		if values[i] == nil {
+			equals = append(equals, c.Column+" IS NULL")
+		} else {
+			equals = append(equals, c.Column+" = ?")
+			equalArgs = append(equalArgs, values[i])
+		}
+	}
+
+	if len(ors) == 0 {
+		return "1 = 0", nil
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// keysetAfter returns the condition selecting the values of c sorted after value, if any.
+func keysetAfter(c KeysetColumn, value interface{}) (string, []interface{}) {
+	switch {
+	case value == nil && c.Desc:
+		return "", nil
+	case value == nil:
+		return c.Column + " IS NOT NULL", nil
+	case c.Desc && c.Nullable:
+		return fmt.Sprintf("(%s < ? OR %s IS NULL)", c.Column, c.Column), []interface{}{value}
+	case c.Desc:
+		return c.Column + " < ?", []interface{}{value}
+	}
+	return c.Column + " > ?", []interface{}{value}
+}
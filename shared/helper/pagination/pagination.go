@@ -1,20 +1,50 @@
 package pagination
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/si-bas/go-rest-geospatial/config"
 	"gorm.io/gorm"
 )
 
+// Param pages through rows either by page number or, when UseCursor is set, after the row
+// Cursor points to. Cursor mode returns NextCursor while there are more rows. SkipTotal
+// saves the count of all rows, the totals are then left out.
 type Param struct {
 	Limit      uint        `json:"limit"`
 	Page       uint        `json:"page"`
 	Sort       []ParamSort `json:"-"`
 	TotalRows  int64       `json:"total_rows"`
 	TotalPages uint        `json:"total_pages"`
+	UseCursor  bool        `json:"-"`
+	Cursor     string      `json:"-"`
+	NextCursor string      `json:"-"`
+	SkipTotal  bool        `json:"-"`
+}
+
+func (p Param) MarshalJSON() ([]byte, error) {
+	meta := struct {
+		Limit      uint   `json:"limit"`
+		Page       uint   `json:"page,omitempty"`
+		TotalRows  *int64 `json:"total_rows,omitempty"`
+		TotalPages *uint  `json:"total_pages,omitempty"`
+		NextCursor string `json:"next_cursor,omitempty"`
+	}{
+		Limit:      p.Limit,
+		NextCursor: p.NextCursor,
+	}
+	if !p.UseCursor {
+		meta.Page = p.Page
+	}
+	if !p.SkipTotal {
+		meta.TotalRows = &p.TotalRows
+		meta.TotalPages = &p.TotalPages
+	}
+	return json.Marshal(meta)
 }
 
 type ParamSort struct {
@@ -23,9 +53,8 @@ type ParamSort struct {
 }
 
 const (
-	OrderDesc    = "DESC"
-	OrderAsc     = "ASC"
-	OrderDefault = "id DESC"
+	OrderDesc = "DESC"
+	OrderAsc  = "ASC"
 )
 
 func (p *Param) GetOffset() uint {
@@ -46,39 +75,74 @@ func (p *Param) GetPage() uint {
 	return p.Page
 }
 
-func (p *Param) GetSort() string {
-	var sortBy string
-	if len(p.Sort) == 0 {
-		return OrderDefault
+// ParseSort parses an ordered, comma separated list of fields, each descending when prefixed
+// with "-", like "level,-name". fields maps every sortable field, and its aliases, to the
+// field it sorts by. Unknown and repeated fields are errors.
+func ParseSort(value string, fields map[string]string) ([]ParamSort, error) {
+	if value == "" {
+		return nil, nil
 	}
 
-	var sortBys []string
-	for _, s := range p.Sort {
-		if strings.ToUpper(s.Order) != OrderAsc && strings.ToUpper(s.Order) != OrderDesc {
-			continue
+	var sorts []ParamSort
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		order := OrderAsc
+		if strings.HasPrefix(item, "-") {
+			order = OrderDesc
+			item = item[1:]
+		} else {
+			item = strings.TrimPrefix(item, "+")
 		}
 
-		sortBys = append(sortBys, fmt.Sprintf("%s %s", s.Column, s.Order))
-	}
+		field, ok := fields[item]
+		if !ok {
+			return nil, fmt.Errorf("cannot sort by %q, sortable fields are %s", item, strings.Join(sortableFields(fields), ", "))
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("cannot sort by %q more than once", item)
+		}
+		seen[field] = true
 
-	if len(sortBys) == 0 {
-		return OrderDefault
+		sorts = append(sorts, ParamSort{Column: field, Order: order})
 	}
 
-	sortBy = strings.Join(sortBys, ", ")
+	return sorts, nil
+}
 
-	return sortBy
+func sortableFields(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
+// Paginate counts the rows of db and limits the query to the page of param. The caller
+// orders the rows, only it knows which columns may be sorted by.
 func Paginate(value interface{}, param *Param, db *gorm.DB) func(db *gorm.DB) *gorm.DB {
+	Count(value, param, db)
+
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset(int(param.GetOffset())).Limit(int(param.GetLimit()))
+	}
+}
+
+// Count sets the totals of param to the rows of db, unless param skips them.
+func Count(value interface{}, param *Param, db *gorm.DB) {
+	if param.SkipTotal {
+		return
+	}
+
 	var totalRows int64
 	db.Model(value).Count(&totalRows)
 
-	param.TotalRows = totalRows
-	totalPages := uint(math.Ceil(float64(totalRows) / float64(param.GetLimit())))
-	param.TotalPages = totalPages
+	param.SetTotal(totalRows)
+}
 
-	return func(db *gorm.DB) *gorm.DB {
-		return db.Offset(int(param.GetOffset())).Limit(int(param.GetLimit())).Order(param.GetSort())
-	}
+// SetTotal sets the total rows and the total pages they take.
+func (p *Param) SetTotal(totalRows int64) {
+	p.TotalRows = totalRows
+	p.TotalPages = uint(math.Ceil(float64(totalRows) / float64(p.GetLimit())))
 }
@@ -25,6 +25,7 @@ var (
 	ErrUnauthorized        = errors.New("unauthorized")
 	ErrConflict            = errors.New("conflict")
 	ErrDependencyFailed    = errors.New("dependency failed")
+	ErrTooManyRequests     = errors.New("too many requests")
 )
 
 const (
@@ -36,6 +37,7 @@ const (
 	StatusCodeNotFound                  = "404000"
 	StatusCodeConflict                  = "409000"
 	StatusCodeGenericPreconditionFailed = "412000"
+	StatusCodeTooManyRequests           = "429000"
 	StatusCodeOTPLimitReached           = "412550"
 	StatusCodeNoLinkerExist             = "412553"
 	StatusCodeInternalError             = "500000"
@@ -72,6 +74,8 @@ func GetErrorCode(err error) string {
 		return StatusCodeGenericSuccess
 	case ErrDependencyFailed:
 		return StatusCodeBadGateway
+	case ErrTooManyRequests:
+		return StatusCodeTooManyRequests
 	default:
 		return StatusCodeInternalError
 	}
@@ -269,3 +273,11 @@ func (r *JSONResponse) APIStatusConflict() *JSONResponse {
 	r.Message = constant.StatusText(constant.StatusConflict)
 	return r
 }
+
+// APIStatusTooManyRequests
+func (r *JSONResponse) APIStatusTooManyRequests() *JSONResponse {
+	r.StatusCode = constant.StatusTooManyRequests
+	r.Code = constant.StatusCode(constant.StatusTooManyRequests)
+	r.Message = constant.StatusText(constant.StatusTooManyRequests)
+	return r
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/pkg/export"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+)
+
+var geospatials = []model.Geospatial{
+	{ID: 1, GadmID: "IDN", Name: "Indonesia", Type: "Country", Level: 1, Geometry: "MULTIPOLYGON(((95 -11,141 -11,141 6,95 6,95 -11)))"},
+	{ID: 2, GadmID: "IDN.7_1", ParentGadmID: "IDN", Name: "Jakarta Raya", Type: "Propinsi", Level: 2, Geometry: "MULTIPOLYGON(((106.6 -6.4,107 -6.4,107 -6,106.6 -6,106.6 -6.4)))"},
+	{ID: 3, GadmID: "IDN.3_1", ParentGadmID: "IDN", Name: "Banten", Type: "Propinsi", Level: 2, Geometry: "POLYGON((105 -7,106.6 -7,106.6 -5.8,105 -5.8,105 -7))"},
+}
+
+func TestWriteGeoPackage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geospatial.gpkg")
+
+	err := export.Write(context.TODO(), constant.ExportFormatGeoPackage, path, geospatials)
+	assert.Equal(t, nil, err)
+
+	db, err := sql.Open("sqlite", path)
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	var applicationID int64
+	db.QueryRow("PRAGMA application_id").Scan(&applicationID)
+	assert.Equal(t, int64(0x47504B47), applicationID)
+
+	var tables int
+	db.QueryRow("SELECT COUNT(*) FROM gpkg_contents").Scan(&tables)
+	assert.Equal(t, 2, tables)
+
+	var rows int
+	db.QueryRow("SELECT COUNT(*) FROM level_2").Scan(&rows)
+	assert.Equal(t, 2, rows)
+
+	var name string
+	db.QueryRow("SELECT name FROM level_2 WHERE fid IN (SELECT id FROM rtree_level_2_geom WHERE minx <= 106.8 AND maxx >= 106.8 AND miny <= -6.2 AND maxy >= -6.2)").Scan(&name)
+	assert.Equal(t, "Jakarta Raya", name)
+
+	var blob []byte
+	db.QueryRow("SELECT geom FROM level_1").Scan(&blob)
+	assert.Equal(t, []byte("GP"), blob[:2])
+	assert.Equal(t, int32(4326), int32(binary.LittleEndian.Uint32(blob[4:8])))
+}
+
+func TestWriteFlatGeobuf(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := export.WriteFlatGeobuf(&buf, geospatials)
+	assert.Equal(t, nil, err)
+
+	data := buf.Bytes()
+	assert.Equal(t, []byte{'f', 'g', 'b', 3, 'f', 'g', 'b', 0}, data[:8])
+
+	headerSize := int(binary.LittleEndian.Uint32(data[8:12]))
+	indexSize := 4 * 40 // three leaves and the root
+	features := data[12+headerSize+indexSize:]
+
+	count := 0
+	for len(features) > 0 {
+		size := int(binary.LittleEndian.Uint32(features[:4]))
+		features = features[4+size:]
+		count++
+	}
+	assert.Equal(t, len(geospatials), count)
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	err := export.Write(context.TODO(), "shp", filepath.Join(t.TempDir(), "geospatial.shp"), geospatials)
+	assert.Equal(t, export.ErrUnsupportedFormat, err)
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/twpayne/go-geom"
+)
+
+func TestMeasure(t *testing.T) {
+	// One degree square on the equator
+	square := geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+	})
+
+	attrs := geo.Measure(square)
+
+	wantArea := geo.EarthRadius * geo.EarthRadius * (math.Pi / 180) * math.Sin(math.Pi/180) / 1e6
+	if math.Abs(attrs.AreaKm2-wantArea) > 1 {
+		t.Errorf("unexpected area, got: %f, want: %f", attrs.AreaKm2, wantArea)
+	}
+
+	if math.Abs(attrs.PerimeterKm-445) > 1 {
+		t.Errorf("unexpected perimeter, got: %f, want: ~445", attrs.PerimeterKm)
+	}
+
+	if math.Abs(attrs.Centroid[0]-0.5) > 1e-9 || math.Abs(attrs.Centroid[1]-0.5) > 1e-9 {
+		t.Errorf("unexpected centroid, got: %v", attrs.Centroid)
+	}
+
+	if attrs.Bounds.Min(0) != 0 || attrs.Bounds.Max(1) != 1 {
+		t.Errorf("unexpected bounds, got: %v", attrs.Bounds)
+	}
+}
+
+func TestGeodesicAreaWithHole(t *testing.T) {
+	withHole := geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+		{
+			{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}},
+			{{0.5, 0.5}, {1.5, 0.5}, {1.5, 1.5}, {0.5, 1.5}, {0.5, 0.5}},
+		},
+	})
+	outer := geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+		{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+	})
+
+	if geo.GeodesicArea(withHole) >= geo.GeodesicArea(outer) {
+		t.Errorf("hole was not subtracted from the area")
+	}
+}
+
+func TestPointOnSurface(t *testing.T) {
+	// U shape whose centroid lies in the gap between both arms
+	u := geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+		{{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 0}}},
+	})
+
+	centroid := geo.Measure(u).Centroid
+	if geo.ContainsPoint(u, centroid[0], centroid[1]) {
+		t.Fatalf("expected centroid %v to be outside the polygon", centroid)
+	}
+
+	label := geo.PointOnSurface(u)
+	if !geo.ContainsPoint(u, label[0], label[1]) {
+		t.Errorf("label point %v is outside the polygon", label)
+	}
+}
+
+func TestContainsPoint(t *testing.T) {
+	withHole := geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+		{
+			{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}},
+			{{0.5, 0.5}, {1.5, 0.5}, {1.5, 1.5}, {0.5, 1.5}, {0.5, 0.5}},
+		},
+	})
+
+	testCases := []struct {
+		name     string
+		lng, lat float64
+		want     bool
+	}{
+		{name: "inside", lng: 0.25, lat: 0.25, want: true},
+		{name: "inside hole", lng: 1, lat: 1, want: false},
+		{name: "outside", lng: 3, lat: 3, want: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := geo.ContainsPoint(withHole, tc.lng, tc.lat); got != tc.want {
+				t.Errorf("ContainsPoint(%f, %f) = %v, want %v", tc.lng, tc.lat, got, tc.want)
+			}
+		})
+	}
+}
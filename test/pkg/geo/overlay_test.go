@@ -0,0 +1,153 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/si-bas/go-rest-geospatial/pkg/geo"
+	"github.com/twpayne/go-geom"
+)
+
+func square(minX, minY, maxX, maxY float64) *geom.MultiPolygon {
+	return geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+		{{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}, {minX, minY}}},
+	})
+}
+
+func TestIntersectionArea(t *testing.T) {
+	a := square(0, 0, 2, 2)
+
+	testCases := []struct {
+		name  string
+		other *geom.MultiPolygon
+		want  float64
+	}{
+		{name: "itself", other: a, want: geo.GeodesicArea(a)},
+		{name: "quarter", other: square(1, 1, 3, 3), want: geo.GeodesicArea(square(1, 1, 2, 2))},
+		{name: "inside", other: square(0.5, 0.5, 1, 1), want: geo.GeodesicArea(square(0.5, 0.5, 1, 1))},
+		{name: "touching", other: square(2, 0, 3, 2), want: 0},
+		{name: "apart", other: square(5, 5, 6, 6), want: 0},
+		{
+			name: "clockwise",
+			other: geom.NewMultiPolygon(geom.XY).MustSetCoords([][][]geom.Coord{
+				{{{1, 0}, {1, 2}, {3, 2}, {3, 0}, {1, 0}}},
+			}),
+			want: geo.GeodesicArea(square(1, 0, 2, 2)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := geo.IntersectionArea(a, tc.other)
+			if math.Abs(got-tc.want) > tc.want*1e-6+1 {
+				t.Errorf("unexpected area, got: %f, want: %f", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIntersectionLength(t *testing.T) {
+	a := square(0, 0, 2, 2)
+	line := geom.NewLineString(geom.XY).MustSetCoords([]geom.Coord{{1, 1}, {3, 1}})
+
+	got := geo.IntersectionLength(a, line) / geo.LineLength(line)
+	if math.Abs(got-0.5) > 1e-3 {
+		t.Errorf("unexpected share of the line, got: %f, want: 0.5", got)
+	}
+
+	along := geom.NewLineString(geom.XY).MustSetCoords([]geom.Coord{{0, 0}, {2, 0}})
+	if got := geo.IntersectionLength(a, along); math.Abs(got-geo.LineLength(along)) > 1 {
+		t.Errorf("a line along the boundary must lie inside, got: %f", got)
+	}
+}
+
+func TestIntersects(t *testing.T) {
+	a := square(0, 0, 2, 2)
+
+	testCases := []struct {
+		name  string
+		other geom.T
+		want  bool
+	}{
+		{name: "overlapping polygon", other: square(1, 1, 3, 3), want: true},
+		{name: "polygon inside", other: square(0.5, 0.5, 1, 1), want: true},
+		{name: "polygon around", other: square(-1, -1, 3, 3), want: true},
+		{name: "polygon apart", other: square(3, 3, 4, 4), want: false},
+		{name: "crossing line", other: geom.NewLineString(geom.XY).MustSetCoords([]geom.Coord{{-1, 1}, {3, 1}}), want: true},
+		{name: "line inside", other: geom.NewLineString(geom.XY).MustSetCoords([]geom.Coord{{0.5, 0.5}, {1, 1}}), want: true},
+		{name: "line apart", other: geom.NewLineString(geom.XY).MustSetCoords([]geom.Coord{{3, -1}, {3, 3}}), want: false},
+		{name: "point", other: geom.NewPoint(geom.XY).MustSetCoords(geom.Coord{1, 1}), want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := geo.Intersects(a, tc.other); got != tc.want {
+				t.Errorf("unexpected result, got: %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSharedBorder(t *testing.T) {
+	a := square(0, 0, 1, 1)
+
+	testCases := []struct {
+		name        string
+		other       *geom.MultiPolygon
+		wantBorder  float64
+		wantTouches bool
+	}{
+		{name: "sharing an edge", other: square(1, 0, 2, 1), wantBorder: geo.Haversine(1, 0, 1, 1), wantTouches: true},
+		{name: "sharing a corner", other: square(1, 1, 2, 2), wantTouches: true},
+		{name: "overlapping", other: square(0.5, 0.5, 2, 2), wantTouches: true},
+		{name: "inside", other: square(0.25, 0.25, 0.75, 0.75), wantTouches: true},
+		{name: "apart", other: square(2, 0, 3, 1), wantTouches: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			border, touches := geo.SharedBorder(a, tc.other)
+			if math.Abs(border-tc.wantBorder) > 1e-6 || touches != tc.wantTouches {
+				t.Errorf("unexpected border, got: %f %v, want: %f %v", border, touches, tc.wantBorder, tc.wantTouches)
+			}
+		})
+	}
+}
+
+func TestDissolve(t *testing.T) {
+	t.Run("grid", func(t *testing.T) {
+		dissolved := geo.Dissolve([]*geom.MultiPolygon{square(0, 0, 1, 1), square(1, 0, 2, 1), square(0, 1, 1, 2), square(1, 1, 2, 2)})
+		if dissolved.NumPolygons() != 1 || dissolved.Polygon(0).NumLinearRings() != 1 {
+			t.Fatalf("unexpected rings, got: %v", dissolved.Coords())
+		}
+		if got, want := geo.GeodesicArea(dissolved), geo.GeodesicArea(square(0, 0, 2, 2)); math.Abs(got-want) > 1 {
+			t.Errorf("unexpected area, got: %f, want: %f", got, want)
+		}
+	})
+
+	t.Run("ring around a hole", func(t *testing.T) {
+		var squares []*geom.MultiPolygon
+		for x := 0.0; x < 3; x++ {
+			for y := 0.0; y < 3; y++ {
+				if x != 1 || y != 1 {
+					squares = append(squares, square(x, y, x+1, y+1))
+				}
+			}
+		}
+		dissolved := geo.Dissolve(squares)
+		if dissolved.NumPolygons() != 1 || dissolved.Polygon(0).NumLinearRings() != 2 {
+			t.Fatalf("unexpected rings, got: %v", dissolved.Coords())
+		}
+		want := geo.GeodesicArea(square(0, 0, 3, 3)) - geo.GeodesicArea(square(1, 1, 2, 2))
+		if got := geo.GeodesicArea(dissolved); math.Abs(got-want) > 1 {
+			t.Errorf("unexpected area, got: %f, want: %f", got, want)
+		}
+	})
+
+	t.Run("apart", func(t *testing.T) {
+		dissolved := geo.Dissolve([]*geom.MultiPolygon{square(0, 0, 1, 1), square(2, 0, 3, 1), square(0, 0, 1, 1)})
+		if dissolved.NumPolygons() != 2 {
+			t.Fatalf("unexpected polygons, got: %v", dissolved.Coords())
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	pkggorm "github.com/si-bas/go-rest-geospatial/pkg/gorm"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
+)
+
+type fakePool struct {
+	gorm.ConnPool
+	down atomic.Bool
+}
+
+func (p *fakePool) PingContext(ctx context.Context) error {
+	if p.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestReplicaPolicyResolve(t *testing.T) {
+	replica1, replica2, primary := &fakePool{}, &fakePool{}, &fakePool{}
+	replica1.down.Store(true)
+	pools := []gorm.ConnPool{replica1, replica2, primary}
+
+	policy := pkggorm.NewReplicaPolicy(10 * time.Millisecond)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, policy.Resolve(pools), gorm.ConnPool(replica2))
+	}
+
+	policy.MarkUnhealthy(replica2)
+	assert.Equal(t, policy.Resolve(pools), gorm.ConnPool(primary))
+
+	// Both replicas are rechecked in the background once the interval passed.
+	replica1.down.Store(false)
+	deadline := time.Now().Add(time.Second)
+	seen := make(map[gorm.ConnPool]bool)
+	for time.Now().Before(deadline) && !(seen[replica1] && seen[replica2]) {
+		seen[policy.Resolve(pools)] = true
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, seen[replica1], true)
+	assert.Equal(t, seen[replica2], true)
+}
+
+func TestOpenReplicaDown(t *testing.T) {
+	// Nothing listens on port 1, the replica is opened anyway and left out of rotation.
+	replica, err := pkggorm.OpenReplica(config.DB{Driver: constant.DbDriverMySQL, Host: "127.0.0.1", Port: 1, Name: "appdb"})
+	assert.Equal(t, err, nil)
+	defer replica.Close()
+
+	primary := &fakePool{}
+	policy := pkggorm.NewReplicaPolicy(time.Minute)
+	assert.Equal(t, policy.Resolve([]gorm.ConnPool{replica, primary}), gorm.ConnPool(primary))
+}
+
+func openSqlite(t *testing.T, name string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), name)), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", name, err)
+	}
+	if err := pkggorm.MigrateSqlite(db); err != nil {
+		t.Fatalf("failed to migrate %s: %v", name, err)
+	}
+	return db
+}
+
+func TestReplicaRouting(t *testing.T) {
+	primary := openSqlite(t, "primary.sqlite")
+	replica := openSqlite(t, "replica.sqlite")
+
+	err := repository.NewGeospatialRepository(replica).UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN", Name: "Replicated", Type: "Country", Level: 1, Geometry: "POLYGON((0 0,1 0,1 1,0 1,0 0))"},
+	})
+	assert.Equal(t, err, nil)
+
+	replicaPool, _ := replica.DB()
+	primaryPool, _ := primary.DB()
+	err = primary.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{
+			sqlite.Dialector{Conn: replicaPool},
+			sqlite.Dialector{Conn: primaryPool},
+		},
+		Policy: pkggorm.NewReplicaPolicy(time.Minute),
+	}))
+	assert.Equal(t, err, nil)
+
+	repo := repository.NewGeospatialRepository(primary)
+	err = repo.UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN", Name: "Written", Type: "Country", Level: 1, Geometry: "POLYGON((0 0,1 0,1 1,0 1,0 0))"},
+	})
+	assert.Equal(t, err, nil)
+
+	// Reads come from the replica, the write went to the primary only.
+	geospatials, err := repo.Get(context.TODO(), model.GeospatialFilter{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(geospatials), 1)
+	assert.Equal(t, geospatials[0].Name, "Replicated")
+
+	var name string
+	primary.Clauses(dbresolver.Write).Raw("SELECT name FROM geospatial").Scan(&name)
+	assert.Equal(t, name, "Written")
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/pkg/ratelimit"
+)
+
+func TestBucket(t *testing.T) {
+	limiter := ratelimit.New()
+	limit := ratelimit.Limit{Rate: 1, Burst: 3}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	for i, want := range []int{2, 1, 0} {
+		result := limiter.TakeAt("a", limit, 1, now)
+		assert.Equal(t, result.Allowed, true)
+		assert.Equal(t, result.Limit, 3)
+		assert.Equal(t, result.Remaining, want)
+		assert.Equal(t, result.Reset, time.Duration(i+1)*time.Second)
+		assert.Equal(t, result.Policy, "3;w=3")
+	}
+
+	result := limiter.TakeAt("a", limit, 1, now)
+	assert.Equal(t, result.Allowed, false)
+	assert.Equal(t, result.RetryAfter, time.Second)
+
+	// Other clients have their own bucket.
+	assert.Equal(t, limiter.TakeAt("b", limit, 1, now).Allowed, true)
+
+	// The bucket refills with time, up to its size.
+	result = limiter.TakeAt("a", limit, 2, now.Add(2*time.Second))
+	assert.Equal(t, result.Allowed, true)
+	assert.Equal(t, result.Remaining, 0)
+	result = limiter.TakeAt("a", limit, 1, now.Add(time.Hour))
+	assert.Equal(t, result.Remaining, 2)
+}
+
+func TestBucketCost(t *testing.T) {
+	limiter := ratelimit.New()
+	limit := ratelimit.Limit{Rate: 2, Burst: 10}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, limiter.TakeAt("a", limit, 8, now).Remaining, 2)
+
+	result := limiter.TakeAt("a", limit, 5, now)
+	assert.Equal(t, result.Allowed, false)
+	assert.Equal(t, result.Remaining, 2)
+	// 3 more tokens come back in 1.5 seconds.
+	assert.Equal(t, result.RetryAfter, 2*time.Second)
+
+	// A cost above the size of the bucket spends the whole bucket.
+	result = limiter.TakeAt("b", limit, 50, now)
+	assert.Equal(t, result.Allowed, true)
+	assert.Equal(t, result.Remaining, 0)
+}
+
+func TestDailyQuota(t *testing.T) {
+	limiter := ratelimit.New()
+	limit := ratelimit.Limit{Rate: 10, Burst: 10, DailyQuota: 5}
+	now := time.Date(2026, 10, 19, 23, 0, 0, 0, time.UTC)
+
+	result := limiter.TakeAt("a", limit, 3, now)
+	assert.Equal(t, result.Allowed, true)
+	// The quota has less left than the bucket.
+	assert.Equal(t, result.Limit, 5)
+	assert.Equal(t, result.Remaining, 2)
+	assert.Equal(t, result.Reset, time.Hour)
+	assert.Equal(t, result.Policy, "10;w=1, 5;w=86400")
+
+	result = limiter.TakeAt("a", limit, 3, now.Add(time.Minute))
+	assert.Equal(t, result.Allowed, false)
+	assert.Equal(t, result.RetryAfter, 59*time.Minute)
+
+	// The quota starts over at midnight UTC.
+	result = limiter.TakeAt("a", limit, 3, now.Add(time.Hour))
+	assert.Equal(t, result.Allowed, true)
+	assert.Equal(t, result.Remaining, 2)
+
+	// A cost above the quota spends the whole day, once.
+	result = limiter.TakeAt("b", limit, 20, now)
+	assert.Equal(t, result.Allowed, true)
+	assert.Equal(t, result.Remaining, 0)
+	result = limiter.TakeAt("b", limit, 20, now.Add(time.Minute))
+	assert.Equal(t, result.Allowed, false)
+}
+
+func TestUnlimited(t *testing.T) {
+	limiter := ratelimit.New()
+
+	result := limiter.Take("a", ratelimit.Limit{}, 100)
+	assert.Equal(t, result.Allowed, true)
+	assert.Equal(t, result.Limit, 0)
+
+	// A quota without a rate only counts the day.
+	limit := ratelimit.Limit{DailyQuota: 2}
+	assert.Equal(t, limiter.Take("a", limit, 1).Remaining, 1)
+	assert.Equal(t, limiter.Take("a", limit, 1).Remaining, 0)
+	assert.Equal(t, limiter.Take("a", limit, 1).Allowed, false)
+}
+
+func TestPeek(t *testing.T) {
+	limiter := ratelimit.New()
+	limit := ratelimit.Limit{Rate: 1, Burst: 2, DailyQuota: 5}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	// Peeking spends nothing.
+	for i := 0; i < 3; i++ {
+		result := limiter.PeekAt("a", limit, 1, now)
+		assert.Equal(t, result.Allowed, true)
+		assert.Equal(t, result.Remaining, 2)
+	}
+
+	limiter.TakeAt("a", limit, 2, now)
+	result := limiter.PeekAt("a", limit, 1, now)
+	assert.Equal(t, result.Allowed, false)
+	assert.Equal(t, result.RetryAfter, time.Second)
+	assert.Equal(t, limiter.TakeAt("a", limit, 1, now.Add(time.Second)).Allowed, true)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/pkg/search"
+)
+
+func TestFold(t *testing.T) {
+	assert.Equal(t, search.Fold("Daerah Istimewa Yógyakarta"), "daerah istimewa yogyakarta")
+	assert.Equal(t, search.Fold("  Kep. Bangka-Belitung "), "kep bangka belitung")
+	assert.Equal(t, search.Fold("São Tomé and Príncipe"), "sao tome and principe")
+	assert.Equal(t, search.Fold("..."), "")
+}
+
+func TestTrigrams(t *testing.T) {
+	trigrams := search.Trigrams("Aa ab")
+	sort.Strings(trigrams)
+	assert.Equal(t, trigrams, []string{"  a", " aa", " ab", "aa ", "ab "})
+}
+
+func TestScore(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		names     []string
+		wantOrder []string
+		wantKinds []string
+	}{
+		{
+			name:      "exact before prefix before word prefix",
+			query:     "jakarta",
+			names:     []string{"Jakarta Selatan", "Jakarta", "DKI Jakarta"},
+			wantOrder: []string{"Jakarta", "Jakarta Selatan", "DKI Jakarta"},
+			wantKinds: []string{search.MatchExact, search.MatchPrefix, search.MatchWordPrefix},
+		},
+		{
+			name:      "typo finds the closest name first",
+			query:     "Jogjakarta",
+			names:     []string{"Jakarta Raya", "Daerah Istimewa Yogyakarta", "Yogyakarta"},
+			wantOrder: []string{"Yogyakarta", "Daerah Istimewa Yogyakarta", "Jakarta Raya"},
+			wantKinds: []string{search.MatchFuzzy, search.MatchFuzzy, search.MatchFuzzy},
+		},
+		{
+			name:      "diacritics are folded",
+			query:     "sao tome",
+			names:     []string{"São Tomé"},
+			wantOrder: []string{"São Tomé"},
+			wantKinds: []string{search.MatchExact},
+		},
+		{
+			name:  "unrelated names do not match",
+			query: "kebayoran",
+			names: []string{"Banten", "Papua"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			scores := make(map[string]float64)
+			kinds := make(map[string]string)
+			var matched []string
+			for _, name := range tc.names {
+				if score, kind, ok := search.Score(tc.query, name); ok {
+					scores[name], kinds[name] = score, kind
+					matched = append(matched, name)
+				}
+			}
+			sort.SliceStable(matched, func(i, j int) bool { return scores[matched[i]] > scores[matched[j]] })
+
+			var gotKinds []string
+			for _, name := range matched {
+				gotKinds = append(gotKinds, kinds[name])
+			}
+			assert.Equal(t, matched, tc.wantOrder)
+			assert.Equal(t, gotKinds, tc.wantKinds)
+		})
+	}
+}
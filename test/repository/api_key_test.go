@@ -0,0 +1,79 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+)
+
+func TestAPIKeys(t *testing.T) {
+	repos := map[string]repository.APIKeyRepository{
+		"memory": repository.NewAPIKeyMemoryRepository(),
+		"sqlite": repository.NewAPIKeyRepository(openSqlite(t)),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			ci := &model.APIKey{Name: "ci", Prefix: "gsk_aaaaaaaa", Hash: "hash-ci", Role: "importer", Key: "gsk_secret"}
+			assert.Equal(t, repo.CreateAPIKey(ctx, ci), nil)
+			assert.NotEqual(t, ci.ID, uint(0))
+			dashboard := &model.APIKey{Name: "dashboard", Prefix: "gsk_bbbbbbbb", Hash: "hash-dashboard", Role: "reader"}
+			assert.Equal(t, repo.CreateAPIKey(ctx, dashboard), nil)
+
+			// The hashes are unique.
+			assert.NotEqual(t, repo.CreateAPIKey(ctx, &model.APIKey{Name: "copy", Prefix: "gsk_aaaaaaaa", Hash: "hash-ci", Role: "admin"}), nil)
+
+			keys, err := repo.GetAPIKeys(ctx, nil)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(keys), 2)
+			assert.Equal(t, keys[0].Name, "ci")
+			assert.Equal(t, keys[1].Name, "dashboard")
+			// The key itself is never stored.
+			assert.Equal(t, keys[0].Key, "")
+
+			keys, err = repo.GetAPIKeys(ctx, []uint{dashboard.ID})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(keys), 1)
+			assert.Equal(t, keys[0].Role, "reader")
+
+			found, err := repo.GetAPIKeyByHash(ctx, "hash-ci")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, found.ID, ci.ID)
+			assert.Equal(t, found.RevokedAt == nil, true)
+
+			missing, err := repo.GetAPIKeyByHash(ctx, "hash-unknown")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, missing == nil, true)
+
+			at := time.Now().UTC().Truncate(time.Second)
+			assert.Equal(t, repo.RevokeAPIKey(ctx, ci.ID, at), nil)
+			// Revoking again keeps the first time.
+			assert.Equal(t, repo.RevokeAPIKey(ctx, ci.ID, at.Add(time.Hour)), nil)
+
+			found, err = repo.GetAPIKeyByHash(ctx, "hash-ci")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, found.RevokedAt.Equal(at), true)
+			assert.Equal(t, found.Limit, model.RateLimit{})
+
+			rate, quota := 2.5, 1000
+			assert.Equal(t, repo.UpdateAPIKeyLimit(ctx, dashboard.ID, model.RateLimit{Rate: &rate, DailyQuota: &quota}), nil)
+			found, err = repo.GetAPIKeyByHash(ctx, "hash-dashboard")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, *found.Limit.Rate, 2.5)
+			assert.Equal(t, found.Limit.Burst == nil, true)
+			assert.Equal(t, *found.Limit.DailyQuota, 1000)
+
+			// Empty fields go back to the default.
+			assert.Equal(t, repo.UpdateAPIKeyLimit(ctx, dashboard.ID, model.RateLimit{}), nil)
+			keys, err = repo.GetAPIKeys(ctx, []uint{dashboard.ID})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, keys[0].Limit, model.RateLimit{})
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+func TestEventOutbox(t *testing.T) {
+	db := openSqlite(t)
+	memoryEvents := repository.NewEventMemoryRepository()
+	memoryGeospatial, err := repository.NewGeospatialMemoryRepository("", memoryEvents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backends := map[string]struct {
+		geospatial repository.GeospatialRepository
+		events     repository.EventRepository
+	}{
+		"memory": {memoryGeospatial, memoryEvents},
+		"sqlite": {repository.NewGeospatialRepository(db), repository.NewEventRepository(db)},
+	}
+
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+			repo := backend.events
+			now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+			all := &model.Webhook{URL: "http://all.test/hook", Secret: "s1"}
+			imports := &model.Webhook{URL: "http://imports.test/hook", Secret: "s2", Events: constant.EventImportCompleted + "," + constant.EventImportFailed}
+			assert.Equal(t, repo.CreateWebhook(ctx, all), nil)
+			assert.Equal(t, repo.CreateWebhook(ctx, imports), nil)
+
+			// Region events are written with the regions.
+			minLng, minLat, maxLng, maxLat := 106.0, -7.0, 107.0, -6.0
+			err := backend.geospatial.UpsertBulk(ctx, []model.Geospatial{{
+				GadmID:     "IDN",
+				Name:       "Indonesia",
+				Level:      1,
+				Geometry:   "MULTIPOLYGON (((106 -7, 107 -7, 107 -6, 106 -6, 106 -7)))",
+				BboxMinLng: &minLng, BboxMinLat: &minLat, BboxMaxLng: &maxLng, BboxMaxLat: &maxLat,
+			}}, model.OutboxEvent{Type: constant.EventRegionCreated, Payload: `{"gadm_id":"IDN"}`})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, repo.AddEvents(ctx, []model.OutboxEvent{{Type: constant.EventImportCompleted, Payload: `{"features":1}`}}), nil)
+
+			events, err := repo.GetEvents(ctx, []uint{1, 2})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(events), 2)
+			assert.Equal(t, events[0].Type, constant.EventRegionCreated)
+			assert.Equal(t, events[0].Payload, `{"gadm_id":"IDN"}`)
+
+			// Each event is fanned out once, to the webhooks subscribed to it.
+			dispatched, err := repo.FanOut(ctx, now, 100)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, dispatched, 2)
+			dispatched, err = repo.FanOut(ctx, now, 100)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, dispatched, 0)
+
+			claimed, err := repo.ClaimDeliveries(ctx, now, now.Add(time.Minute), 100)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(claimed), 3)
+			// Claimed deliveries are left alone until the claim runs out.
+			again, err := repo.ClaimDeliveries(ctx, now.Add(30*time.Second), now.Add(time.Minute), 100)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(again), 0)
+
+			for _, d := range claimed {
+				statusCode := 200
+				d.Attempts = 1
+				d.Status = constant.WebhookDeliveryDelivered
+				d.StatusCode = &statusCode
+				d.DeliveredAt = &now
+				if d.WebhookID == all.ID && d.EventType == constant.EventImportCompleted {
+					statusCode = 500
+					d.Status = constant.WebhookDeliveryPending
+					d.Error = "receiver answered 500"
+					d.DeliveredAt = nil
+					d.NextAttemptAt = now.Add(30 * time.Second)
+				}
+				assert.Equal(t, repo.SaveDelivery(ctx, d), nil)
+			}
+
+			retried, err := repo.ClaimDeliveries(ctx, now.Add(30*time.Second), now.Add(time.Minute), 100)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(retried), 1)
+			assert.Equal(t, retried[0].Attempts, uint(1))
+			assert.Equal(t, retried[0].Error, "receiver answered 500")
+
+			deliveries, meta, err := repo.GetDeliveriesPaginate(ctx, all.ID, pagination.Param{Limit: 1})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, meta.TotalRows, int64(2))
+			assert.Equal(t, deliveries[0].EventType, constant.EventImportCompleted)
+			assert.Equal(t, *deliveries[0].StatusCode, 500)
+
+			deliveries, _, err = repo.GetDeliveriesPaginate(ctx, imports.ID, pagination.Param{Limit: 10})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(deliveries), 1)
+			assert.Equal(t, deliveries[0].Status, constant.WebhookDeliveryDelivered)
+
+			assert.Equal(t, repo.DeleteWebhook(ctx, all.ID), nil)
+			webhooks, err := repo.GetWebhooks(ctx, nil)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(webhooks), 1)
+			assert.Equal(t, webhooks[0].Events, "import.completed,import.failed")
+			deliveries, _, err = repo.GetDeliveriesPaginate(ctx, all.ID, pagination.Param{Limit: 10})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(deliveries), 0)
+		})
+	}
+}
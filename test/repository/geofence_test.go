@@ -0,0 +1,174 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func geofenceNames(geofences []model.Geofence) []string {
+	var names []string
+	for _, g := range geofences {
+		names = append(names, g.Name)
+	}
+	return names
+}
+
+func TestGeofences(t *testing.T) {
+	repos := map[string]repository.GeofenceRepository{
+		"memory": repository.NewGeofenceMemoryRepository(),
+		"sqlite": repository.NewGeofenceRepository(openSqlite(t)),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			minLng, minLat, maxLng, maxLat := 106.0, -6.5, 107.0, -6.0
+			depot := model.Geofence{
+				Name:         "Depot",
+				Geometry:     "MULTIPOLYGON (((106 -6.5, 107 -6.5, 107 -6, 106 -6, 106 -6.5)))",
+				BboxMinLng:   &minLng,
+				BboxMinLat:   &minLat,
+				BboxMaxLng:   &maxLng,
+				BboxMaxLat:   &maxLat,
+				DwellSeconds: 60,
+			}
+			regionID := uint(7)
+			region := model.Geofence{Name: "Jakarta", GeospatialID: &regionID}
+
+			assert.Equal(t, repo.CreateGeofence(ctx, &depot), nil)
+			assert.Equal(t, repo.CreateGeofence(ctx, &region), nil)
+			assert.NotEqual(t, depot.ID, uint(0))
+
+			geofences, err := repo.GetGeofences(ctx, nil)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, geofenceNames(geofences), []string{"Depot", "Jakarta"})
+			assert.Equal(t, geofences[0].Geometry, depot.Geometry)
+			assert.Equal(t, geofences[0].DwellSeconds, uint(60))
+			assert.Equal(t, geofences[1].Geometry, "")
+			assert.Equal(t, *geofences[1].GeospatialID, regionID)
+
+			testCases := []struct {
+				name    string
+				lat     float64
+				lng     float64
+				regions []uint
+				want    []string
+			}{
+				{name: "in the polygon", lat: -6.2, lng: 106.5, want: []string{"Depot"}},
+				{name: "in the polygon and the region", lat: -6.2, lng: 106.5, regions: []uint{3, regionID}, want: []string{"Depot", "Jakarta"}},
+				{name: "in the region only", lat: -7.2, lng: 106.5, regions: []uint{regionID}, want: []string{"Jakarta"}},
+				{name: "in another region", lat: -7.2, lng: 106.5, regions: []uint{3}},
+				{name: "nowhere", lat: -7.2, lng: 106.5},
+				// 0 is a coordinate like any other, not a missing one.
+				{name: "on the equator", lat: 0, lng: 106.5},
+			}
+			for _, tc := range testCases {
+				containing, err := repo.GetContaining(ctx, tc.lat, tc.lng, tc.regions)
+				assert.Equal(t, err, nil)
+				assert.Equal(t, geofenceNames(containing), tc.want)
+			}
+
+			object, _, err := repo.GetObject(ctx, "truck-1")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, object == nil, true)
+
+			seenAt := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+			err = repo.SaveObject(ctx, model.GeofenceObject{ObjectID: "truck-1", Lat: -6.2, Lng: 106.5, SeenAt: seenAt}, []model.GeofenceState{
+				{ObjectID: "truck-1", GeofenceID: region.ID, EnteredAt: seenAt},
+				{ObjectID: "truck-1", GeofenceID: depot.ID, EnteredAt: seenAt, Dwelled: true},
+			})
+			assert.Equal(t, err, nil)
+
+			// Saving a new object that was saved meanwhile fails.
+			err = repo.SaveObject(ctx, model.GeofenceObject{ObjectID: "truck-1", Lat: -6.4, Lng: 106.5, SeenAt: seenAt}, nil)
+			assert.Equal(t, err, repository.ErrGeofenceObjectChanged)
+
+			object, _, err = repo.GetObject(ctx, "truck-1")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, object.Version, uint(1))
+
+			// Saving again replaces the position and the fences.
+			err = repo.SaveObject(ctx, model.GeofenceObject{ObjectID: "truck-1", Lat: -6.3, Lng: 106.5, SeenAt: seenAt.Add(time.Minute), Version: object.Version}, []model.GeofenceState{
+				{ObjectID: "truck-1", GeofenceID: depot.ID, EnteredAt: seenAt, Dwelled: true},
+			})
+			assert.Equal(t, err, nil)
+
+			// So does saving with the version read before that save.
+			err = repo.SaveObject(ctx, model.GeofenceObject{ObjectID: "truck-1", Lat: -6.4, Lng: 106.5, SeenAt: seenAt, Version: object.Version}, nil)
+			assert.Equal(t, err, repository.ErrGeofenceObjectChanged)
+
+			object, states, err := repo.GetObject(ctx, "truck-1")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, object.Version, uint(2))
+			assert.Equal(t, object.Lat, -6.3)
+			assert.Equal(t, object.SeenAt.Equal(seenAt.Add(time.Minute)), true)
+			assert.Equal(t, len(states), 1)
+			assert.Equal(t, states[0].GeofenceID, depot.ID)
+			assert.Equal(t, states[0].Dwelled, true)
+
+			// Deleting a fence drops the objects' state in it.
+			assert.Equal(t, repo.DeleteGeofence(ctx, depot.ID), nil)
+			_, states, err = repo.GetObject(ctx, "truck-1")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(states), 0)
+
+			containing, err := repo.GetContaining(ctx, -6.2, 106.5, []uint{regionID})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, geofenceNames(containing), []string{"Jakarta"})
+		})
+	}
+}
+
+func TestGeofenceContainingDialects(t *testing.T) {
+	testCases := []struct {
+		name      string
+		dialector gorm.Dialector
+		wantSQL   string
+	}{
+		{
+			name:      "mysql",
+			dialector: mysql.New(mysql.Config{Conn: dryRunConnPool{}, SkipInitializeWithVersion: true}),
+			wantSQL:   "WHERE (geospatial_id IS NULL AND ST_Contains(geometry, ST_GeomFromText(?))) OR geospatial_id IN (?,?)",
+		},
+		{
+			name:      "postgres",
+			dialector: postgres.New(postgres.Config{Conn: dryRunConnPool{}}),
+			wantSQL:   "WHERE (geospatial_id IS NULL AND ST_Contains(geometry, ST_GeomFromText($1, 4326))) OR geospatial_id IN ($2,$3)",
+		},
+		{
+			name:      "sqlite",
+			dialector: sqlite.Open(":memory:"),
+			wantSQL:   "SELECT id FROM geofence_rtree WHERE",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db := dryRunDb(t, tc.dialector)
+
+			var sql string
+			db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+				sql = tx.Statement.SQL.String()
+			})
+
+			repo := repository.NewGeofenceRepository(db)
+			_, _ = repo.GetContaining(context.TODO(), -6.2, 106.5, []uint{3, 7})
+
+			if !strings.Contains(sql, tc.wantSQL) {
+				t.Errorf("expected %q in %q", tc.wantSQL, sql)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+// cursorPages pages through every region one at a time and returns the names page by page.
+func cursorPages(t *testing.T, repo repository.GeospatialRepository, sorts []pagination.ParamSort) []string {
+	var result []string
+	param := pagination.Param{Limit: 1, Sort: sorts, UseCursor: true}
+	for i := 0; i < 10; i++ {
+		page, meta, err := repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, param)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result = append(result, names(page)...)
+		if meta.NextCursor == "" {
+			return result
+		}
+		param.Cursor = meta.NextCursor
+	}
+	t.Fatal("cursor pages do not end")
+	return nil
+}
+
+func TestGeospatialCursorPagination(t *testing.T) {
+	repos := map[string]repository.GeospatialRepository{
+		"memory": newMemoryRepository(t),
+		"sqlite": newSqliteRepository(t),
+	}
+
+	testCases := []struct {
+		name  string
+		sorts []pagination.ParamSort
+		want  []string
+	}{
+		{
+			name: "default order",
+			want: []string{"Jakarta Selatan", "Banten", "Jakarta Raya", "Indonesia"},
+		},
+		{
+			name:  "level then name",
+			sorts: []pagination.ParamSort{{Column: "level", Order: "asc"}, {Column: "name", Order: "desc"}},
+			want:  []string{"Indonesia", "Jakarta Raya", "Banten", "Jakarta Selatan"},
+		},
+		{
+			name:  "ties on a nullable column fall back to the descending id",
+			sorts: []pagination.ParamSort{{Column: "area_km2", Order: "desc"}},
+			want:  []string{"Indonesia", "Banten", "Jakarta Raya", "Jakarta Selatan"},
+		},
+	}
+
+	for name, repo := range repos {
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(name+"/"+tc.name, func(t *testing.T) {
+				assert.Equal(t, cursorPages(t, repo, tc.sorts), tc.want)
+			})
+		}
+
+		t.Run(name+"/totals", func(t *testing.T) {
+			_, meta, err := repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{Limit: 3, UseCursor: true})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, meta.TotalRows, int64(4))
+			assert.NotEqual(t, meta.NextCursor, "")
+
+			_, meta, err = repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{Limit: 3, UseCursor: true, SkipTotal: true})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, meta.TotalRows, int64(0))
+		})
+
+		t.Run(name+"/invalid", func(t *testing.T) {
+			_, meta, err := repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{Limit: 1, UseCursor: true})
+			assert.Equal(t, err, nil)
+
+			// The cursor belongs to the default order.
+			sorts := []pagination.ParamSort{{Column: "name", Order: "asc"}}
+			_, _, err = repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{Limit: 1, UseCursor: true, Cursor: meta.NextCursor, Sort: sorts})
+			assert.Equal(t, err, pagination.ErrInvalidCursor)
+
+			_, _, err = repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{Limit: 1, UseCursor: true, Cursor: "not a cursor"})
+			assert.Equal(t, err, pagination.ErrInvalidCursor)
+
+			sorts = []pagination.ParamSort{{Column: "geometry", Order: "asc"}}
+			_, _, err = repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{Limit: 1, UseCursor: true, Sort: sorts})
+			assert.Equal(t, err, pagination.ErrInvalidSort)
+		})
+	}
+}
+
+func TestGeospatialSortByDistance(t *testing.T) {
+	repos := map[string]repository.GeospatialRepository{
+		"memory": newMemoryRepository(t),
+		"sqlite": newSqliteRepository(t),
+	}
+	sorts := []pagination.ParamSort{{Column: "distance_km", Order: "asc"}}
+	filter := model.GeospatialFilter{Near: &model.LatLng{Lat: -6.12, Lng: 106.15}}
+	want := []string{"Jakarta Raya", "Banten", "Jakarta Selatan", "Indonesia"}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			page, _, err := repo.GetPaginate(context.TODO(), filter, pagination.Param{Limit: 10, Sort: sorts})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, names(page), want)
+			assert.Equal(t, math.Round(*page[0].DistanceKm), 57.0)
+
+			var pages []string
+			param := pagination.Param{Limit: 1, Sort: sorts, UseCursor: true}
+			for i := 0; i < 10; i++ {
+				page, meta, err := repo.GetPaginate(context.TODO(), filter, param)
+				assert.Equal(t, err, nil)
+				pages = append(pages, names(page)...)
+				if meta.NextCursor == "" {
+					break
+				}
+				param.Cursor = meta.NextCursor
+			}
+			assert.Equal(t, pages, want)
+
+			_, _, err = repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{Limit: 1, Sort: sorts})
+			assert.Equal(t, err, pagination.ErrInvalidSort)
+		})
+	}
+}
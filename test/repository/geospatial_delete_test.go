@@ -0,0 +1,47 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+)
+
+func TestGeospatialDelete(t *testing.T) {
+	repos := map[string]repository.GeospatialRepository{
+		"memory": newMemoryRepository(t),
+		"sqlite": newSqliteRepository(t),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+			assert.Equal(t, repo.IndexNeighbors(ctx), nil)
+
+			// Banten goes with its names and its border with Jakarta Raya.
+			assert.Equal(t, repo.Delete(ctx, []uint{3}), nil)
+
+			geospatials, err := repo.Get(ctx, model.GeospatialFilter{IDs: []uint{2, 3}})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(geospatials), 1)
+			assert.Equal(t, geospatials[0].Name, "Jakarta Raya")
+
+			geospatials, err = repo.GetByGadmIds(ctx, []string{"IDN.3_1"})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(geospatials), 0)
+
+			geospatials, err = repo.Search(ctx, "Banten", model.GeospatialFilter{}, 10)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(geospatials), 0)
+
+			neighbors, err := repo.GetNeighbors(ctx, 2)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(neighbors), 0)
+
+			// Unknown ids are left alone.
+			assert.Equal(t, repo.Delete(ctx, []uint{99}), nil)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+func TestGeospatialIntersects(t *testing.T) {
+	repos := map[string]repository.GeospatialRepository{
+		"memory": newMemoryRepository(t),
+		"sqlite": newSqliteRepository(t),
+	}
+
+	testCases := []struct {
+		name   string
+		filter model.GeospatialFilter
+		want   []string
+	}{
+		{
+			name:   "polygon",
+			filter: model.GeospatialFilter{Intersects: "POLYGON ((106.6 -6.9, 106.8 -6.9, 106.8 -6.8, 106.6 -6.8, 106.6 -6.9))"},
+			want:   []string{"Indonesia", "Jakarta Raya", "Jakarta Selatan"},
+		},
+		{
+			name:   "line",
+			filter: model.GeospatialFilter{Intersects: "LINESTRING (105.5 -6.2, 106.2 -6.2)"},
+			want:   []string{"Banten", "Indonesia", "Jakarta Raya"},
+		},
+		{
+			name:   "line with levels",
+			filter: model.GeospatialFilter{Intersects: "LINESTRING (105.5 -6.2, 106.2 -6.2)", Levels: []uint{2}},
+			want:   []string{"Banten", "Jakarta Raya"},
+		},
+		{
+			name:   "polygon apart",
+			filter: model.GeospatialFilter{Intersects: "POLYGON ((130 -5, 131 -5, 131 -4, 130 -4, 130 -5))"},
+			want:   nil,
+		},
+	}
+
+	for name, repo := range repos {
+		for _, tc := range testCases {
+			t.Run(name+"/"+tc.name, func(t *testing.T) {
+				page, _, err := repo.GetPaginate(context.TODO(), tc.filter, pagination.Param{Limit: 10})
+				assert.Equal(t, err, nil)
+
+				got := names(page)
+				sort.Strings(got)
+				assert.Equal(t, got, tc.want)
+			})
+		}
+	}
+}
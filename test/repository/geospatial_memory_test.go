@@ -0,0 +1,239 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/export"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+const gadmFixture = `{"type":"FeatureCollection","features":[
+{"type":"Feature","properties":{"GID_0":"IDN","COUNTRY":"Indonesia"},"geometry":{"type":"MultiPolygon","coordinates":[[[[100,-10],[120,-10],[120,0],[100,0],[100,-10]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.7_1","COUNTRY":"Indonesia","NAME_1":"Jakarta Raya","TYPE_1":"Propinsi","ENGTYPE_1":"Special District"},"geometry":{"type":"MultiPolygon","coordinates":[[[[106,-7],[107,-7],[107,-6],[106,-6],[106,-7]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.3_1","COUNTRY":"Indonesia","NAME_1":"Banten","TYPE_1":"Propinsi","ENGTYPE_1":"Province"},"geometry":{"type":"MultiPolygon","coordinates":[[[[105,-7],[106,-7],[106,-6],[105,-6],[105,-7]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.7_1","GID_2":"IDN.7.1_1","COUNTRY":"Indonesia","NAME_1":"Jakarta Raya","NAME_2":"Jakarta Selatan","TYPE_2":"Kota","ENGTYPE_2":"City"},"geometry":{"type":"MultiPolygon","coordinates":[[[[106.5,-7],[107,-7],[107,-6.5],[106.5,-6.5],[106.5,-7]]]]}}
+]}`
+
+func newMemoryRepository(t *testing.T) repository.GeospatialRepository {
+	path := filepath.Join(t.TempDir(), "gadm.json")
+	if err := os.WriteFile(path, []byte(gadmFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := repository.NewGeospatialMemoryRepository(path, nil)
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+	return repo
+}
+
+// pointFilter returns the filter of the regions containing lat, lng.
+func pointFilter(lat, lng float64) model.GeospatialFilter {
+	return model.GeospatialFilter{Lat: &lat, Lng: &lng}
+}
+
+func names(geospatials []model.Geospatial) []string {
+	var result []string
+	for _, g := range geospatials {
+		result = append(result, g.Name)
+	}
+	return result
+}
+
+func TestGeospatialMemoryGet(t *testing.T) {
+	repo := newMemoryRepository(t)
+
+	testCases := []struct {
+		name   string
+		filter model.GeospatialFilter
+		want   []string
+	}{
+		{
+			name:   "point in polygon",
+			filter: pointFilter(-6.8, 106.8),
+			want:   []string{"Indonesia", "Jakarta Raya", "Jakarta Selatan"},
+		},
+		{
+			name:   "point outside every region",
+			filter: pointFilter(10, 10),
+		},
+		{
+			name:   "bbox",
+			filter: model.GeospatialFilter{Bbox: []float64{105.1, -6.9, 105.2, -6.8}},
+			want:   []string{"Indonesia", "Banten"},
+		},
+		{
+			name:   "name is case insensitive",
+			filter: model.GeospatialFilter{Name: "jakarta"},
+			want:   []string{"Jakarta Raya", "Jakarta Selatan"},
+		},
+		{
+			name:   "levels and types",
+			filter: model.GeospatialFilter{Levels: []uint{2}, Types: []string{"Propinsi"}, ExcludedIds: []uint{2}},
+			want:   []string{"Banten"},
+		},
+		{
+			name:   "parent ids",
+			filter: model.GeospatialFilter{ParentIds: []uint{2}},
+			want:   []string{"Jakarta Selatan"},
+		},
+		{
+			name:   "area",
+			filter: model.GeospatialFilter{MinAreaKm2: 5000, MaxAreaKm2: 20000},
+			want:   []string{"Jakarta Raya", "Banten"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := repo.Get(context.TODO(), tc.filter)
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.want, names(result))
+		})
+	}
+}
+
+func TestGeospatialMemoryGetGeometry(t *testing.T) {
+	repo := newMemoryRepository(t)
+
+	geospatials, err := repo.Get(context.TODO(), model.GeospatialFilter{IDs: []uint{2}})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Jakarta Raya"})
+	assert.Equal(t, geospatials[0].Geometry, "")
+
+	geospatials, err = repo.GetWithGeometry(context.TODO(), model.GeospatialFilter{IDs: []uint{2}})
+	assert.Equal(t, err, nil)
+	assert.NotEqual(t, geospatials[0].Geometry, "")
+}
+
+func TestGeospatialMemoryGetPaginate(t *testing.T) {
+	config.Config = &config.Cfg{Data: config.Data{MaxRows: 10}}
+	repo := newMemoryRepository(t)
+
+	result, meta, err := repo.GetPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{
+		Limit: 2,
+		Page:  2,
+		Sort:  []pagination.ParamSort{{Column: "level", Order: "asc"}, {Column: "name", Order: "desc"}},
+	})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Banten", "Jakarta Selatan"}, names(result))
+	assert.Equal(t, int64(4), meta.TotalRows)
+	assert.Equal(t, uint(2), meta.TotalPages)
+}
+
+func TestGeospatialMemoryTypesAndExamples(t *testing.T) {
+	repo := newMemoryRepository(t)
+
+	types, err := repo.GetTypes(context.TODO(), model.GeospatialFilter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []model.GeospatialTypeCount{
+		{Country: "IDN", EngType: "COUNTRY", Type: "Country", Level: 1, Count: 1},
+		{Country: "IDN", EngType: "DISTRICT", Type: "Propinsi", Level: 2, Count: 1},
+		{Country: "IDN", EngType: "PROVINCE", Type: "Propinsi", Level: 2, Count: 1},
+		{Country: "IDN", EngType: "CITY", Type: "Kota", Level: 3, Count: 1},
+	}, types)
+
+	types, err = repo.GetTypes(context.TODO(), model.GeospatialFilter{AncestorID: 1, Levels: []uint{3}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []model.GeospatialTypeCount{{Country: "IDN", EngType: "CITY", Type: "Kota", Level: 3, Count: 1}}, types)
+
+	types, err = repo.GetTypes(context.TODO(), model.GeospatialFilter{Country: "MYS"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []model.GeospatialTypeCount{}, types)
+
+	result, err := repo.Get(context.TODO(), model.GeospatialFilter{Types: []string{"PROVINCE", "Kota"}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Banten", "Jakarta Selatan"}, names(result))
+
+	examples, err := repo.GetExamples(context.TODO(), model.GeospatialFilter{Levels: []uint{2, 3}}, 2)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Jakarta Raya", "Banten"}, names(examples))
+}
+
+func TestGeospatialMemorySearch(t *testing.T) {
+	repo := newMemoryRepository(t)
+
+	geospatials, err := repo.Search(context.TODO(), "jakarta", model.GeospatialFilter{}, 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Jakarta Raya", "Jakarta Selatan"})
+
+	geospatials, err = repo.Search(context.TODO(), "selatn", model.GeospatialFilter{ParentIds: []uint{2}}, 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Jakarta Selatan"})
+
+	// Renaming a region replaces its trigrams.
+	err = repo.UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN.3_1", ParentGadmID: "IDN", Name: "Bántên Baru", Type: "Propinsi", Level: 2, Geometry: "MULTIPOLYGON(((105 -7,106 -7,106 -6,105 -6,105 -7)))"},
+	})
+	assert.Equal(t, err, nil)
+
+	geospatials, err = repo.Search(context.TODO(), "banten baru", model.GeospatialFilter{}, 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Bántên Baru"})
+	assert.Equal(t, geospatials[0].Match, "exact")
+
+	// Alternate names are searched too.
+	err = repo.UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN.7_1", ParentGadmID: "IDN", Name: "Jakarta Raya", Type: "Propinsi", Level: 2, Geometry: "MULTIPOLYGON(((106 -7,107 -7,107 -6,106 -6,106 -7)))",
+			Names: []model.GeospatialName{{Name: "Batavia", Language: "nl", Kind: "historical"}}},
+	})
+	assert.Equal(t, err, nil)
+
+	geospatials, err = repo.Search(context.TODO(), "batavia", model.GeospatialFilter{}, 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Jakarta Raya"})
+	assert.Equal(t, geospatials[0].MatchedName, "Batavia")
+
+	regionNames, err := repo.GetNames(context.TODO(), []uint{2})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(regionNames), 1)
+	assert.Equal(t, regionNames[0].GeospatialID, uint(2))
+}
+
+func TestGeospatialMemoryUpsertBulk(t *testing.T) {
+	repo := newMemoryRepository(t)
+
+	err := repo.UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN.3_1", ParentGadmID: "IDN", Name: "Banten", Type: "Propinsi", Level: 2, Geometry: "MULTIPOLYGON(((10 10,11 10,11 11,10 11,10 10)))"},
+		{GadmID: "IDN.9_1", ParentGadmID: "IDN", Name: "Jawa Barat", Type: "Propinsi", Level: 2, Geometry: "MULTIPOLYGON(((107 -8,109 -8,109 -6,107 -6,107 -8)))"},
+	})
+	assert.Equal(t, nil, err)
+
+	moved, _ := repo.Get(context.TODO(), pointFilter(10.5, 10.5))
+	assert.Equal(t, []string{"Banten"}, names(moved))
+	assert.Equal(t, uint(3), moved[0].ID)
+
+	old, _ := repo.Get(context.TODO(), pointFilter(-6.5, 105.5))
+	assert.Equal(t, []string{"Indonesia"}, names(old))
+
+	added, _ := repo.Get(context.TODO(), model.GeospatialFilter{Name: "Jawa"})
+	assert.Equal(t, uint(5), added[0].ID)
+}
+
+func TestGeospatialMemoryLoadGeoPackage(t *testing.T) {
+	source := newMemoryRepository(t)
+	geospatials, err := source.GetWithGeometry(context.TODO(), model.GeospatialFilter{})
+	assert.Equal(t, nil, err)
+
+	path := filepath.Join(t.TempDir(), "geospatial.gpkg")
+	assert.Equal(t, nil, export.WriteGeoPackage(context.TODO(), path, geospatials))
+
+	repo, err := repository.NewGeospatialMemoryRepository(path, nil)
+	assert.Equal(t, nil, err)
+
+	result, err := repo.Get(context.TODO(), pointFilter(-6.8, 106.8))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Indonesia", "Jakarta Raya", "Jakarta Selatan"}, names(result))
+	assert.Equal(t, uint(4), result[2].ID)
+	assert.Equal(t, "IDN.7_1", result[2].ParentGadmID)
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+)
+
+func TestGeospatialNeighbors(t *testing.T) {
+	repos := map[string]repository.GeospatialRepository{
+		"memory": newMemoryRepository(t),
+		"sqlite": newSqliteRepository(t),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, repo.IndexNeighbors(context.TODO()), nil)
+
+			// Jakarta Raya and Banten share a degree of longitude 106.
+			neighbors, err := repo.GetNeighbors(context.TODO(), 2)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(neighbors), 1)
+			assert.Equal(t, neighbors[0].NeighborID, uint(3))
+			assert.Equal(t, math.Round(neighbors[0].BorderKm), 111.0)
+
+			neighbors, err = repo.GetNeighbors(context.TODO(), 3)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(neighbors), 1)
+			assert.Equal(t, neighbors[0].NeighborID, uint(2))
+
+			// Indonesia contains them, but it is not of their level.
+			neighbors, err = repo.GetNeighbors(context.TODO(), 1)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(neighbors), 0)
+
+			// A custom area lying on top of them does not border them.
+			err = repo.UpsertBulk(context.TODO(), []model.Geospatial{
+				{GadmID: "CUSTOM.west", ParentGadmID: "IDN", Name: "West", Type: "Custom", EngType: "CUSTOM", Level: 2, Geometry: "POLYGON((105 -7,107 -7,107 -6,105 -6,105 -7))"},
+			})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, repo.IndexNeighbors(context.TODO()), nil)
+
+			neighbors, err = repo.GetNeighbors(context.TODO(), 2)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(neighbors), 1)
+
+			neighbors, err = repo.GetNeighbors(context.TODO(), 5)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(neighbors), 0)
+
+			// Moving Banten away only recomputes the pairs it is part of.
+			err = repo.UpsertBulk(context.TODO(), []model.Geospatial{
+				{GadmID: "IDN.3_1", ParentGadmID: "IDN", Name: "Banten", Type: "Propinsi", Level: 2, Geometry: "POLYGON((0 0,1 0,1 1,0 1,0 0))"},
+			})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, repo.IndexNeighbors(context.TODO()), nil)
+
+			neighbors, err = repo.GetNeighbors(context.TODO(), 2)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(neighbors), 0)
+		})
+	}
+}
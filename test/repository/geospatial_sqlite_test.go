@@ -0,0 +1,284 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/gadm"
+	pkggorm "github.com/si-bas/go-rest-geospatial/pkg/gorm"
+	"github.com/twpayne/go-geom/encoding/geojson"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// openSqlite returns a migrated SQLite database in a temporary directory.
+func openSqlite(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "geospatial.sqlite")), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		sqlDb, _ := db.DB()
+		sqlDb.Close()
+	})
+
+	if err := pkggorm.MigrateSqlite(db); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	// Migrating twice is a no-op.
+	if err := pkggorm.MigrateSqlite(db); err != nil {
+		t.Fatalf("failed to migrate again: %v", err)
+	}
+	return db
+}
+
+func newSqliteRepository(t *testing.T) repository.GeospatialRepository {
+	db := openSqlite(t)
+
+	var fc geojson.FeatureCollection
+	if err := json.Unmarshal([]byte(gadmFixture), &fc); err != nil {
+		t.Fatal(err)
+	}
+
+	var geospatials []model.Geospatial
+	for _, f := range fc.Features {
+		g, err := gadm.ParseFeature(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		geospatials = append(geospatials, g)
+	}
+
+	repo := repository.NewGeospatialRepository(db)
+	if err := repo.UpsertBulk(context.TODO(), geospatials); err != nil {
+		t.Fatalf("failed to upsert: %v", err)
+	}
+	return repo
+}
+
+func TestGeospatialSqliteGet(t *testing.T) {
+	repo := newSqliteRepository(t)
+
+	testCases := []struct {
+		name   string
+		filter model.GeospatialFilter
+		want   []string
+	}{
+		{
+			name:   "point in polygon",
+			filter: pointFilter(-6.8, 106.8),
+			want:   []string{"Indonesia", "Jakarta Raya", "Jakarta Selatan"},
+		},
+		{
+			name:   "point outside every region",
+			filter: pointFilter(10, 10),
+		},
+		{
+			name:   "bbox",
+			filter: model.GeospatialFilter{Bbox: []float64{105.1, -6.9, 105.2, -6.8}},
+			want:   []string{"Indonesia", "Banten"},
+		},
+		{
+			name:   "name is case insensitive",
+			filter: model.GeospatialFilter{Name: "jakarta"},
+			want:   []string{"Jakarta Raya", "Jakarta Selatan"},
+		},
+		{
+			name:   "parent ids",
+			filter: model.GeospatialFilter{ParentIds: []uint{2}},
+			want:   []string{"Jakarta Selatan"},
+		},
+		{
+			name:   "local or normalized types",
+			filter: model.GeospatialFilter{Types: []string{"PROVINCE", "Kota"}},
+			want:   []string{"Banten", "Jakarta Selatan"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			geospatials, err := repo.Get(context.TODO(), tc.filter)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, names(geospatials), tc.want)
+		})
+	}
+}
+
+func TestGeospatialSqliteGetColumns(t *testing.T) {
+	repo := newSqliteRepository(t)
+
+	geospatials, err := repo.Get(context.TODO(), model.GeospatialFilter{IDs: []uint{2}, Columns: []string{"id", "name"}})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(geospatials), 1)
+	assert.Equal(t, geospatials[0].Name, "Jakarta Raya")
+	assert.Equal(t, geospatials[0].Level, uint(0))
+	assert.Equal(t, geospatials[0].Geometry, "")
+
+	geospatials, err = repo.Get(context.TODO(), model.GeospatialFilter{IDs: []uint{2}, WithGeometry: true})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, geospatials[0].Level, uint(2))
+	assert.Equal(t, strings.HasPrefix(geospatials[0].Geometry, "MULTIPOLYGON"), true)
+}
+
+func TestGeospatialSqliteTypesAndExamples(t *testing.T) {
+	repo := newSqliteRepository(t)
+
+	types, err := repo.GetTypes(context.TODO(), model.GeospatialFilter{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, types, []model.GeospatialTypeCount{
+		{Country: "IDN", EngType: "COUNTRY", Type: "Country", Level: 1, Count: 1},
+		{Country: "IDN", EngType: "DISTRICT", Type: "Propinsi", Level: 2, Count: 1},
+		{Country: "IDN", EngType: "PROVINCE", Type: "Propinsi", Level: 2, Count: 1},
+		{Country: "IDN", EngType: "CITY", Type: "Kota", Level: 3, Count: 1},
+	})
+
+	// Every region below the country, across levels.
+	types, err = repo.GetTypes(context.TODO(), model.GeospatialFilter{AncestorID: 1, Country: "IDN"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(types), 3)
+
+	types, err = repo.GetTypes(context.TODO(), model.GeospatialFilter{AncestorID: 2})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, types, []model.GeospatialTypeCount{{Country: "IDN", EngType: "CITY", Type: "Kota", Level: 3, Count: 1}})
+
+	examples, err := repo.GetExamples(context.TODO(), model.GeospatialFilter{Levels: []uint{2, 3}}, 2)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(examples), []string{"Jakarta Raya", "Banten"})
+}
+
+func TestGeospatialSqliteUpsertBulk(t *testing.T) {
+	repo := newSqliteRepository(t)
+
+	// Moving Banten must move its R-tree entry too.
+	err := repo.UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN.3_1", ParentGadmID: "IDN", Name: "Banten", Type: "Provinsi", Level: 2, Geometry: "POLYGON((0 0,1 0,1 1,0 1,0 0))"},
+	})
+	assert.Equal(t, err, nil)
+
+	var bbox = []float64{0.1, 0.1, 0.2, 0.2}
+	geospatials, err := repo.Get(context.TODO(), model.GeospatialFilter{Bbox: bbox})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string(nil))
+
+	minLng, minLat, maxLng, maxLat := 0.0, 0.0, 1.0, 1.0
+	err = repo.UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN.3_1", ParentGadmID: "IDN", Name: "Banten", Type: "Provinsi", Level: 2, Geometry: "POLYGON((0 0,1 0,1 1,0 1,0 0))",
+			BboxMinLng: &minLng, BboxMinLat: &minLat, BboxMaxLng: &maxLng, BboxMaxLat: &maxLat},
+	})
+	assert.Equal(t, err, nil)
+
+	geospatials, err = repo.GetWithGeometry(context.TODO(), pointFilter(0.5, 0.5))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Banten"})
+	assert.Equal(t, geospatials[0].ID, uint(3))
+	assert.Equal(t, geospatials[0].Type, "Provinsi")
+	assert.Equal(t, strings.HasPrefix(geospatials[0].Geometry, "MULTIPOLYGON"), true)
+
+	geospatials, err = repo.Get(context.TODO(), pointFilter(-6.5, 105.5))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Indonesia"})
+}
+
+func TestGeospatialSqliteSearch(t *testing.T) {
+	repo := newSqliteRepository(t)
+
+	geospatials, err := repo.Search(context.TODO(), "jakarta", model.GeospatialFilter{}, 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Jakarta Raya", "Jakarta Selatan"})
+	assert.Equal(t, geospatials[0].Match, "prefix")
+	assert.Equal(t, *geospatials[0].Score > *geospatials[1].Score, true)
+
+	geospatials, err = repo.Search(context.TODO(), "Bantén", model.GeospatialFilter{}, 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Banten"})
+	assert.Equal(t, geospatials[0].Match, "exact")
+
+	geospatials, err = repo.Search(context.TODO(), "selatn", model.GeospatialFilter{Levels: []uint{3}}, 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Jakarta Selatan"})
+	assert.Equal(t, geospatials[0].Match, "fuzzy")
+
+	geospatials, err = repo.Search(context.TODO(), "jakarta", model.GeospatialFilter{}, 1)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Jakarta Raya"})
+}
+
+func TestGeospatialSqliteAlternateNames(t *testing.T) {
+	repo := newSqliteRepository(t)
+
+	err := repo.UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN.34_1", ParentGadmID: "IDN", Name: "Yogyakarta", Type: "Daerah Istimewa", Level: 2, Geometry: "POLYGON((110 -8,111 -8,111 -7.5,110 -8))",
+			Names: []model.GeospatialName{{Name: "Jogjakarta", Kind: "variant"}, {Name: "Ngayogyakarta", Language: "jv", Kind: "official"}}},
+	})
+	assert.Equal(t, err, nil)
+
+	geospatials, err := repo.Search(context.TODO(), "jogjakarta", model.GeospatialFilter{}, 1)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Yogyakarta"})
+	assert.Equal(t, geospatials[0].Match, "exact")
+	assert.Equal(t, geospatials[0].MatchedName, "Jogjakarta")
+
+	geospatials, err = repo.Search(context.TODO(), "ngayog", model.GeospatialFilter{}, 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, names(geospatials), []string{"Yogyakarta"})
+	assert.Equal(t, geospatials[0].Match, "prefix")
+
+	// Importing again replaces the names.
+	err = repo.UpsertBulk(context.TODO(), []model.Geospatial{
+		{GadmID: "IDN.34_1", ParentGadmID: "IDN", Name: "Yogyakarta", Type: "Daerah Istimewa", Level: 2, Geometry: "POLYGON((110 -8,111 -8,111 -7.5,110 -8))",
+			Names: []model.GeospatialName{{Name: "Jogja", Kind: "variant"}}},
+	})
+	assert.Equal(t, err, nil)
+
+	regionNames, err := repo.GetNames(context.TODO(), []uint{5})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(regionNames), 1)
+	assert.Equal(t, regionNames[0].Name, "Jogja")
+	assert.Equal(t, regionNames[0].SearchName, "jogja")
+}
+
+func TestGeospatialPointOnZero(t *testing.T) {
+	repos := map[string]repository.GeospatialRepository{
+		"memory": newMemoryRepository(t),
+		"sqlite": newSqliteRepository(t),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			minLng, minLat, maxLng, maxLat := -1.0, -1.0, 1.0, 1.0
+			err := repo.UpsertBulk(context.TODO(), []model.Geospatial{
+				{GadmID: "NUL", Name: "Null Island", Level: 1, Geometry: "MULTIPOLYGON(((-1 -1,1 -1,1 1,-1 1,-1 -1)))",
+					BboxMinLng: &minLng, BboxMinLat: &minLat, BboxMaxLng: &maxLng, BboxMaxLat: &maxLat},
+			})
+			assert.Equal(t, err, nil)
+
+			testCases := []struct {
+				name     string
+				lat, lng float64
+			}{
+				{name: "on the equator", lat: 0, lng: 0.5},
+				{name: "on the prime meridian", lat: 0.5, lng: 0},
+				{name: "on both", lat: 0, lng: 0},
+			}
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					geospatials, err := repo.Get(context.TODO(), pointFilter(tc.lat, tc.lng))
+					assert.Equal(t, err, nil)
+					assert.Equal(t, names(geospatials), []string{"Null Island"})
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func dryRunDb(t *testing.T, dialector gorm.Dialector) *gorm.DB {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry run db: %v", err)
+	}
+	return db
+}
+
+// dryRunConnPool lets dry run statements open transactions without a database server.
+type dryRunConnPool struct{}
+
+func (p dryRunConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("dry run")
+}
+
+func (p dryRunConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errors.New("dry run")
+}
+
+func (p dryRunConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("dry run")
+}
+
+func (p dryRunConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (p dryRunConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return p, nil
+}
+
+func (p dryRunConnPool) Commit() error {
+	return nil
+}
+
+func (p dryRunConnPool) Rollback() error {
+	return nil
+}
+
+// filteredDbRepository is implemented by the SQL backed repositories only.
+type filteredDbRepository interface {
+	FilteredDb(model.GeospatialFilter) *gorm.DB
+}
+
+func TestGeospatialFilteredDbDialects(t *testing.T) {
+	filter := pointFilter(-6.2, 106.8)
+	filter.Name = "jakarta"
+	filter.Levels = []uint{2}
+	filter.AncestorID = 1
+	filter.Country = "IDN"
+	filter.MinAreaKm2 = 100
+	filter.Bbox = []float64{106, -7, 107, -6}
+	filter.Intersects = "LINESTRING (106 -6.5, 107 -6.5)"
+
+	testCases := []struct {
+		name      string
+		dialector gorm.Dialector
+		wantSQL   []string
+	}{
+		{
+			name:      "mysql",
+			dialector: mysql.New(mysql.Config{DSN: "user:pass@tcp(localhost:3306)/db", SkipInitializeWithVersion: true}),
+			wantSQL:   []string{"name LIKE ?", "level IN (?)", "WITH RECURSIVE descendant", "gadm_id LIKE ?", "area_km2 >= ?", "ST_Contains(geometry, ST_GeomFromText(?))", "MBRIntersects(geometry, ST_GeomFromText(?))", "ST_Intersects(geometry, ST_GeomFromText(?))"},
+		},
+		{
+			name:      "postgres",
+			dialector: postgres.New(postgres.Config{DSN: "host=localhost user=user password=pass dbname=db"}),
+			wantSQL:   []string{"name ILIKE $1", "level IN ($2)", "WHERE id = $3", "gadm_id LIKE $4", "area_km2 >= $5", "ST_Contains(geometry, ST_GeomFromText($6, 4326))", "geometry && ST_GeomFromText($7, 4326)", "ST_Intersects(geometry, ST_GeomFromText($8, 4326))"},
+		},
+		{
+			name:      "sqlite",
+			dialector: sqlite.Open(":memory:"),
+			wantSQL:   []string{"name LIKE ?", "SELECT id FROM geospatial_rtree WHERE min_lng <= ?", "ST_Contains(geometry, ?)", "max_lng >= ?", "ST_Intersects(geometry, ?)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			repo := repository.NewGeospatialRepository(dryRunDb(t, tc.dialector)).(filteredDbRepository)
+
+			var geospatials []model.Geospatial
+			stmt := repo.FilteredDb(filter).Find(&geospatials).Statement
+			sql := stmt.SQL.String()
+
+			for _, want := range tc.wantSQL {
+				if !strings.Contains(sql, want) {
+					t.Errorf("expected %q in %q", want, sql)
+				}
+			}
+		})
+	}
+}
+
+func TestGeospatialUpsertBulkDialects(t *testing.T) {
+	geospatials := []model.Geospatial{
+		{GadmID: "IDN", Name: "Indonesia", Type: "Country", Level: 1, Geometry: "MULTIPOLYGON(((0 0,1 0,1 1,0 0)))"},
+	}
+
+	testCases := []struct {
+		name      string
+		dialector gorm.Dialector
+		wantSQL   []string
+	}{
+		{
+			name:      "mysql",
+			dialector: mysql.New(mysql.Config{Conn: dryRunConnPool{}, SkipInitializeWithVersion: true}),
+			wantSQL:   []string{"ST_GeomFromText(?)", "ON DUPLICATE KEY UPDATE gadm_id=VALUES(gadm_id)", "area_km2=VALUES(area_km2)"},
+		},
+		{
+			name:      "postgres",
+			dialector: postgres.New(postgres.Config{Conn: dryRunConnPool{}}),
+			wantSQL:   []string{"ST_Multi(ST_GeomFromText($8, 4326))", "ON CONFLICT (gadm_id) DO UPDATE SET", "area_km2=EXCLUDED.area_km2", "updated_at=CURRENT_TIMESTAMP"},
+		},
+		{
+			name:      "sqlite",
+			dialector: sqlite.Open(":memory:"),
+			wantSQL:   []string{"ST_GeomFromText(?)", "ON CONFLICT (gadm_id) DO UPDATE SET", "area_km2=EXCLUDED.area_km2", "updated_at=CURRENT_TIMESTAMP"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db := dryRunDb(t, tc.dialector)
+
+			var statements []string
+			db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+				statements = append(statements, tx.Statement.SQL.String())
+			})
+
+			err := repository.NewGeospatialRepository(db).UpsertBulk(context.TODO(), geospatials)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			sql := strings.Join(statements, "; ")
+			for _, want := range tc.wantSQL {
+				if !strings.Contains(sql, want) {
+					t.Errorf("expected %q in %q", want, sql)
+				}
+			}
+		})
+	}
+}
+
+func TestGeospatialFilteredDbColumns(t *testing.T) {
+	repo := repository.NewGeospatialRepository(dryRunDb(t, sqlite.Open(":memory:"))).(filteredDbRepository)
+
+	testCases := []struct {
+		name    string
+		filter  model.GeospatialFilter
+		want    string
+		notWant string
+	}{
+		{
+			name:    "every column but the geometry",
+			filter:  model.GeospatialFilter{},
+			want:    "SELECT id, gadm_id, parent_gadm_id, name, type, eng_type, level, centroid_lat",
+			notWant: "geometry",
+		},
+		{
+			name:    "picked columns",
+			filter:  model.GeospatialFilter{IDs: []uint{3}, Columns: []string{"id", "name"}},
+			want:    "SELECT id, name FROM `geospatial` WHERE id IN (?)",
+			notWant: "level",
+		},
+		{
+			name:   "with geometry",
+			filter: model.GeospatialFilter{Columns: []string{"id"}, WithGeometry: true},
+			want:   "SELECT id, ST_AsText(geometry) AS geometry FROM",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var geospatials []model.Geospatial
+			sql := repo.FilteredDb(tc.filter).Find(&geospatials).Statement.SQL.String()
+
+			if !strings.Contains(sql, tc.want) {
+				t.Errorf("expected %q in %q", tc.want, sql)
+			}
+			if tc.notWant != "" && strings.Contains(sql, tc.notWant) {
+				t.Errorf("unexpected %q in %q", tc.notWant, sql)
+			}
+		})
+	}
+}
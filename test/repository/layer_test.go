@@ -0,0 +1,205 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func layerFeature(layerID uint, featureID, name, geometry string, bbox [4]float64) model.LayerFeature {
+	f := model.LayerFeature{
+		LayerID:    layerID,
+		Name:       name,
+		Properties: `{"name":"` + name + `"}`,
+		Geometry:   geometry,
+		BboxMinLng: &bbox[0],
+		BboxMinLat: &bbox[1],
+		BboxMaxLng: &bbox[2],
+		BboxMaxLat: &bbox[3],
+	}
+	if featureID != "" {
+		f.FeatureID = &featureID
+	}
+	return f
+}
+
+func featureNames(features []model.LayerFeature) []string {
+	var names []string
+	for _, f := range features {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestLayers(t *testing.T) {
+	repos := map[string]repository.LayerRepository{
+		"memory": repository.NewLayerMemoryRepository(),
+		"sqlite": repository.NewLayerRepository(openSqlite(t)),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			zones, err := repo.UpsertLayer(ctx, "delivery-zones")
+			assert.Equal(t, err, nil)
+			floods, err := repo.UpsertLayer(ctx, "flood-areas")
+			assert.Equal(t, err, nil)
+			again, err := repo.UpsertLayer(ctx, "delivery-zones")
+			assert.Equal(t, err, nil)
+			assert.Equal(t, again.ID, zones.ID)
+
+			err = repo.UpsertFeatures(ctx, []model.LayerFeature{
+				layerFeature(zones.ID, "north", "North", "MULTIPOLYGON (((106 -6.5, 107 -6.5, 107 -6, 106 -6, 106 -6.5)))", [4]float64{106, -6.5, 107, -6}),
+				layerFeature(zones.ID, "south", "South", "MULTIPOLYGON (((106 -7, 107 -7, 107 -6.5, 106 -6.5, 106 -7)))", [4]float64{106, -7, 107, -6.5}),
+				layerFeature(floods.ID, "", "River", "MULTIPOLYGON (((106.4 -7, 106.6 -7, 106.6 -6, 106.4 -6, 106.4 -7)))", [4]float64{106.4, -7, 106.6, -6}),
+			})
+			assert.Equal(t, err, nil)
+
+			testCases := []struct {
+				name   string
+				filter model.LayerFeatureFilter
+				want   []string
+			}{
+				{
+					name:   "every feature of a layer",
+					filter: model.LayerFeatureFilter{LayerID: zones.ID},
+					want:   []string{"North", "South"},
+				},
+				{
+					name:   "point",
+					filter: model.LayerFeatureFilter{LayerID: zones.ID, Point: &model.LatLng{Lat: -6.8, Lng: 106.5}},
+					want:   []string{"South"},
+				},
+				{
+					name:   "point of another layer",
+					filter: model.LayerFeatureFilter{LayerID: floods.ID, Point: &model.LatLng{Lat: -6.8, Lng: 106.8}},
+					want:   nil,
+				},
+				{
+					name:   "bbox",
+					filter: model.LayerFeatureFilter{LayerID: zones.ID, Bbox: []float64{106.2, -6.4, 106.3, -6.2}},
+					want:   []string{"North"},
+				},
+				{
+					name:   "intersects",
+					filter: model.LayerFeatureFilter{LayerID: floods.ID, Intersects: "LINESTRING (106 -6.2, 107 -6.2)"},
+					want:   []string{"River"},
+				},
+				{
+					name:   "intersects apart",
+					filter: model.LayerFeatureFilter{LayerID: zones.ID, Intersects: "LINESTRING (110 -6.2, 111 -6.2)"},
+					want:   nil,
+				},
+			}
+
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					features, _, err := repo.GetFeaturesPaginate(ctx, tc.filter, pagination.Param{Limit: 10})
+					assert.Equal(t, err, nil)
+					assert.Equal(t, featureNames(features), tc.want)
+				})
+			}
+
+			// A feature with a known id is replaced, one without is added.
+			err = repo.UpsertFeatures(ctx, []model.LayerFeature{
+				layerFeature(zones.ID, "north", "North East", "MULTIPOLYGON (((106.5 -6.5, 107 -6.5, 107 -6, 106.5 -6, 106.5 -6.5)))", [4]float64{106.5, -6.5, 107, -6}),
+				layerFeature(floods.ID, "", "River", "MULTIPOLYGON (((106.4 -7, 106.6 -7, 106.6 -6, 106.4 -6, 106.4 -7)))", [4]float64{106.4, -7, 106.6, -6}),
+			})
+			assert.Equal(t, err, nil)
+
+			features, meta, err := repo.GetFeaturesPaginate(ctx, model.LayerFeatureFilter{LayerID: zones.ID, Point: &model.LatLng{Lat: -6.2, Lng: 106.2}, WithGeometry: true}, pagination.Param{Limit: 10})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(features), 0)
+			assert.Equal(t, meta.TotalRows, int64(0))
+
+			features, _, err = repo.GetFeaturesPaginate(ctx, model.LayerFeatureFilter{LayerID: zones.ID, Point: &model.LatLng{Lat: -6.2, Lng: 106.8}, WithGeometry: true}, pagination.Param{Limit: 10})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, featureNames(features), []string{"North East"})
+			assert.Equal(t, features[0].Properties, `{"name":"North East"}`)
+			assert.Equal(t, strings.HasPrefix(features[0].Geometry, "MULTIPOLYGON"), true)
+
+			layers, err := repo.GetLayers(ctx, nil)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(layers), 2)
+			assert.Equal(t, layers[0].Name, "delivery-zones")
+			assert.Equal(t, layers[0].FeatureCount, int64(2))
+			assert.Equal(t, layers[1].Name, "flood-areas")
+			assert.Equal(t, layers[1].FeatureCount, int64(2))
+
+			assert.Equal(t, repo.DeleteLayer(ctx, zones.ID), nil)
+			layers, err = repo.GetLayers(ctx, []string{"delivery-zones"})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(layers), 0)
+
+			features, _, err = repo.GetFeaturesPaginate(ctx, model.LayerFeatureFilter{LayerID: zones.ID}, pagination.Param{Limit: 10})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, len(features), 0)
+		})
+	}
+}
+
+func TestLayerFeatureDialects(t *testing.T) {
+	features := []model.LayerFeature{
+		layerFeature(1, "north", "North", "MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)))", [4]float64{0, 0, 1, 1}),
+	}
+
+	testCases := []struct {
+		name      string
+		dialector gorm.Dialector
+		wantSQL   []string
+	}{
+		{
+			name:      "mysql",
+			dialector: mysql.New(mysql.Config{Conn: dryRunConnPool{}, SkipInitializeWithVersion: true}),
+			wantSQL:   []string{"ON DUPLICATE KEY UPDATE layer_id=VALUES(layer_id), feature_id=VALUES(feature_id), name=VALUES(name)", "MBRIntersects(geometry"},
+		},
+		{
+			name:      "postgres",
+			dialector: postgres.New(postgres.Config{Conn: dryRunConnPool{}}),
+			wantSQL:   []string{"ST_Multi(ST_GeomFromText($5, 4326))", "ON CONFLICT (layer_id, feature_id) DO UPDATE SET", "geometry && ST_GeomFromText("},
+		},
+		{
+			name:      "sqlite",
+			dialector: sqlite.Open(":memory:"),
+			wantSQL:   []string{"ON CONFLICT (layer_id, feature_id) DO UPDATE SET", "SELECT id FROM layer_feature_rtree WHERE"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db := dryRunDb(t, tc.dialector)
+
+			var statements []string
+			db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+				statements = append(statements, tx.Statement.SQL.String())
+			})
+			db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+				statements = append(statements, tx.Statement.SQL.String())
+			})
+
+			repo := repository.NewLayerRepository(db)
+			if err := repo.UpsertFeatures(context.TODO(), features); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, _, _ = repo.GetFeaturesPaginate(context.TODO(), model.LayerFeatureFilter{LayerID: 1, Bbox: []float64{0, 0, 1, 1}}, pagination.Param{Limit: 10, SkipTotal: true})
+
+			sql := strings.Join(statements, "; ")
+			for _, want := range tc.wantSQL {
+				if !strings.Contains(sql, want) {
+					t.Errorf("expected %q in %q", want, sql)
+				}
+			}
+		})
+	}
+}
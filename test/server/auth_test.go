@@ -0,0 +1,225 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/server"
+	"github.com/si-bas/go-rest-geospatial/service"
+)
+
+const testJWTSecret = "test-secret"
+
+func newAuthRouter(t *testing.T, protectReads bool) (*gin.Engine, service.AuthService) {
+	config.Config = &config.Cfg{Data: config.Data{MaxRows: 10}, Auth: config.Auth{JWTSecret: testJWTSecret, ProtectReads: protectReads}}
+
+	eventRepo := repository.NewEventMemoryRepository()
+	geospatialRepo, err := repository.NewGeospatialMemoryRepository("", eventRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authService := service.NewAuthService(repository.NewAPIKeyMemoryRepository(), testJWTSecret, "")
+
+	gin.SetMode(gin.TestMode)
+	return server.NewRouter(server.Services{
+		Geospatial: service.NewGeospatialService(geospatialRepo, eventRepo),
+		Auth:       authService,
+	}), authService
+}
+
+func createKey(t *testing.T, authService service.AuthService, role string) string {
+	key, err := authService.CreateKey(context.TODO(), role+"-client", role, model.RateLimit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key.Key
+}
+
+func signJWT(t *testing.T, secret string, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func serveAuth(router *gin.Engine, method, path, authorization, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorMessage(t *testing.T, rec *httptest.ResponseRecorder) string {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Error
+}
+
+func TestAuthRoles(t *testing.T) {
+	router, authService := newAuthRouter(t, false)
+	reader := createKey(t, authService, "reader")
+	importer := createKey(t, authService, "importer")
+	admin := createKey(t, authService, "admin")
+
+	testCases := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		want          int
+	}{
+		{"anonymous import", http.MethodPost, "/v1/import", "", http.StatusUnauthorized},
+		{"reader import", http.MethodPost, "/v1/import", "Bearer " + reader, http.StatusForbidden},
+		// Past the role check the import is rejected for not having a file.
+		{"importer import", http.MethodPost, "/v1/import", "Bearer " + importer, http.StatusBadRequest},
+		{"admin import", http.MethodPost, "/v1/import", "Bearer " + admin, http.StatusBadRequest},
+		{"importer keys", http.MethodGet, "/v1/keys", "Bearer " + importer, http.StatusForbidden},
+		{"admin keys", http.MethodGet, "/v1/keys", "Bearer " + admin, http.StatusOK},
+		{"unknown key", http.MethodGet, "/v1/keys", "Bearer gsk_unknown", http.StatusUnauthorized},
+		{"not a bearer token", http.MethodGet, "/v1/keys", "Basic " + admin, http.StatusUnauthorized},
+		{"empty bearer token", http.MethodGet, "/v1/keys", "Bearer ", http.StatusUnauthorized},
+		{"anonymous read", http.MethodGet, "/v1/types", "", http.StatusOK},
+		{"anonymous export", http.MethodPost, "/v1/exports?format=gpkg", "", http.StatusUnauthorized},
+		// Past the role check the export is rejected for its format.
+		{"reader export", http.MethodPost, "/v1/exports?format=csv", "Bearer " + reader, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveAuth(router, tc.method, tc.path, tc.authorization, "")
+			assert.Equal(t, rec.Code, tc.want)
+			if tc.want == http.StatusUnauthorized {
+				assert.Equal(t, rec.Header().Get("WWW-Authenticate"), `Bearer realm="geospatial"`)
+				assert.NotEqual(t, errorMessage(t, rec), "")
+			}
+		})
+	}
+}
+
+func TestAuthKeys(t *testing.T) {
+	router, authService := newAuthRouter(t, false)
+	admin := createKey(t, authService, "admin")
+
+	rec := serveAuth(router, http.MethodPost, "/v1/keys", "Bearer "+admin, `{"name":"ci","role":"importer"}`)
+	assert.Equal(t, rec.Code, http.StatusCreated)
+	var created struct {
+		Data struct {
+			ID   uint   `json:"id"`
+			Key  string `json:"key"`
+			Role string `json:"role"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, created.Data.Role, "importer")
+	assert.Equal(t, strings.HasPrefix(created.Data.Key, "gsk_"), true)
+
+	rec = serveAuth(router, http.MethodPost, "/v1/import", "Bearer "+created.Data.Key, "")
+	assert.Equal(t, rec.Code, http.StatusBadRequest)
+
+	// The key is only shown when it is created.
+	rec = serveAuth(router, http.MethodGet, "/v1/keys", "Bearer "+admin, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, strings.Contains(rec.Body.String(), created.Data.Key), false)
+
+	rec = serveAuth(router, http.MethodPost, "/v1/keys", "Bearer "+admin, `{"name":"ci","role":"root"}`)
+	assert.Equal(t, rec.Code, http.StatusBadRequest)
+	rec = serveAuth(router, http.MethodPost, "/v1/keys", "Bearer "+admin, `{"name":" ","role":"reader"}`)
+	assert.Equal(t, rec.Code, http.StatusBadRequest)
+
+	rec = serveAuth(router, http.MethodDelete, "/v1/keys/999", "Bearer "+admin, "")
+	assert.Equal(t, rec.Code, http.StatusNotFound)
+
+	rec = serveAuth(router, http.MethodDelete, "/v1/keys/"+strconv.FormatUint(uint64(created.Data.ID), 10), "Bearer "+admin, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	// A revoked key is rejected.
+	rec = serveAuth(router, http.MethodPost, "/v1/import", "Bearer "+created.Data.Key, "")
+	assert.Equal(t, rec.Code, http.StatusUnauthorized)
+}
+
+func TestAuthJWT(t *testing.T) {
+	router, _ := newAuthRouter(t, false)
+	exp := time.Now().Add(time.Hour).Unix()
+
+	testCases := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"importer", signJWT(t, testJWTSecret, jwt.MapClaims{"sub": "user-1", "role": "importer", "exp": exp}), http.StatusBadRequest},
+		{"reader", signJWT(t, testJWTSecret, jwt.MapClaims{"sub": "user-1", "role": "reader", "exp": exp}), http.StatusForbidden},
+		{"expired", signJWT(t, testJWTSecret, jwt.MapClaims{"sub": "user-1", "role": "importer", "exp": time.Now().Add(-time.Minute).Unix()}), http.StatusUnauthorized},
+		{"without expiry", signJWT(t, testJWTSecret, jwt.MapClaims{"sub": "user-1", "role": "importer"}), http.StatusUnauthorized},
+		{"without subject", signJWT(t, testJWTSecret, jwt.MapClaims{"role": "importer", "exp": exp}), http.StatusUnauthorized},
+		{"unknown role", signJWT(t, testJWTSecret, jwt.MapClaims{"sub": "user-1", "role": "root", "exp": exp}), http.StatusUnauthorized},
+		{"other secret", signJWT(t, "other-secret", jwt.MapClaims{"sub": "user-1", "role": "importer", "exp": exp}), http.StatusUnauthorized},
+		{"unsigned", unsignedJWT(t, jwt.MapClaims{"sub": "user-1", "role": "importer", "exp": exp}), http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serveAuth(router, http.MethodPost, "/v1/import", "Bearer "+tc.token, "")
+			assert.Equal(t, rec.Code, tc.want)
+		})
+	}
+}
+
+func unsignedJWT(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthProtectReads(t *testing.T) {
+	router, authService := newAuthRouter(t, true)
+	reader := createKey(t, authService, "reader")
+
+	rec := serveAuth(router, http.MethodGet, "/v1/types", "", "")
+	assert.Equal(t, rec.Code, http.StatusUnauthorized)
+
+	rec = serveAuth(router, http.MethodGet, "/v1/types", "Bearer "+reader, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	// The docs stay public.
+	rec = serveAuth(router, http.MethodGet, "/openapi.json", "", "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+}
+
+func TestAuthUnionSaveAs(t *testing.T) {
+	router, authService := newAuthRouter(t, false)
+	reader := createKey(t, authService, "reader")
+
+	rec := serveAuth(router, http.MethodPost, "/v1/geometry/union", "Bearer "+reader, `{"ids":[1],"saveAs":"Greater Area"}`)
+	assert.Equal(t, rec.Code, http.StatusForbidden)
+	assert.Equal(t, errorMessage(t, rec), "the importer role is required to save a union")
+
+	rec = serveAuth(router, http.MethodPost, "/v1/geometry/union", "", `{"ids":[1],"saveAs":"Greater Area"}`)
+	assert.Equal(t, rec.Code, http.StatusForbidden)
+}
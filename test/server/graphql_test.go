@@ -0,0 +1,245 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/server/gql"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/twpayne/go-geom/encoding/geojson"
+)
+
+// countingRepository counts the reads of the regions.
+type countingRepository struct {
+	repository.GeospatialRepository
+
+	mu    sync.Mutex
+	reads int
+}
+
+func (r *countingRepository) count() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reads++
+}
+
+func (r *countingRepository) Get(ctx context.Context, filter model.GeospatialFilter) ([]model.Geospatial, error) {
+	r.count()
+	return r.GeospatialRepository.Get(ctx, filter)
+}
+
+func (r *countingRepository) GetByGadmIds(ctx context.Context, gadmIds []string) ([]model.Geospatial, error) {
+	r.count()
+	return r.GeospatialRepository.GetByGadmIds(ctx, gadmIds)
+}
+
+func (r *countingRepository) GetWithGeometry(ctx context.Context, filter model.GeospatialFilter) ([]model.Geospatial, error) {
+	r.count()
+	return r.GeospatialRepository.GetWithGeometry(ctx, filter)
+}
+
+const hierarchyGeoJSON = `{"type":"FeatureCollection","features":[
+{"type":"Feature","properties":{"GID_0":"IDN","COUNTRY":"Indonesia"},"geometry":{"type":"MultiPolygon","coordinates":[[[[106,-8],[112,-8],[112,-6],[106,-6],[106,-8]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.9_1","COUNTRY":"Indonesia","NAME_1":"Jawa Barat","TYPE_1":"Propinsi","ENGTYPE_1":"Province"},"geometry":{"type":"MultiPolygon","coordinates":[[[[106,-8],[108,-8],[108,-6],[106,-6],[106,-8]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.34_1","COUNTRY":"Indonesia","NAME_1":"Yogyakarta","TYPE_1":"Daerah Istimewa","ENGTYPE_1":"Special Region"},"geometry":{"type":"MultiPolygon","coordinates":[[[[110,-8],[111,-8],[111,-7.5],[110,-7.5],[110,-8]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.9_1","GID_2":"IDN.9.1_1","COUNTRY":"Indonesia","NAME_1":"Jawa Barat","NAME_2":"Bandung","TYPE_2":"Kabupaten","ENGTYPE_2":"Regency"},"geometry":{"type":"MultiPolygon","coordinates":[[[[107,-7.5],[108,-7.5],[108,-7],[107,-7],[107,-7.5]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.9_1","GID_2":"IDN.9.2_1","COUNTRY":"Indonesia","NAME_1":"Jawa Barat","NAME_2":"Bogor","TYPE_2":"Kabupaten","ENGTYPE_2":"Regency"},"geometry":{"type":"MultiPolygon","coordinates":[[[[106,-7],[107,-7],[107,-6],[106,-6],[106,-7]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.34_1","GID_2":"IDN.34.1_1","COUNTRY":"Indonesia","NAME_1":"Yogyakarta","NAME_2":"Sleman","TYPE_2":"Kabupaten","ENGTYPE_2":"Regency"},"geometry":{"type":"MultiPolygon","coordinates":[[[[110,-7.8],[110.5,-7.8],[110.5,-7.5],[110,-7.5],[110,-7.8]]]]}}]}`
+
+func newGraphQLRouter(t *testing.T) (*gin.Engine, *countingRepository) {
+	config.Config = &config.Cfg{Data: config.Data{MaxRows: 10}}
+
+	eventRepo := repository.NewEventMemoryRepository()
+	memoryRepo, err := repository.NewGeospatialMemoryRepository("", eventRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &countingRepository{GeospatialRepository: memoryRepo}
+	geospatialService := service.NewGeospatialService(repo, eventRepo)
+
+	var features geojson.FeatureCollection
+	json.Unmarshal([]byte(hierarchyGeoJSON), &features)
+	if err := geospatialService.CreateFromFeatureCollection(context.TODO(), &features); err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/graphql", gql.Handler(geospatialService))
+	router.GET("/graphql", gql.Handler(geospatialService))
+	return router, repo
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, router *gin.Engine, query string, variables ...map[string]interface{}) (int, graphQLResponse) {
+	request := map[string]interface{}{"query": query}
+	if len(variables) > 0 {
+		request["variables"] = variables[0]
+	}
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var res graphQLResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, res
+}
+
+func TestGraphQLHierarchy(t *testing.T) {
+	router, repo := newGraphQLRouter(t)
+
+	code, res := postGraphQL(t, router, `{ regions(levels: [1]) { id } }`)
+	assert.Equal(t, code, http.StatusOK)
+	var countries struct {
+		Regions []struct{ ID string }
+	}
+	json.Unmarshal(res.Data, &countries)
+	assert.Equal(t, len(countries.Regions), 1)
+
+	repo.reads = 0
+	code, res = postGraphQL(t, router, `{ region(id: "`+countries.Regions[0].ID+`") {
+		name
+		children {
+			name
+			children { name level parent { name } ancestors { name } geometry }
+		}
+	} }`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, len(res.Errors), 0)
+
+	var data struct {
+		Region struct {
+			Name     string
+			Children []struct {
+				Name     string
+				Children []struct {
+					Name      string
+					Level     int
+					Parent    struct{ Name string }
+					Ancestors []struct{ Name string }
+					Geometry  struct{ Type string }
+				}
+			}
+		}
+	}
+	json.Unmarshal(res.Data, &data)
+	assert.Equal(t, data.Region.Name, "Indonesia")
+	assert.Equal(t, len(data.Region.Children), 2)
+	assert.Equal(t, data.Region.Children[0].Name, "Jawa Barat")
+	assert.Equal(t, len(data.Region.Children[0].Children), 2)
+	assert.Equal(t, data.Region.Children[1].Name, "Yogyakarta")
+	sleman := data.Region.Children[1].Children[0]
+	assert.Equal(t, sleman.Name, "Sleman")
+	assert.Equal(t, sleman.Level, 3)
+	assert.Equal(t, sleman.Parent.Name, "Yogyakarta")
+	assert.Equal(t, len(sleman.Ancestors), 2)
+	assert.Equal(t, sleman.Ancestors[0].Name, "Indonesia")
+	assert.Equal(t, sleman.Geometry.Type, "MultiPolygon")
+
+	// The region, the children of each level, two levels of ancestors by GADM id, the
+	// ancestors in full and the geometries: one read per level, not one per region.
+	assert.Equal(t, repo.reads, 7)
+}
+
+func TestGraphQLChildrenPage(t *testing.T) {
+	router, repo := newGraphQLRouter(t)
+
+	repo.reads = 0
+	code, res := postGraphQL(t, router, `{ regions(levels: [1]) {
+		first: children(limit: 1) { name children(limit: 1, page: 2) { name geometry } }
+		second: children(limit: 1, page: 2) { name }
+		past: children(page: 2) { name }
+	} }`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, len(res.Errors), 0)
+
+	var data struct {
+		Regions []struct {
+			First []struct {
+				Name     string
+				Children []struct {
+					Name     string
+					Geometry struct{ Type string }
+				}
+			}
+			Second []struct{ Name string }
+			Past   []struct{ Name string }
+		}
+	}
+	json.Unmarshal(res.Data, &data)
+	country := data.Regions[0]
+	assert.Equal(t, len(country.First), 1)
+	assert.Equal(t, country.First[0].Name, "Jawa Barat")
+	assert.Equal(t, len(country.First[0].Children), 1)
+	assert.Equal(t, country.First[0].Children[0].Name, "Bogor")
+	assert.Equal(t, country.First[0].Children[0].Geometry.Type, "MultiPolygon")
+	assert.Equal(t, len(country.Second), 1)
+	assert.Equal(t, country.Second[0].Name, "Yogyakarta")
+	assert.Equal(t, len(country.Past), 0)
+
+	// The children of each level and the geometries of the paged regions: the pages share
+	// the children loaded once per level.
+	assert.Equal(t, repo.reads, 3)
+
+	_, res = postGraphQL(t, router, `{ regions(levels: [1]) { children(limit: 1001) { name } } }`)
+	assert.Equal(t, res.Errors[0].Message, "limit must be within 1 and 1000")
+
+	_, res = postGraphQL(t, router, `{ regions(levels: [1]) { children(page: 0) { name } } }`)
+	assert.Equal(t, res.Errors[0].Message, "page must be positive")
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	router, _ := newGraphQLRouter(t)
+
+	code, res := postGraphQL(t, router, `{ region(id: "999") { name } }`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, string(res.Data), `{"region":null}`)
+
+	_, res = postGraphQL(t, router, `{ region(id: "x") { name } }`)
+	assert.Equal(t, res.Errors[0].Message, "id must be an integer")
+
+	// A point on the equator is a point like any other.
+	_, res = postGraphQL(t, router, `query($lat: Float!) { reverse(lat: $lat, lng: 110.0) { name } }`, map[string]interface{}{"lat": 0})
+	assert.Equal(t, len(res.Errors), 0)
+	assert.Equal(t, string(res.Data), `{"reverse":[]}`)
+
+	_, res = postGraphQL(t, router, `query($lng: Float!) { reverse(lat: 7.5, lng: $lng) { name } }`, map[string]interface{}{"lng": 0})
+	assert.Equal(t, len(res.Errors), 0)
+	assert.Equal(t, string(res.Data), `{"reverse":[]}`)
+
+	_, res = postGraphQL(t, router, `{ region(id: "1") { unknown } }`)
+	assert.Equal(t, len(res.Errors), 1)
+
+	code, _ = postGraphQL(t, router, ``)
+	assert.Equal(t, code, http.StatusBadRequest)
+
+	code, res = postGraphQL(t, router, `{ region(id: "1") { name } }`+strings.Repeat(" ", 10000))
+	assert.Equal(t, code, http.StatusBadRequest)
+	assert.Equal(t, res.Errors[0].Message, "graphql: query must be at most 10000 bytes")
+
+	req := httptest.NewRequest(http.MethodGet, `/graphql?query=query($lat:Float!){reverse(lat:$lat,lng:110.2){name}}&variables={"lat":-7.6}`, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Body.String(), `{"data":{"reverse":[{"name":"Indonesia"},{"name":"Yogyakarta"},{"name":"Sleman"}]}}`)
+}
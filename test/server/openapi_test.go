@@ -0,0 +1,133 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/server"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/response"
+)
+
+type openAPIDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas struct {
+			ErrorCode struct {
+				Enum []string `json:"enum"`
+			} `json:"ErrorCode"`
+		} `json:"schemas"`
+		Responses map[string]struct {
+			ErrorCode string `json:"x-error-code"`
+		} `json:"responses"`
+	} `json:"components"`
+}
+
+// undocumentedRoutes serve the docs UI, not the API.
+var undocumentedRoutes = map[string]bool{"GET /docs/*filepath": true}
+
+var ginParam = regexp.MustCompile(`:([A-Za-z]+)`)
+
+func getOpenAPI(t *testing.T) (*httptest.ResponseRecorder, openAPIDocument) {
+	config.Config = &config.Cfg{}
+	router := server.NewRouter(server.Services{})
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var doc openAPIDocument
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return rec, doc
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	rec, doc := getOpenAPI(t)
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, doc.OpenAPI, "3.0.3")
+
+	router := server.NewRouter(server.Services{})
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		key := route.Method + " " + route.Path
+		if undocumentedRoutes[key] {
+			continue
+		}
+
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		registered[route.Method+" "+path] = true
+		if _, ok := doc.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s is registered but missing from openapi.json", key)
+		}
+	}
+
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			if key := strings.ToUpper(method) + " " + path; !registered[key] {
+				t.Errorf("%s is in openapi.json but not registered", key)
+			}
+		}
+	}
+}
+
+func TestOpenAPIErrorCodes(t *testing.T) {
+	_, doc := getOpenAPI(t)
+
+	codes := make(map[string]bool)
+	for _, code := range doc.Components.Schemas.ErrorCode.Enum {
+		codes[code] = true
+	}
+	for _, err := range []error{
+		response.ErrBadRequest,
+		response.ErrForbiddenResource,
+		response.ErrNotFound,
+		response.ErrPreConditionFailed,
+		response.ErrInternalServerError,
+		response.ErrTimeoutError,
+		response.ErrUnauthorized,
+		response.ErrConflict,
+		response.ErrDependencyFailed,
+		response.ErrTooManyRequests,
+	} {
+		if code := response.GetErrorCode(err); !codes[code] {
+			t.Errorf("error code %s of %q is missing from openapi.json", code, err)
+		}
+	}
+
+	for name, r := range doc.Components.Responses {
+		if !codes[r.ErrorCode] {
+			t.Errorf("response %s has the unknown error code %q", name, r.ErrorCode)
+		}
+	}
+}
+
+func TestOpenAPIDocs(t *testing.T) {
+	config.Config = &config.Cfg{}
+	router := server.NewRouter(server.Services{})
+
+	for _, tc := range []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/docs/", "text/html", `url: "/openapi.json"`},
+		{"/docs/swagger-ui-bundle.js", "javascript", "SwaggerUIBundle"},
+		{"/docs/swagger-ui.css", "text/css", ".swagger-ui"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, strings.Contains(rec.Header().Get("Content-Type"), tc.contentType), true)
+		assert.Equal(t, strings.Contains(rec.Body.String(), tc.contains), true)
+	}
+}
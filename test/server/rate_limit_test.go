@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/ratelimit"
+	"github.com/si-bas/go-rest-geospatial/server"
+	"github.com/si-bas/go-rest-geospatial/service"
+)
+
+func newRateLimitRouter(t *testing.T, cfg config.RateLimit) (*gin.Engine, service.AuthService) {
+	return newRateLimitRouterWithKeys(t, cfg, repository.NewAPIKeyMemoryRepository())
+}
+
+func newRateLimitRouterWithKeys(t *testing.T, cfg config.RateLimit, apiKeyRepo repository.APIKeyRepository) (*gin.Engine, service.AuthService) {
+	config.Config = &config.Cfg{Data: config.Data{MaxRows: 10}, RateLimit: cfg}
+
+	eventRepo := repository.NewEventMemoryRepository()
+	geospatialRepo, err := repository.NewGeospatialMemoryRepository("", eventRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authService := service.NewAuthService(apiKeyRepo, testJWTSecret, "")
+	limit := ratelimit.Limit{Rate: cfg.Rate, Burst: cfg.Burst, DailyQuota: cfg.DailyQuota}
+
+	gin.SetMode(gin.TestMode)
+	return server.NewRouter(server.Services{
+		Geospatial: service.NewGeospatialService(geospatialRepo, eventRepo),
+		Auth:       authService,
+		RateLimit:  service.NewRateLimitService(limit, cfg.Costs),
+	}), authService
+}
+
+func serveFrom(router *gin.Engine, method, path, remoteAddr string, header http.Header, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	router, _ := newRateLimitRouter(t, config.RateLimit{Rate: 0.001, Burst: 3})
+
+	for _, want := range []string{"2", "1", "0"} {
+		rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", nil, "")
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, rec.Header().Get("RateLimit-Limit"), "3")
+		assert.Equal(t, rec.Header().Get("RateLimit-Remaining"), want)
+		assert.Equal(t, rec.Header().Get("RateLimit-Policy"), "3;w=3000")
+	}
+
+	rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", nil, "")
+	assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+	assert.Equal(t, rec.Header().Get("RateLimit-Remaining"), "0")
+	assert.Equal(t, rec.Header().Get("Retry-After"), "1000")
+	assert.Equal(t, strings.HasPrefix(errorMessage(t, rec), "rate limit exceeded"), true)
+	assert.Equal(t, strings.Contains(rec.Body.String(), `"status":false`), true)
+	assert.Equal(t, strings.Contains(rec.Body.String(), `"message":"Too many requests"`), true)
+
+	// Another IP has its own bucket, a forwarded IP is not trusted without RateLimit.TrustedProxies.
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.2:1234", nil, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"10.0.0.3"}}, "")
+	assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+
+	// The health check and the docs are not limited.
+	rec = serveFrom(router, http.MethodGet, "/openapi.json", "10.0.0.1:1234", nil, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Header().Get("RateLimit-Limit"), "")
+}
+
+func TestRateLimitTrustedProxies(t *testing.T) {
+	router, _ := newRateLimitRouter(t, config.RateLimit{Rate: 0.001, Burst: 1, TrustedProxies: []string{"10.0.0.0/8"}})
+
+	for _, client := range []string{"192.0.2.1", "192.0.2.2"} {
+		rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {client}}, "")
+		assert.Equal(t, rec.Code, http.StatusOK)
+	}
+	rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"192.0.2.1"}}, "")
+	assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+}
+
+func TestRateLimitCosts(t *testing.T) {
+	router, _ := newRateLimitRouter(t, config.RateLimit{Rate: 0.001, Burst: 20, Costs: map[string]int{"get /v1/levels": 4}})
+
+	// A union costs 10 tokens, spent before its body is even read.
+	rec := serveFrom(router, http.MethodPost, "/v1/geometry/union", "10.0.0.1:1234", nil, `{}`)
+	assert.Equal(t, rec.Code, http.StatusBadRequest)
+	assert.Equal(t, rec.Header().Get("RateLimit-Remaining"), "10")
+
+	rec = serveFrom(router, http.MethodGet, "/v1/levels", "10.0.0.1:1234", nil, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Header().Get("RateLimit-Remaining"), "6")
+
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", nil, "")
+	assert.Equal(t, rec.Header().Get("RateLimit-Remaining"), "5")
+
+	rec = serveFrom(router, http.MethodPost, "/v1/geometry/union", "10.0.0.1:1234", nil, `{}`)
+	assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+}
+
+func TestRateLimitDailyQuota(t *testing.T) {
+	router, _ := newRateLimitRouter(t, config.RateLimit{DailyQuota: 2})
+
+	for _, want := range []string{"1", "0"} {
+		rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", nil, "")
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, rec.Header().Get("RateLimit-Limit"), "2")
+		assert.Equal(t, rec.Header().Get("RateLimit-Remaining"), want)
+		assert.Equal(t, rec.Header().Get("RateLimit-Policy"), "2;w=86400")
+	}
+
+	rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", nil, "")
+	assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+	assert.NotEqual(t, rec.Header().Get("Retry-After"), "")
+}
+
+func TestRateLimitPerKey(t *testing.T) {
+	// Without default limits only the keys with a limit of their own are limited.
+	router, authService := newRateLimitRouter(t, config.RateLimit{})
+	admin := createKey(t, authService, "admin")
+
+	one := 1
+	rate := 0.001
+	limited, err := authService.CreateKey(context.TODO(), "batch-job", "reader", model.RateLimit{Rate: &rate, Burst: &one})
+	assert.Equal(t, err, nil)
+
+	limitedHeader := http.Header{"Authorization": {"Bearer " + limited.Key}}
+	rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", limitedHeader, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Header().Get("RateLimit-Limit"), "1")
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", limitedHeader, "")
+	assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+
+	// The same IP without the key is not limited.
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", nil, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Header().Get("RateLimit-Limit"), "")
+
+	// A new limit applies to the next requests, a rate of 0 is unlimited.
+	adminHeader := http.Header{"Authorization": {"Bearer " + admin}}
+	rec = serveFrom(router, http.MethodPut, "/v1/keys/"+strconv.FormatUint(uint64(limited.ID), 10)+"/limit", "10.0.0.1:1234", adminHeader, `{"rate": 0}`)
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, strings.Contains(rec.Body.String(), `"limit":{"rate":0}`), true)
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", limitedHeader, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Header().Get("RateLimit-Limit"), "")
+
+	rec = serveFrom(router, http.MethodPut, "/v1/keys/"+strconv.FormatUint(uint64(limited.ID), 10)+"/limit", "10.0.0.1:1234", adminHeader, `{"burst": -1}`)
+	assert.Equal(t, rec.Code, http.StatusBadRequest)
+	rec = serveFrom(router, http.MethodPut, "/v1/keys/999/limit", "10.0.0.1:1234", adminHeader, `{}`)
+	assert.Equal(t, rec.Code, http.StatusNotFound)
+}
+
+// countingKeyRepository counts the lookups of API keys.
+type countingKeyRepository struct {
+	repository.APIKeyRepository
+
+	lookups int
+}
+
+func (r *countingKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	r.lookups++
+	return r.APIKeyRepository.GetAPIKeyByHash(ctx, hash)
+}
+
+func TestRateLimitInvalidCredentials(t *testing.T) {
+	keyRepo := &countingKeyRepository{APIKeyRepository: repository.NewAPIKeyMemoryRepository()}
+	router, authService := newRateLimitRouterWithKeys(t, config.RateLimit{Rate: 0.001, Burst: 2}, keyRepo)
+	bogus := http.Header{"Authorization": {"Bearer gsk_bogus"}}
+
+	for i := 0; i < 2; i++ {
+		rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", bogus, "")
+		assert.Equal(t, rec.Code, http.StatusUnauthorized)
+	}
+
+	// Once the IP has run out, its credentials are not looked up anymore.
+	rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", http.Header{"Authorization": {"Bearer gsk_other"}}, "")
+	assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+	assert.Equal(t, rec.Header().Get("Retry-After"), "1000")
+	assert.Equal(t, keyRepo.lookups, 2)
+
+	// Failed credentials do not spend the bucket of the anonymous requests, nor of other IPs.
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", nil, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Header().Get("RateLimit-Remaining"), "1")
+	key := createKey(t, authService, "reader")
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.2:1234", http.Header{"Authorization": {"Bearer " + key}}, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+}
+
+func TestRateLimitKeyAndJWTBuckets(t *testing.T) {
+	router, authService := newRateLimitRouter(t, config.RateLimit{Rate: 0.001, Burst: 1})
+	key, err := authService.CreateKey(context.TODO(), "batch-job", "reader", model.RateLimit{})
+	assert.Equal(t, err, nil)
+	id := strconv.FormatUint(uint64(key.ID), 10)
+
+	// JWTs whose subject looks like the key spend buckets of their own.
+	exp := time.Now().Add(time.Hour).Unix()
+	for _, sub := range []string{"key:" + id, "apikey:" + id} {
+		token := signJWT(t, testJWTSecret, jwt.MapClaims{"sub": sub, "role": "reader", "exp": exp})
+		rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", http.Header{"Authorization": {"Bearer " + token}}, "")
+		assert.Equal(t, rec.Code, http.StatusOK)
+	}
+
+	rec := serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", http.Header{"Authorization": {"Bearer " + key.Key}}, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+	rec = serveFrom(router, http.MethodGet, "/v1/types", "10.0.0.1:1234", http.Header{"Authorization": {"Bearer " + key.Key}}, "")
+	assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+}
@@ -0,0 +1,231 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/pkg/ratelimit"
+	"github.com/si-bas/go-rest-geospatial/server/rpc"
+	"github.com/si-bas/go-rest-geospatial/server/rpc/geospatialv1"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/twpayne/go-geom/encoding/geojson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newRPCClient serves a country and one of its provinces over an in-memory connection, to
+// callers with role when it is set and rate limited by rateLimitService when it is set.
+func newRPCClient(t *testing.T, authService service.AuthService, role string, rateLimitService service.RateLimitService) geospatialv1.GeospatialServiceClient {
+	config.Config = &config.Cfg{Data: config.Data{MaxRows: 10}}
+
+	eventRepo := repository.NewEventMemoryRepository()
+	geospatialRepo, err := repository.NewGeospatialMemoryRepository("", eventRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	geospatialService := service.NewGeospatialService(geospatialRepo, eventRepo)
+
+	var features geojson.FeatureCollection
+	json.Unmarshal([]byte(`{"type":"FeatureCollection","features":[
+{"type":"Feature","properties":{"GID_0":"IDN","COUNTRY":"Indonesia"},"geometry":{"type":"MultiPolygon","coordinates":[[[[106,-8],[112,-8],[112,-6],[106,-6],[106,-8]]]]}},
+{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.34_1","COUNTRY":"Indonesia","NAME_1":"Yogyakarta","TYPE_1":"Daerah Istimewa","ENGTYPE_1":"Special Region"},"geometry":{"type":"MultiPolygon","coordinates":[[[[110,-8],[111,-8],[111,-7.5],[110,-7.5],[110,-8]]]]}}]}`), &features)
+	if err := geospatialService.CreateFromFeatureCollection(context.TODO(), &features); err != nil {
+		t.Fatal(err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := rpc.NewServer(geospatialService, authService, role, rateLimitService)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return geospatialv1.NewGeospatialServiceClient(conn)
+}
+
+func receiveNames(t *testing.T, recv func() (*geospatialv1.Region, error)) []string {
+	var names []string
+	for {
+		region, err := recv()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, region.Name)
+	}
+}
+
+func TestGeospatialRPC(t *testing.T) {
+	ctx := context.TODO()
+	client := newRPCClient(t, service.NewAuthService(repository.NewAPIKeyMemoryRepository(), "", ""), "", nil)
+
+	list, err := client.List(ctx, &geospatialv1.ListRequest{Sort: "level", Limit: 1})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list.Regions), 1)
+	assert.Equal(t, list.Regions[0].Name, "Indonesia")
+	assert.Equal(t, list.Meta.TotalRows, int64(2))
+	country := list.Regions[0]
+
+	// A page holds at most 1000 regions, StreamList sends more.
+	list, err = client.List(ctx, &geospatialv1.ListRequest{Limit: 5000})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, list.Meta.Limit, uint32(1000))
+
+	cursor := ""
+	list, err = client.List(ctx, &geospatialv1.ListRequest{Levels: []uint32{2}, Cursor: &cursor})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list.Regions), 1)
+	assert.Equal(t, list.Regions[0].EngType, "REGION")
+
+	_, err = client.List(ctx, &geospatialv1.ListRequest{Sort: "distance"})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+
+	region, err := client.GetRegion(ctx, &geospatialv1.GetRegionRequest{Id: country.Id, WithGeometry: true})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, region.Name, "Indonesia")
+	assert.Equal(t, region.Bbox, []float64{106, -8, 112, -6})
+	assert.Equal(t, region.Geometry != "", true)
+	assert.Equal(t, region.CreatedAt != nil, true)
+
+	_, err = client.GetRegion(ctx, &geospatialv1.GetRegionRequest{Id: 999})
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	reverse, err := client.Reverse(ctx, &geospatialv1.ReverseRequest{Point: &geospatialv1.LatLng{Lat: -7.8, Lng: 110.4}, WithParent: true})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(reverse.Regions), 2)
+	assert.Equal(t, reverse.Regions[0].Name, "Indonesia")
+	assert.Equal(t, reverse.Regions[1].Name, "Yogyakarta")
+	assert.Equal(t, reverse.Regions[1].Parent.Id, country.Id)
+
+	_, err = client.Reverse(ctx, &geospatialv1.ReverseRequest{Point: &geospatialv1.LatLng{Lat: -97, Lng: 110}})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+
+	reverse, err = client.Reverse(ctx, &geospatialv1.ReverseRequest{Point: &geospatialv1.LatLng{Lat: -7, Lng: 0}})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(reverse.Regions), 0)
+
+	batch, err := client.BatchReverse(ctx, &geospatialv1.BatchReverseRequest{
+		Points: []*geospatialv1.LatLng{{Lat: -7.8, Lng: 110.4}, {Lat: -6.5, Lng: 107}, {Lat: 10, Lng: 10}, {Lat: 0, Lng: 110}},
+		Levels: []uint32{2},
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(batch.Results), 4)
+	assert.Equal(t, len(batch.Results[0].Regions), 1)
+	assert.Equal(t, len(batch.Results[1].Regions), 0)
+	assert.Equal(t, len(batch.Results[2].Regions), 0)
+	// A point on the equator is a point like any other.
+	assert.Equal(t, len(batch.Results[3].Regions), 0)
+
+	_, err = client.BatchReverse(ctx, &geospatialv1.BatchReverseRequest{})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+
+	children, err := client.Children(ctx, &geospatialv1.ChildrenRequest{Id: country.Id})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, receiveNames(t, children.Recv), []string{"Yogyakarta"})
+
+	children, err = client.Children(ctx, &geospatialv1.ChildrenRequest{Id: 999})
+	assert.Equal(t, err, nil)
+	_, err = children.Recv()
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	stream, err := client.StreamList(ctx, &geospatialv1.ListRequest{Sort: "-level"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, receiveNames(t, stream.Recv), []string{"Yogyakarta", "Indonesia"})
+
+	types, err := client.Types(ctx, &geospatialv1.ScopeRequest{Country: "idn"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(types.Types) > 0, true)
+
+	levels, err := client.Levels(ctx, &geospatialv1.ScopeRequest{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(levels.Levels), 2)
+	assert.Equal(t, levels.Levels[0].Count, int64(1))
+
+	_, err = client.Levels(ctx, &geospatialv1.ScopeRequest{Country: "Indonesia"})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+}
+
+func TestGeospatialRPCAuth(t *testing.T) {
+	authService := service.NewAuthService(repository.NewAPIKeyMemoryRepository(), "", "")
+	client := newRPCClient(t, authService, "reader", nil)
+	reader, err := authService.CreateKey(context.TODO(), "dashboard", "reader", model.RateLimit{})
+	assert.Equal(t, err, nil)
+
+	_, err = client.Types(context.TODO(), &geospatialv1.ScopeRequest{})
+	assert.Equal(t, status.Code(err), codes.Unauthenticated)
+
+	ctx := metadata.AppendToOutgoingContext(context.TODO(), "authorization", "Bearer gsk_unknown")
+	_, err = client.Types(ctx, &geospatialv1.ScopeRequest{})
+	assert.Equal(t, status.Code(err), codes.Unauthenticated)
+
+	ctx = metadata.AppendToOutgoingContext(context.TODO(), "authorization", "Bearer "+reader.Key)
+	_, err = client.Types(ctx, &geospatialv1.ScopeRequest{})
+	assert.Equal(t, err, nil)
+
+	stream, err := client.Children(context.TODO(), &geospatialv1.ChildrenRequest{})
+	assert.Equal(t, err, nil)
+	_, err = stream.Recv()
+	assert.Equal(t, status.Code(err), codes.Unauthenticated)
+}
+
+func TestGeospatialRPCRateLimit(t *testing.T) {
+	authService := service.NewAuthService(repository.NewAPIKeyMemoryRepository(), "", "")
+	rateLimitService := service.NewRateLimitService(ratelimit.Limit{Rate: 0.001, Burst: 12}, nil)
+	client := newRPCClient(t, authService, "", rateLimitService)
+	ctx := context.TODO()
+
+	var header metadata.MD
+	_, err := client.Types(ctx, &geospatialv1.ScopeRequest{}, grpc.Header(&header))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, header.Get("ratelimit-limit"), []string{"12"})
+	assert.Equal(t, header.Get("ratelimit-remaining"), []string{"11"})
+
+	// A batch reverse costs 10 tokens.
+	points := &geospatialv1.BatchReverseRequest{Points: []*geospatialv1.LatLng{{Lat: -7.8, Lng: 110.4}}}
+	_, err = client.BatchReverse(ctx, points, grpc.Header(&header))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, header.Get("ratelimit-remaining"), []string{"1"})
+
+	_, err = client.BatchReverse(ctx, points, grpc.Header(&header))
+	assert.Equal(t, status.Code(err), codes.ResourceExhausted)
+	assert.Equal(t, header.Get("retry-after"), []string{"9000"})
+
+	_, err = client.Types(ctx, &geospatialv1.ScopeRequest{})
+	assert.Equal(t, err, nil)
+}
+
+func TestGeospatialRPCInvalidCredentials(t *testing.T) {
+	authService := service.NewAuthService(repository.NewAPIKeyMemoryRepository(), "", "")
+	rateLimitService := service.NewRateLimitService(ratelimit.Limit{Rate: 0.001, Burst: 1}, nil)
+	client := newRPCClient(t, authService, "", rateLimitService)
+	ctx := metadata.AppendToOutgoingContext(context.TODO(), "authorization", "Bearer gsk_bogus")
+
+	_, err := client.Types(ctx, &geospatialv1.ScopeRequest{})
+	assert.Equal(t, status.Code(err), codes.Unauthenticated)
+	_, err = client.Types(ctx, &geospatialv1.ScopeRequest{})
+	assert.Equal(t, status.Code(err), codes.ResourceExhausted)
+
+	// The calls without credentials have their own bucket.
+	_, err = client.Types(context.TODO(), &geospatialv1.ScopeRequest{})
+	assert.Equal(t, err, nil)
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/config"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	repoMocks "github.com/si-bas/go-rest-geospatial/domain/repository/mocks"
+	"github.com/si-bas/go-rest-geospatial/pkg/export"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/stretchr/testify/mock"
+)
+
+// cursorAt matches the page params of the page after cursor.
+func cursorAt(cursor string) interface{} {
+	return mock.MatchedBy(func(param pagination.Param) bool { return param.UseCursor && param.Cursor == cursor })
+}
+
+func TestExport(t *testing.T) {
+	geospatials := []model.Geospatial{
+		{ID: 1, GadmID: "IDN", Name: "Indonesia", Type: "Country", Level: 1, Geometry: "MULTIPOLYGON(((95 -11,141 -11,141 6,95 6,95 -11)))"},
+	}
+	geospatials2 := []model.Geospatial{
+		{ID: 2, GadmID: "IDN.7_1", ParentGadmID: "IDN", Name: "Jakarta Raya", Type: "Propinsi", Level: 2, Geometry: "MULTIPOLYGON(((106 -7,107 -7,107 -6,106 -6,106 -7)))"},
+	}
+	exportFilter := model.GeospatialFilter{WithGeometry: true}
+	errRepo := errors.New("connection refused")
+
+	testCases := []struct {
+		name     string
+		format   string
+		mockFunc func(mock *geospatialMock)
+		wantRows int
+		wantErr  error
+	}{
+		{
+			name:   "happy flow",
+			format: constant.ExportFormatFlatGeobuf,
+			mockFunc: func(listMock *geospatialMock) {
+				listMock.geospatialRepo.On("GetPaginate", mock.Anything, exportFilter, cursorAt("")).Return(geospatials, &pagination.Param{}, nil)
+			},
+			wantRows: 1,
+		},
+		{
+			name:   "happy flow - read by page",
+			format: constant.ExportFormatGeoPackage,
+			mockFunc: func(listMock *geospatialMock) {
+				listMock.geospatialRepo.On("GetPaginate", mock.Anything, exportFilter, cursorAt("")).Return(geospatials, &pagination.Param{NextCursor: "next"}, nil)
+				listMock.geospatialRepo.On("GetPaginate", mock.Anything, exportFilter, cursorAt("next")).Return(geospatials2, &pagination.Param{}, nil)
+			},
+			wantRows: 2,
+		},
+		{
+			name:    "error - unsupported format",
+			format:  "shp",
+			wantErr: export.ErrUnsupportedFormat,
+		},
+		{
+			name:   "error - error get geospatial from repo",
+			format: constant.ExportFormatGeoPackage,
+			mockFunc: func(listMock *geospatialMock) {
+				listMock.geospatialRepo.On("GetPaginate", mock.Anything, exportFilter, cursorAt("")).Return(nil, nil, errRepo)
+			},
+			wantErr: errRepo,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			listMock := geospatialMock{
+				geospatialRepo: repoMocks.GeospatialRepository{},
+			}
+			if tc.mockFunc != nil {
+				tc.mockFunc(&listMock)
+			}
+
+			svc := service.NewExportService(&listMock.geospatialRepo, 1)
+			rows, err := svc.Export(context.TODO(), model.GeospatialFilter{}, tc.format, filepath.Join(t.TempDir(), "geospatial."+tc.format))
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRows, rows)
+			listMock.geospatialRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportJob(t *testing.T) {
+	config.Config = &config.Cfg{Export: config.Export{Dir: t.TempDir()}}
+
+	listMock := geospatialMock{
+		geospatialRepo: repoMocks.GeospatialRepository{},
+	}
+	// The first job holds the only slot until released.
+	release := make(chan time.Time)
+	listMock.geospatialRepo.On("GetPaginate", mock.Anything, model.GeospatialFilter{Levels: []uint{1}, WithGeometry: true}, cursorAt("")).
+		WaitUntil(release).Return([]model.Geospatial{}, &pagination.Param{}, nil)
+	listMock.geospatialRepo.On("GetPaginate", mock.Anything, model.GeospatialFilter{Levels: []uint{2}, WithGeometry: true}, cursorAt("")).
+		Return([]model.Geospatial{}, &pagination.Param{}, nil)
+
+	svc := service.NewExportService(&listMock.geospatialRepo, 1)
+	job, err := svc.CreateJob(context.TODO(), model.GeospatialFilter{Levels: []uint{1}}, constant.ExportFormatGeoPackage)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, constant.ExportStatusPending, job.Status)
+
+	waitFor(t, svc, job.ID, constant.ExportStatusRunning)
+	queued, err := svc.CreateJob(context.TODO(), model.GeospatialFilter{Levels: []uint{2}}, constant.ExportFormatFlatGeobuf)
+	assert.Equal(t, nil, err)
+	time.Sleep(50 * time.Millisecond)
+	queued, _ = svc.GetJob(context.TODO(), queued.ID)
+	assert.Equal(t, constant.ExportStatusPending, queued.Status)
+
+	close(release)
+	job = waitFor(t, svc, job.ID, constant.ExportStatusCompleted)
+	queued = waitFor(t, svc, queued.ID, constant.ExportStatusCompleted)
+
+	_, err = svc.GetJob(context.TODO(), "unknown")
+	assert.Equal(t, service.ErrExportJobNotFound, err)
+
+	// A job is kept until it expires, then it is dropped with its file.
+	_, err = os.Stat(job.FilePath)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, svc.Expire(context.TODO(), job.FinishedAt.Add(-time.Second)))
+	assert.Equal(t, 2, svc.Expire(context.TODO(), queued.FinishedAt.Add(time.Second)))
+
+	_, err = svc.GetJob(context.TODO(), job.ID)
+	assert.Equal(t, service.ErrExportJobNotFound, err)
+	_, err = os.Stat(job.FilePath)
+	assert.Equal(t, true, os.IsNotExist(err))
+}
+
+// waitFor polls the job with the given id until it has status, for up to 5 seconds.
+func waitFor(t *testing.T, svc service.ExportService, id, status string) *model.ExportJob {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := svc.GetJob(context.TODO(), id)
+		assert.Equal(t, nil, err)
+		if job.Status == status || time.Now().After(deadline) {
+			assert.Equal(t, status, job.Status)
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,225 @@
+package test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	repoMocks "github.com/si-bas/go-rest-geospatial/domain/repository/mocks"
+	pkggorm "github.com/si-bas/go-rest-geospatial/pkg/gorm"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/twpayne/go-geom"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type recordingEmitter struct {
+	events []model.GeofenceEvent
+}
+
+func (e *recordingEmitter) Emit(ctx context.Context, events []model.GeofenceEvent) error {
+	e.events = append(e.events, events...)
+	return nil
+}
+
+func eventTypes(events []model.GeofenceEvent) []string {
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type+" "+e.GeofenceName)
+	}
+	return types
+}
+
+func position(objectID string, lat, lng float64, at *time.Time) model.GeofencePosition {
+	return model.GeofencePosition{ObjectID: objectID, Lat: &lat, Lng: &lng, At: at}
+}
+
+// pointLat returns the latitude of the point the regions of filter contain, 0 without one.
+func pointLat(filter model.GeospatialFilter) float64 {
+	if point := filter.Point(); point != nil {
+		return point.Lat
+	}
+	return 0
+}
+
+func TestGeofenceTrack(t *testing.T) {
+	ctx := context.TODO()
+
+	// Region 7 is everything south of latitude -6.5.
+	geospatialRepo := &repoMocks.GeospatialRepository{}
+	geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{7}, Columns: []string{"id", "name"}}).
+		Return([]model.Geospatial{{ID: 7, Name: "South Jakarta"}}, nil)
+	geospatialRepo.On("Get", mock.Anything, mock.MatchedBy(func(f model.GeospatialFilter) bool { return pointLat(f) < -6.5 })).
+		Return([]model.Geospatial{{ID: 7}}, nil)
+	geospatialRepo.On("Get", mock.Anything, mock.Anything).Return([]model.Geospatial{}, nil)
+
+	emitter := &recordingEmitter{}
+	svc := service.NewGeofenceService(repository.NewGeofenceMemoryRepository(), geospatialRepo, emitter)
+
+	regionID := uint(7)
+	region, err := svc.Create(ctx, model.Geofence{GeospatialID: &regionID}, nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, region.Name, "South Jakarta")
+
+	depotShape := geom.NewPolygonFlat(geom.XY, []float64{106, -6.6, 107, -6.6, 107, -6, 106, -6, 106, -6.6}, []int{10})
+	depot, err := svc.Create(ctx, model.Geofence{Name: "Depot", DwellSeconds: 300}, depotShape)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(depot.Shape), `{"type":"MultiPolygon","coordinates":[[[[106,-6.6],[107,-6.6],[107,-6],[106,-6],[106,-6.6]]]]}`)
+
+	missing := uint(8)
+	_, err = svc.Create(ctx, model.Geofence{GeospatialID: &missing}, nil)
+	assert.Equal(t, err, service.ErrGeospatialNotFound)
+
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		t := start.Add(time.Duration(minutes) * time.Minute)
+		return &t
+	}
+
+	// Posted out of order: the object enters the depot, dwells there, crosses into the region
+	// and leaves the depot.
+	tracking, err := svc.Track(ctx, []model.GeofencePosition{
+		position("truck-1", -6.55, 106.5, at(6)),
+		position("truck-1", -6.2, 106.5, at(0)),
+		position("truck-1", -6.3, 106.5, at(5)),
+		position("truck-1", -6.8, 106.5, at(7)),
+	}, true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, tracking.Ignored, 0)
+	assert.Equal(t, eventTypes(tracking.Events), []string{"enter Depot", "dwell Depot", "enter South Jakarta", "exit Depot"})
+	assert.Equal(t, tracking.Events[1].At, *at(5))
+	assert.Equal(t, emitter.events, tracking.Events)
+
+	status, err := svc.Object(ctx, "truck-1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, status.Lat, -6.8)
+	assert.Equal(t, len(status.Fences), 1)
+	assert.Equal(t, status.Fences[0].GeofenceID, region.ID)
+	assert.Equal(t, status.Fences[0].EnteredAt, *at(6))
+
+	// A position older than the last one is ignored, events are not emitted without emit.
+	tracking, err = svc.Track(ctx, []model.GeofencePosition{
+		position("truck-1", -6.2, 106.5, at(3)),
+		position("truck-1", -6.2, 106.5, at(8)),
+	}, false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, tracking.Ignored, 1)
+	assert.Equal(t, eventTypes(tracking.Events), []string{"exit South Jakarta", "enter Depot"})
+	assert.Equal(t, len(emitter.events), 4)
+
+	// Deleting a fence drops it without an exit event.
+	assert.Equal(t, svc.Delete(ctx, depot.ID), nil)
+	tracking, err = svc.Track(ctx, []model.GeofencePosition{position("truck-1", -7, 106.5, at(9))}, false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, eventTypes(tracking.Events), []string{"enter South Jakarta"})
+
+	assert.Equal(t, svc.Delete(ctx, depot.ID), service.ErrGeofenceNotFound)
+	_, err = svc.Object(ctx, "truck-2")
+	assert.Equal(t, err, service.ErrGeofenceObjectNotFound)
+}
+
+func TestGeofenceTrackConcurrent(t *testing.T) {
+	ctx := context.TODO()
+
+	geospatialRepo := &repoMocks.GeospatialRepository{}
+	geospatialRepo.On("Get", mock.Anything, mock.Anything).Return([]model.Geospatial{}, nil)
+
+	svc := service.NewGeofenceService(repository.NewGeofenceMemoryRepository(), geospatialRepo, &recordingEmitter{})
+	depotShape := geom.NewPolygonFlat(geom.XY, []float64{106, -6.6, 107, -6.6, 107, -6, 106, -6, 106, -6.6}, []int{10})
+	_, err := svc.Create(ctx, model.Geofence{Name: "Depot"}, depotShape)
+	assert.Equal(t, err, nil)
+
+	// Positions of the same object posted at once enter the depot only once. Fewer posts than
+	// attempts never run out of attempts, each failed attempt is another post saved.
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var events []model.GeofenceEvent
+	for i := 0; i < 4; i++ {
+		at := start.Add(time.Duration(i) * time.Second)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracking, err := svc.Track(ctx, []model.GeofencePosition{position("truck-1", -6.2, 106.5, &at)}, false)
+			assert.Equal(t, err, nil)
+			mu.Lock()
+			events = append(events, tracking.Events...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, eventTypes(events), []string{"enter Depot"})
+}
+
+func TestGeofenceTrackEquator(t *testing.T) {
+	ctx := context.TODO()
+
+	geospatialRepo := &repoMocks.GeospatialRepository{}
+	geospatialRepo.On("Get", mock.Anything, mock.Anything).Return([]model.Geospatial{}, nil)
+
+	svc := service.NewGeofenceService(repository.NewGeofenceMemoryRepository(), geospatialRepo, &recordingEmitter{})
+	// Pontianak lies on the equator.
+	cityShape := geom.NewPolygonFlat(geom.XY, []float64{109.2, -0.1, 109.5, -0.1, 109.5, 0.1, 109.2, 0.1, 109.2, -0.1}, []int{10})
+	_, err := svc.Create(ctx, model.Geofence{Name: "Pontianak"}, cityShape)
+	assert.Equal(t, err, nil)
+
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	later := start.Add(time.Minute)
+	lat := 0.05
+	tracking, err := svc.Track(ctx, []model.GeofencePosition{
+		position("boat-1", 0, 109.3, &start),
+		// A position without a longitude is ignored.
+		{ObjectID: "boat-1", Lat: &lat, At: &later},
+	}, false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, tracking.Ignored, 1)
+	assert.Equal(t, eventTypes(tracking.Events), []string{"enter Pontianak"})
+}
+
+func TestGeofenceTrackBorder(t *testing.T) {
+	ctx := context.TODO()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "geospatial.sqlite")), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, pkggorm.MigrateSqlite(db), nil)
+
+	// The region and the polygon fence have the same shape.
+	minLng, minLat, maxLng, maxLat := 106.0, -6.5, 107.0, -6.0
+	geospatialRepo := repository.NewGeospatialRepository(db)
+	err = geospatialRepo.UpsertBulk(ctx, []model.Geospatial{
+		{GadmID: "IDN", Name: "Depot Region", Level: 1, Geometry: "MULTIPOLYGON(((106 -6.5,107 -6.5,107 -6,106 -6,106 -6.5)))",
+			BboxMinLng: &minLng, BboxMinLat: &minLat, BboxMaxLng: &maxLng, BboxMaxLat: &maxLat},
+	})
+	assert.Equal(t, err, nil)
+
+	svc := service.NewGeofenceService(repository.NewGeofenceRepository(db), geospatialRepo, &recordingEmitter{})
+	regionID := uint(1)
+	_, err = svc.Create(ctx, model.Geofence{GeospatialID: &regionID}, nil)
+	assert.Equal(t, err, nil)
+	depotShape := geom.NewPolygonFlat(geom.XY, []float64{106, -6.5, 107, -6.5, 107, -6, 106, -6, 106, -6.5}, []int{10})
+	_, err = svc.Create(ctx, model.Geofence{Name: "Depot"}, depotShape)
+	assert.Equal(t, err, nil)
+
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	later := start.Add(time.Minute)
+
+	// A point on the border is in both kinds of fence or in neither, whatever the dialect
+	// makes of the border.
+	tracking, err := svc.Track(ctx, []model.GeofencePosition{position("truck-1", -6.5, 106.5, &start)}, false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, eventTypes(tracking.Events), []string{"enter Depot Region", "enter Depot"})
+
+	tracking, err = svc.Track(ctx, []model.GeofencePosition{position("truck-1", -6.6, 106.5, &later)}, false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, eventTypes(tracking.Events), []string{"exit Depot Region", "exit Depot"})
+}
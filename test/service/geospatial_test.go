@@ -3,21 +3,26 @@ package test
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"reflect"
 	"testing"
 
 	"github.com/go-playground/assert/v2"
 	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
 	repoMocks "github.com/si-bas/go-rest-geospatial/domain/repository/mocks"
 	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
 	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
 	"github.com/stretchr/testify/mock"
+	"github.com/twpayne/go-geom"
 	"github.com/twpayne/go-geom/encoding/geojson"
 	"gorm.io/gorm"
 )
 
 type geospatialMock struct {
 	geospatialRepo repoMocks.GeospatialRepository
+	eventRepo      repoMocks.EventRepository
 }
 
 func TestGeospatialList(t *testing.T) {
@@ -62,7 +67,7 @@ func TestGeospatialList(t *testing.T) {
 				tc.mockFunc(&listMock)
 			}
 
-			svc := service.NewGeospatialService(&listMock.geospatialRepo)
+			svc := service.NewGeospatialService(&listMock.geospatialRepo, &listMock.eventRepo)
 			result, err := svc.List(context.TODO(), model.GeospatialFilter{})
 
 			assert.Equal(t, tc.wantErr, err)
@@ -117,7 +122,7 @@ func TestGeospatialListPaginate(t *testing.T) {
 				tc.mockFunc(&listMock)
 			}
 
-			svc := service.NewGeospatialService(&listMock.geospatialRepo)
+			svc := service.NewGeospatialService(&listMock.geospatialRepo, &listMock.eventRepo)
 			result, _, err := svc.ListPaginate(context.TODO(), model.GeospatialFilter{}, pagination.Param{})
 
 			assert.Equal(t, tc.wantErr, err)
@@ -130,29 +135,71 @@ func TestGeospatialListPaginate(t *testing.T) {
 	}
 }
 
+var typeCounts = []model.GeospatialTypeCount{
+	{Country: "IDN", EngType: "COUNTRY", Type: "Country", Level: 1, Count: 1},
+	{Country: "MYS", EngType: "COUNTRY", Type: "Country", Level: 1, Count: 1},
+	{Country: "IDN", EngType: "OTHER", Type: "Daerah Istimewa", Level: 2, Count: 1},
+	{Country: "IDN", EngType: "PROVINCE", Type: "Propinsi", Level: 2, Count: 33},
+	{Country: "IDN", EngType: "STATE", Type: "Negeri", Level: 2, Count: 13},
+	{Country: "MYS", EngType: "STATE", Type: "Negeri", Level: 2, Count: 13},
+	{Country: "IDN", EngType: "CITY", Type: "Kota", Level: 3, Count: 98},
+	{Country: "IDN", EngType: "REGENCY", Type: "Kabupaten", Level: 3, Count: 416},
+	{Country: "IDN", EngType: "REGENCY", Type: "Kabupaten Administrasi", Level: 3, Count: 1},
+}
+
+var typeCountries = []model.Geospatial{
+	{ID: 1, GadmID: "IDN", Name: "Indonesia", Level: 1},
+	{ID: 2, GadmID: "MYS", Name: "Malaysia", Level: 1},
+}
+
 func TestGeospatialGetTypes(t *testing.T) {
+	indonesia := model.GeospatialCountry{Code: "IDN", Name: "Indonesia"}
+	malaysia := model.GeospatialCountry{Code: "MYS", Name: "Malaysia"}
+
 	testCases := []struct {
 		name     string
 		mockFunc func(mock *geospatialMock)
+		want     []model.GeospatialType
 		wantErr  error
 	}{
 		{
 			name: "error - error get types from repo",
 			mockFunc: func(listMock *geospatialMock) {
-				listMock.geospatialRepo.On("GetTypes", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+				listMock.geospatialRepo.On("GetTypes", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
 			},
 			wantErr: gorm.ErrRecordNotFound,
 		},
 		{
 			name: "happy flow - data is empty",
 			mockFunc: func(listMock *geospatialMock) {
-				listMock.geospatialRepo.On("GetTypes", mock.Anything).Return(nil, nil)
+				listMock.geospatialRepo.On("GetTypes", mock.Anything, mock.Anything).Return(nil, nil)
 			},
 		},
 		{
 			name: "happy flow",
 			mockFunc: func(listMock *geospatialMock) {
-				listMock.geospatialRepo.On("GetTypes", mock.Anything).Return([]string{"A", "B", "C"}, nil)
+				listMock.geospatialRepo.On("GetTypes", mock.Anything, model.GeospatialFilter{Country: "IDN"}).Return(typeCounts, nil)
+				listMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN", "MYS"}).Return(typeCountries, nil)
+			},
+			want: []model.GeospatialType{
+				{EngType: "COUNTRY", Count: 2, Countries: []model.GeospatialCountry{indonesia, malaysia}, Levels: []model.GeospatialTypeLevel{
+					{Level: 1, Count: 2, LocalTypes: []string{"Country"}},
+				}},
+				{EngType: "STATE", Count: 26, Countries: []model.GeospatialCountry{indonesia, malaysia}, Levels: []model.GeospatialTypeLevel{
+					{Level: 2, Count: 26, LocalTypes: []string{"Negeri"}},
+				}},
+				{EngType: "PROVINCE", Count: 33, Countries: []model.GeospatialCountry{indonesia}, Levels: []model.GeospatialTypeLevel{
+					{Level: 2, Count: 33, LocalTypes: []string{"Propinsi"}},
+				}},
+				{EngType: "REGENCY", Count: 417, Countries: []model.GeospatialCountry{indonesia}, Levels: []model.GeospatialTypeLevel{
+					{Level: 3, Count: 417, LocalTypes: []string{"Kabupaten", "Kabupaten Administrasi"}},
+				}},
+				{EngType: "CITY", Count: 98, Countries: []model.GeospatialCountry{indonesia}, Levels: []model.GeospatialTypeLevel{
+					{Level: 3, Count: 98, LocalTypes: []string{"Kota"}},
+				}},
+				{EngType: "OTHER", Count: 1, Countries: []model.GeospatialCountry{indonesia}, Levels: []model.GeospatialTypeLevel{
+					{Level: 2, Count: 1, LocalTypes: []string{"Daerah Istimewa"}},
+				}},
 			},
 		},
 	}
@@ -167,38 +214,85 @@ func TestGeospatialGetTypes(t *testing.T) {
 				tc.mockFunc(&listMock)
 			}
 
-			svc := service.NewGeospatialService(&listMock.geospatialRepo)
-			_, err := svc.GetTypes(context.TODO())
+			svc := service.NewGeospatialService(&listMock.geospatialRepo, &listMock.eventRepo)
+			types, err := svc.GetTypes(context.TODO(), model.GeospatialFilter{Country: "IDN"})
 
 			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, types)
 			listMock.geospatialRepo.AssertExpectations(t)
 		})
 	}
 }
 
 func TestGeospatialGetLevels(t *testing.T) {
+	indonesia := model.GeospatialCountry{Code: "IDN", Name: "Indonesia"}
+	malaysia := model.GeospatialCountry{Code: "MYS", Name: "Malaysia"}
+	area := 2000.0
+
 	testCases := []struct {
 		name     string
 		mockFunc func(mock *geospatialMock)
+		want     []model.GeospatialLevel
 		wantErr  error
 	}{
 		{
 			name: "error - error get types from repo",
 			mockFunc: func(listMock *geospatialMock) {
-				listMock.geospatialRepo.On("GetLevels", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+				listMock.geospatialRepo.On("GetTypes", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
 			},
 			wantErr: gorm.ErrRecordNotFound,
 		},
 		{
 			name: "happy flow - data is empty",
 			mockFunc: func(listMock *geospatialMock) {
-				listMock.geospatialRepo.On("GetLevels", mock.Anything).Return(nil, nil)
+				listMock.geospatialRepo.On("GetTypes", mock.Anything, mock.Anything).Return(nil, nil)
+			},
+		},
+		{
+			name: "error - error get examples from repo",
+			mockFunc: func(listMock *geospatialMock) {
+				listMock.geospatialRepo.On("GetTypes", mock.Anything, mock.Anything).Return(typeCounts, nil)
+				listMock.geospatialRepo.On("GetByGadmIds", mock.Anything, mock.Anything).Return(typeCountries, nil)
+				listMock.geospatialRepo.On("GetExamples", mock.Anything, mock.Anything, 3).Return(nil, gorm.ErrInvalidDB).Once()
 			},
+			wantErr: gorm.ErrInvalidDB,
 		},
 		{
 			name: "happy flow",
 			mockFunc: func(listMock *geospatialMock) {
-				listMock.geospatialRepo.On("GetLevels", mock.Anything).Return([]uint{1, 2, 3}, nil)
+				listMock.geospatialRepo.On("GetTypes", mock.Anything, model.GeospatialFilter{AncestorID: 7}).Return(typeCounts, nil)
+				listMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN", "MYS"}).Return(typeCountries, nil)
+				listMock.geospatialRepo.On("GetExamples", mock.Anything, model.GeospatialFilter{AncestorID: 7, Levels: []uint{1}}, 3).
+					Return([]model.Geospatial{{ID: 1, Name: "Indonesia", Type: "Country"}}, nil)
+				listMock.geospatialRepo.On("GetExamples", mock.Anything, model.GeospatialFilter{AncestorID: 7, Levels: []uint{2}}, 3).
+					Return([]model.Geospatial{{ID: 9, Name: "Papua", Type: "Propinsi", AreaKm2: &area}}, nil)
+				listMock.geospatialRepo.On("GetExamples", mock.Anything, model.GeospatialFilter{AncestorID: 7, Levels: []uint{3}}, 3).
+					Return(nil, nil)
+			},
+			want: []model.GeospatialLevel{
+				{
+					Level: 1, Count: 2, Countries: []model.GeospatialCountry{indonesia, malaysia},
+					Types:    []model.GeospatialLevelType{{Type: "Country", EngType: "COUNTRY", Count: 2}},
+					Examples: []model.GeospatialExample{{ID: 1, Name: "Indonesia", Type: "Country"}},
+				},
+				{
+					Level: 2, Count: 60, Countries: []model.GeospatialCountry{indonesia, malaysia},
+					Types: []model.GeospatialLevelType{
+						{Type: "Daerah Istimewa", EngType: "OTHER", Count: 1},
+						{Type: "Propinsi", EngType: "PROVINCE", Count: 33},
+						{Type: "Negeri", EngType: "STATE", Count: 26},
+					},
+					Examples: []model.GeospatialExample{{ID: 9, Name: "Papua", Type: "Propinsi", AreaKm2: &area}},
+				},
+				{
+					Level: 3, Count: 515, Countries: []model.GeospatialCountry{indonesia},
+					Types: []model.GeospatialLevelType{
+						{Type: "Kota", EngType: "CITY", Count: 98},
+						{Type: "Kabupaten", EngType: "REGENCY", Count: 416},
+						{Type: "Kabupaten Administrasi", EngType: "REGENCY", Count: 1},
+					},
+					Examples: []model.GeospatialExample{},
+				},
 			},
 		},
 	}
@@ -213,16 +307,25 @@ func TestGeospatialGetLevels(t *testing.T) {
 				tc.mockFunc(&listMock)
 			}
 
-			svc := service.NewGeospatialService(&listMock.geospatialRepo)
-			_, err := svc.GetLevels(context.TODO())
+			svc := service.NewGeospatialService(&listMock.geospatialRepo, &listMock.eventRepo)
+			levels, err := svc.GetLevels(context.TODO(), model.GeospatialFilter{AncestorID: 7})
 
 			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, levels)
 			listMock.geospatialRepo.AssertExpectations(t)
 		})
 	}
 }
 
 func TestCreateFromFeatureCollection(t *testing.T) {
+	createdEvent := mock.MatchedBy(func(events []model.OutboxEvent) bool {
+		return len(events) == 1 && events[0].Type == constant.EventRegionCreated &&
+			events[0].Payload == `{"gadm_id":"IDN","parent_gadm_id":"","name":"Indonesia","type":"Country","eng_type":"COUNTRY","level":1}`
+	})
+	importEvent := func(eventType, payload string) interface{} {
+		return []model.OutboxEvent{{Type: eventType, Payload: payload}}
+	}
+
 	testCases := []struct {
 		name     string
 		mockFunc func(mock *geospatialMock)
@@ -231,7 +334,54 @@ func TestCreateFromFeatureCollection(t *testing.T) {
 		{
 			name: "Happy flow",
 			mockFunc: func(listMock *geospatialMock) {
-				listMock.geospatialRepo.On("UpsertBulk", mock.Anything, mock.Anything).Return(nil)
+				listMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN"}).Return([]model.Geospatial{}, nil)
+				listMock.geospatialRepo.On("UpsertBulk", mock.Anything, mock.Anything, createdEvent).Return(nil)
+				listMock.geospatialRepo.On("IndexNeighbors", mock.Anything).Return(nil)
+				listMock.eventRepo.On("AddEvents", mock.Anything, importEvent(constant.EventImportCompleted, `{"features":1,"created":1,"updated":0}`)).Return(nil)
+			},
+		},
+		{
+			name: "Happy flow - existing regions are updated",
+			mockFunc: func(listMock *geospatialMock) {
+				listMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN"}).Return([]model.Geospatial{{ID: 1, GadmID: "IDN"}}, nil)
+				listMock.geospatialRepo.On("UpsertBulk", mock.Anything, mock.Anything, mock.MatchedBy(func(events []model.OutboxEvent) bool {
+					return len(events) == 1 && events[0].Type == constant.EventRegionUpdated
+				})).Return(nil)
+				listMock.geospatialRepo.On("IndexNeighbors", mock.Anything).Return(nil)
+				listMock.eventRepo.On("AddEvents", mock.Anything, importEvent(constant.EventImportCompleted, `{"features":1,"created":0,"updated":1}`)).Return(nil)
+			},
+		},
+		{
+			name: "Failed to index neighbors",
+			mockFunc: func(listMock *geospatialMock) {
+				listMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN"}).Return([]model.Geospatial{}, nil)
+				listMock.geospatialRepo.On("UpsertBulk", mock.Anything, mock.Anything, createdEvent).Return(nil)
+				listMock.geospatialRepo.On("IndexNeighbors", mock.Anything).Return(gorm.ErrInvalidDB)
+				listMock.eventRepo.On("AddEvents", mock.Anything, importEvent(constant.EventImportFailed, `{"features":1,"created":1,"updated":0,"error":"invalid db"}`)).Return(nil)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name: "Failed to upsert, the outbox failing too is only logged",
+			mockFunc: func(listMock *geospatialMock) {
+				listMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN"}).Return([]model.Geospatial{}, nil)
+				listMock.geospatialRepo.On("UpsertBulk", mock.Anything, mock.Anything, createdEvent).Return(gorm.ErrInvalidTransaction)
+				listMock.eventRepo.On("AddEvents", mock.Anything, importEvent(constant.EventImportFailed, `{"features":1,"created":0,"updated":0,"error":"invalid transaction"}`)).Return(gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidTransaction,
+		},
+		{
+			name: "Happy flow - geometry attributes are computed",
+			mockFunc: func(listMock *geospatialMock) {
+				listMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN"}).Return([]model.Geospatial{}, nil)
+				listMock.geospatialRepo.On("UpsertBulk", mock.Anything, mock.MatchedBy(func(data []model.Geospatial) bool {
+					g := data[0]
+					return g.AreaKm2 != nil && *g.AreaKm2 > 0 &&
+						g.PerimeterKm != nil && *g.PerimeterKm > 0 &&
+						g.Centroid() != nil && g.LabelPoint() != nil && len(g.Bbox()) == 4
+				}), mock.Anything).Return(nil)
+				listMock.geospatialRepo.On("IndexNeighbors", mock.Anything).Return(nil)
+				listMock.eventRepo.On("AddEvents", mock.Anything, mock.Anything).Return(nil)
 			},
 		},
 	}
@@ -252,15 +402,127 @@ func TestCreateFromFeatureCollection(t *testing.T) {
 				tc.mockFunc(&listMock)
 			}
 
-			svc := service.NewGeospatialService(&listMock.geospatialRepo)
+			svc := service.NewGeospatialService(&listMock.geospatialRepo, &listMock.eventRepo)
 			err := svc.CreateFromFeatureCollection(context.TODO(), &features)
 
 			assert.Equal(t, tc.wantErr, err)
 			listMock.geospatialRepo.AssertExpectations(t)
+			listMock.eventRepo.AssertExpectations(t)
 		})
 	}
 }
 
+func TestGeospatialCreateAlternateNames(t *testing.T) {
+	gadmGeoJSON := `{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"GID_0":"IDN","GID_1":"IDN.34_1","COUNTRY":"Indonesia","NAME_1":"Yogyakarta","VARNAME_1":"Jogjakarta|Daerah Istimewa Yogyakarta","NL_NAME_1":"NA","TYPE_1":"Daerah Istimewa"},"geometry":{"type":"MultiPolygon","coordinates":[[[[110,-8],[111,-8],[111,-7.5],[110,-8]]]]}},
+{"type":"Feature","properties":{"GID_0":"CHN","GID_1":"CHN.2_1","COUNTRY":"China","NAME_1":"Beijing","NL_NAME_1":"北京|北京市","TYPE_1":"Zhíxiáshì","names":[{"name":"Peking","language":"en","kind":"historical"}]},"geometry":{"type":"MultiPolygon","coordinates":[[[[116,39],[117,39],[117,40],[116,39]]]]}}]}`
+
+	var features geojson.FeatureCollection
+	json.Unmarshal([]byte(gadmGeoJSON), &features)
+
+	namesMock := geospatialMock{
+		geospatialRepo: repoMocks.GeospatialRepository{},
+	}
+	namesMock.geospatialRepo.On("GetByGadmIds", mock.Anything, mock.Anything).Return([]model.Geospatial{}, nil)
+	namesMock.geospatialRepo.On("UpsertBulk", mock.Anything, mock.MatchedBy(func(data []model.Geospatial) bool {
+		return reflect.DeepEqual(data[0].Names, []model.GeospatialName{
+			{Name: "Jogjakarta", Kind: "variant"},
+			{Name: "Daerah Istimewa Yogyakarta", Kind: "variant"},
+		}) && reflect.DeepEqual(data[1].Names, []model.GeospatialName{
+			{Name: "北京", Language: "local", Kind: "official"},
+			{Name: "北京市", Language: "local", Kind: "official"},
+			{Name: "Peking", Language: "en", Kind: "historical"},
+		})
+	}), mock.Anything).Return(nil)
+	namesMock.geospatialRepo.On("IndexNeighbors", mock.Anything).Return(nil)
+	namesMock.eventRepo.On("AddEvents", mock.Anything, mock.Anything).Return(nil)
+
+	svc := service.NewGeospatialService(&namesMock.geospatialRepo, &namesMock.eventRepo)
+	err := svc.CreateFromFeatureCollection(context.TODO(), &features)
+
+	assert.Equal(t, err, nil)
+	namesMock.geospatialRepo.AssertExpectations(t)
+}
+
+func TestGeospatialLocalize(t *testing.T) {
+	geospatials := []model.Geospatial{
+		{ID: 1, Name: "Beijing"},
+		{ID: 2, Name: "Jakarta Raya"},
+	}
+
+	localizeMock := geospatialMock{
+		geospatialRepo: repoMocks.GeospatialRepository{},
+	}
+	localizeMock.geospatialRepo.On("GetNames", mock.Anything, []uint{1, 2}).Return([]model.GeospatialName{
+		{GeospatialID: 1, Name: "Peking", Language: "zh", Kind: "historical"},
+		{GeospatialID: 1, Name: "Beijing Shi", Language: "zh-Latn", Kind: "variant"},
+		{GeospatialID: 1, Name: "北京", Language: "zh", Kind: "official"},
+		{GeospatialID: 2, Name: "Djakarta", Language: "id", Kind: "historical"},
+	}, nil)
+
+	svc := service.NewGeospatialService(&localizeMock.geospatialRepo, &localizeMock.eventRepo)
+	result, err := svc.Localize(context.TODO(), geospatials, "zh")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result[0].Name, "北京")
+	assert.Equal(t, result[0].OfficialName, "Beijing")
+	assert.Equal(t, result[1].Name, "Jakarta Raya")
+	assert.Equal(t, result[1].OfficialName, "")
+	assert.Equal(t, geospatials[0].Name, "Beijing")
+	localizeMock.geospatialRepo.AssertExpectations(t)
+}
+
+func TestGeospatialGet(t *testing.T) {
+	getMock := geospatialMock{
+		geospatialRepo: repoMocks.GeospatialRepository{},
+	}
+	getMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{1}}).Return([]model.Geospatial{{ID: 1, Name: "Jakarta"}}, nil)
+	getMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{9}}).Return([]model.Geospatial{}, nil)
+
+	svc := service.NewGeospatialService(&getMock.geospatialRepo, &getMock.eventRepo)
+
+	result, err := svc.Get(context.TODO(), 1, model.GeospatialFilter{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result.Name, "Jakarta")
+
+	_, err = svc.Get(context.TODO(), 9, model.GeospatialFilter{})
+	assert.Equal(t, err, service.ErrGeospatialNotFound)
+	getMock.geospatialRepo.AssertExpectations(t)
+}
+
+func TestGeospatialInclude(t *testing.T) {
+	geospatials := []model.Geospatial{
+		{ID: 2, GadmID: "IDN.1_1", ParentGadmID: "IDN", Name: "Jakarta Raya", Geometry: "MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)))"},
+		{ID: 3, GadmID: "IDN.2_1", ParentGadmID: "IDN", Name: "Banten"},
+	}
+
+	includeMock := geospatialMock{
+		geospatialRepo: repoMocks.GeospatialRepository{},
+	}
+	includeMock.geospatialRepo.On("GetWithGeometry", mock.Anything, model.GeospatialFilter{IDs: []uint{3}, Columns: []string{"id"}}).
+		Return([]model.Geospatial{{ID: 3, Geometry: "MULTIPOLYGON (((2 2, 3 2, 3 3, 2 2)))"}}, nil)
+	includeMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN", "IDN"}).
+		Return([]model.Geospatial{{ID: 1, GadmID: "IDN", Name: "Indonesia", Type: "Country", EngType: "COUNTRY", Level: 1}}, nil)
+	includeMock.geospatialRepo.On("Get", mock.Anything, mock.MatchedBy(func(filter model.GeospatialFilter) bool {
+		return reflect.DeepEqual(filter.ParentIds, []uint{2, 3})
+	})).Return([]model.Geospatial{
+		{ID: 5, ParentGadmID: "IDN.1_1", Name: "Jakarta Utara", Level: 3},
+		{ID: 4, ParentGadmID: "IDN.1_1", Name: "Jakarta Selatan", Level: 3},
+	}, nil)
+
+	svc := service.NewGeospatialService(&includeMock.geospatialRepo, &includeMock.eventRepo)
+	result, err := svc.Include(context.TODO(), geospatials, []string{model.IncludeGeometry, model.IncludeParent, model.IncludeChildren})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(result[0].Shape), `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]]]}`)
+	assert.Equal(t, string(result[1].Shape), `{"type":"MultiPolygon","coordinates":[[[[2,2],[3,2],[3,3],[2,2]]]]}`)
+	assert.Equal(t, result[0].Parent.Name, "Indonesia")
+	assert.Equal(t, result[1].Parent.ID, uint(1))
+	assert.Equal(t, result[0].Children, []model.GeospatialRef{{ID: 4, Name: "Jakarta Selatan", Level: 3}, {ID: 5, Name: "Jakarta Utara", Level: 3}})
+	assert.Equal(t, len(result[1].Children), 0)
+	assert.Equal(t, geospatials[1].Geometry, "")
+	includeMock.geospatialRepo.AssertExpectations(t)
+}
+
 func TestGeospatialBuildTree(t *testing.T) {
 	geos := []model.Geospatial{
 		{ID: 1, GadmID: "1", ParentGadmID: "", Name: "A", Type: "Country", Level: 1},
@@ -287,10 +549,316 @@ func TestGeospatialBuildTree(t *testing.T) {
 	listMock := geospatialMock{
 		geospatialRepo: repoMocks.GeospatialRepository{},
 	}
-	svc := service.NewGeospatialService(&listMock.geospatialRepo)
+	svc := service.NewGeospatialService(&listMock.geospatialRepo, &listMock.eventRepo)
 	result := svc.BuildTree(geos, rootLevel)
 
 	if !reflect.DeepEqual(result, expectedGeo) {
 		t.Errorf("Expected %v but got %v", expectedGeo, result)
 	}
 }
+
+func TestGeospatialSearch(t *testing.T) {
+	score := 1.0
+	geospatials := []model.Geospatial{
+		{ID: 1, Name: "Jakarta", Score: &score, Match: "exact"},
+	}
+
+	testCases := []struct {
+		name     string
+		mockFunc func(mock *geospatialMock)
+		want     []model.Geospatial
+		wantErr  error
+	}{
+		{
+			name: "happy flow",
+			mockFunc: func(searchMock *geospatialMock) {
+				searchMock.geospatialRepo.On("Search", mock.Anything, "jakarta", model.GeospatialFilter{}, 10).Return(geospatials, nil)
+			},
+			want: geospatials,
+		},
+		{
+			name: "error - error search geospatial from repo",
+			mockFunc: func(searchMock *geospatialMock) {
+				searchMock.geospatialRepo.On("Search", mock.Anything, "jakarta", model.GeospatialFilter{}, 10).Return(nil, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			searchMock := geospatialMock{
+				geospatialRepo: repoMocks.GeospatialRepository{},
+			}
+			tc.mockFunc(&searchMock)
+
+			svc := service.NewGeospatialService(&searchMock.geospatialRepo, &searchMock.eventRepo)
+			result, err := svc.Search(context.TODO(), "jakarta", model.GeospatialFilter{}, 10)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, result)
+			searchMock.geospatialRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGeospatialAutocomplete(t *testing.T) {
+	prefixScore, wordScore := 0.78, 0.72
+	lat, lng := -6.2, 106.8
+	matches := []model.Geospatial{
+		{ID: 10, GadmID: "IDN.7.1.1_1", ParentGadmID: "IDN.7.1_1", Name: "Kebayoran Baru", Type: "Kecamatan", Level: 4, Score: &prefixScore, Match: "prefix"},
+		{ID: 20, GadmID: "IDN.9.1.1_1", ParentGadmID: "IDN.9.1_1", Name: "Kebayoran", Type: "Desa", Level: 4, Score: &wordScore, Match: "prefix", LabelLat: &lat, LabelLng: &lng},
+	}
+	parents := []model.Geospatial{
+		{ID: 3, GadmID: "IDN.7.1_1", ParentGadmID: "IDN.7_1", Name: "Jakarta Selatan", Type: "Kota", Level: 3},
+		{ID: 4, GadmID: "IDN.9.1_1", ParentGadmID: "IDN.9_1", Name: "Bogor", Type: "Kabupaten", Level: 3},
+	}
+	grandparents := []model.Geospatial{
+		{ID: 2, GadmID: "IDN.7_1", ParentGadmID: "IDN", Name: "Jakarta Raya", Type: "Propinsi", Level: 2},
+		{ID: 5, GadmID: "IDN.9_1", ParentGadmID: "IDN", Name: "Jawa Barat", Type: "Propinsi", Level: 2},
+	}
+	countries := []model.Geospatial{
+		{ID: 1, GadmID: "IDN", Name: "Indonesia", Type: "Country", Level: 1},
+	}
+
+	testCases := []struct {
+		name       string
+		opts       model.AutocompleteOptions
+		wantLabels []string
+	}{
+		{
+			name:       "ranked by match",
+			wantLabels: []string{"Kebayoran Baru, Jakarta Selatan, Jakarta Raya", "Kebayoran, Bogor, Jawa Barat"},
+		},
+		{
+			name:       "biased toward a parent",
+			opts:       model.AutocompleteOptions{ParentID: 5},
+			wantLabels: []string{"Kebayoran, Bogor, Jawa Barat", "Kebayoran Baru, Jakarta Selatan, Jakarta Raya"},
+		},
+		{
+			name:       "biased toward a point",
+			opts:       model.AutocompleteOptions{Point: &model.LatLng{Lat: -6.2, Lng: 106.8}},
+			wantLabels: []string{"Kebayoran, Bogor, Jawa Barat", "Kebayoran Baru, Jakarta Selatan, Jakarta Raya"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			autocompleteMock := geospatialMock{
+				geospatialRepo: repoMocks.GeospatialRepository{},
+			}
+			autocompleteMock.geospatialRepo.On("Search", mock.Anything, "kebayoran", model.GeospatialFilter{}, 6).Return(matches, nil)
+			autocompleteMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN.7.1_1", "IDN.9.1_1"}).Return(parents, nil)
+			autocompleteMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN.7_1", "IDN.9_1"}).Return(grandparents, nil)
+			autocompleteMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"IDN"}).Return(countries, nil)
+
+			svc := service.NewGeospatialService(&autocompleteMock.geospatialRepo, &autocompleteMock.eventRepo)
+			suggestions, err := svc.Autocomplete(context.TODO(), "kebayoran", model.GeospatialFilter{}, tc.opts, 2)
+			assert.Equal(t, err, nil)
+
+			var labels []string
+			for _, s := range suggestions {
+				labels = append(labels, s.Label)
+			}
+			assert.Equal(t, labels, tc.wantLabels)
+			assert.Equal(t, len(suggestions[0].Ancestors), 3)
+			assert.Equal(t, suggestions[0].Ancestors[2].Name, "Indonesia")
+		})
+	}
+}
+
+func TestGeospatialIntersecting(t *testing.T) {
+	var input geom.T
+	if err := geojson.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,2],[0,2],[0,0]]]}`), &input); err != nil {
+		t.Fatal(err)
+	}
+
+	intersectsMock := geospatialMock{
+		geospatialRepo: repoMocks.GeospatialRepository{},
+	}
+	intersectsMock.geospatialRepo.On("GetPaginate", mock.Anything, model.GeospatialFilter{
+		Intersects:   "POLYGON ((0 0, 2 0, 2 2, 0 2, 0 0))",
+		WithGeometry: true,
+	}, pagination.Param{Limit: 10}).Return([]model.Geospatial{
+		{ID: 1, Name: "Around", Geometry: "MULTIPOLYGON (((-1 -1, 3 -1, 3 3, -1 3, -1 -1)))"},
+		{ID: 2, Name: "Half", Geometry: "MULTIPOLYGON (((1 0, 3 0, 3 2, 1 2, 1 0)))"},
+	}, &pagination.Param{Limit: 10}, nil)
+
+	svc := service.NewGeospatialService(&intersectsMock.geospatialRepo, &intersectsMock.eventRepo)
+	result, _, err := svc.Intersecting(context.TODO(), input, model.GeospatialFilter{}, pagination.Param{Limit: 10}, true)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, math.Round(*result[0].OverlapPct), 100.0)
+	assert.Equal(t, math.Round(*result[1].OverlapPct), 50.0)
+	assert.Equal(t, math.Round(*result[1].OverlapKm2), math.Round(*result[0].OverlapKm2/2))
+	assert.Equal(t, result[0].OverlapKm, (*float64)(nil))
+	intersectsMock.geospatialRepo.AssertExpectations(t)
+}
+
+func TestGeospatialNeighbors(t *testing.T) {
+	neighborsMock := geospatialMock{
+		geospatialRepo: repoMocks.GeospatialRepository{},
+	}
+	neighborsMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{2}, Columns: []string{"id"}}).
+		Return([]model.Geospatial{{ID: 2}}, nil)
+	neighborsMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{9}, Columns: []string{"id"}}).
+		Return([]model.Geospatial{}, nil)
+	neighborsMock.geospatialRepo.On("GetNeighbors", mock.Anything, uint(2)).Return([]model.GeospatialNeighbor{
+		{GeospatialID: 2, NeighborID: 3, BorderKm: 111},
+		{GeospatialID: 2, NeighborID: 5, BorderKm: 10},
+	}, nil)
+	neighborsMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{3, 5}}).
+		Return([]model.Geospatial{{ID: 5, Name: "Jawa Barat"}, {ID: 3, Name: "Banten"}}, nil)
+
+	svc := service.NewGeospatialService(&neighborsMock.geospatialRepo, &neighborsMock.eventRepo)
+
+	result, err := svc.Neighbors(context.TODO(), 2, model.GeospatialFilter{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(result), 2)
+	assert.Equal(t, result[0].Name, "Banten")
+	assert.Equal(t, *result[0].BorderKm, 111.0)
+	assert.Equal(t, result[1].Name, "Jawa Barat")
+
+	_, err = svc.Neighbors(context.TODO(), 9, model.GeospatialFilter{})
+	assert.Equal(t, err, service.ErrGeospatialNotFound)
+	neighborsMock.geospatialRepo.AssertExpectations(t)
+}
+
+func TestGeospatialUnion(t *testing.T) {
+	filter := model.GeospatialFilter{IDs: []uint{2, 3, 4}}
+	columns := []string{"id", "gadm_id", "parent_gadm_id", "level"}
+
+	unionMock := geospatialMock{
+		geospatialRepo: repoMocks.GeospatialRepository{},
+	}
+	unionMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{2, 3, 4}, Columns: columns}).Return([]model.Geospatial{
+		{ID: 2, GadmID: "IDN.7_1", ParentGadmID: "IDN", Level: 2},
+		{ID: 3, GadmID: "IDN.3_1", ParentGadmID: "IDN", Level: 2},
+		{ID: 4, GadmID: "IDN.7.1_1", ParentGadmID: "IDN.7_1", Level: 3},
+	}, nil)
+	unionMock.geospatialRepo.On("GetWithGeometry", mock.Anything, model.GeospatialFilter{IDs: []uint{2, 3}, Columns: []string{"id"}}).Return([]model.Geospatial{
+		{ID: 2, Geometry: "MULTIPOLYGON (((106 -7, 107 -7, 107 -6, 106 -6, 106 -7)))"},
+		{ID: 3, Geometry: "MULTIPOLYGON (((105 -7, 106 -7, 106 -6, 105 -6, 105 -7)))"},
+	}, nil)
+	unionMock.geospatialRepo.On("UpsertBulk", mock.Anything, mock.MatchedBy(func(data []model.Geospatial) bool {
+		g := data[0]
+		return g.GadmID == "CUSTOM.west-java-sales" && g.Name == "West Java Sales" && g.EngType == "CUSTOM" &&
+			g.Level == 2 && g.ParentGadmID == "IDN" && g.AreaKm2 != nil
+	}), mock.MatchedBy(func(events []model.OutboxEvent) bool {
+		// The area was saved before under the same name.
+		return len(events) == 1 && events[0].Type == constant.EventRegionUpdated
+	})).Return(nil)
+	unionMock.geospatialRepo.On("IndexNeighbors", mock.Anything).Return(nil)
+	unionMock.geospatialRepo.On("GetByGadmIds", mock.Anything, []string{"CUSTOM.west-java-sales"}).
+		Return([]model.Geospatial{{ID: 10, GadmID: "CUSTOM.west-java-sales", Name: "West Java Sales"}}, nil)
+	unionMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{10}}).
+		Return([]model.Geospatial{{ID: 10, GadmID: "CUSTOM.west-java-sales", Name: "West Java Sales", EngType: "CUSTOM"}}, nil)
+
+	svc := service.NewGeospatialService(&unionMock.geospatialRepo, &unionMock.eventRepo)
+
+	union, err := svc.Union(context.TODO(), filter, "West Java Sales")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, union.RegionIDs, []uint{2, 3})
+	assert.Equal(t, union.Bbox, []float64{105, -7, 107, -6})
+	assert.Equal(t, string(union.Geometry), `{"type":"MultiPolygon","coordinates":[[[[106,-7],[107,-7],[107,-6],[106,-6],[105,-6],[105,-7],[106,-7]]]]}`)
+	assert.Equal(t, union.Saved.ID, uint(10))
+	unionMock.geospatialRepo.AssertExpectations(t)
+
+	_, err = svc.Union(context.TODO(), filter, "!!!")
+	assert.Equal(t, err, service.ErrInvalidCustomName)
+}
+
+func TestDeleteCustom(t *testing.T) {
+	retiredEvent := mock.MatchedBy(func(events []model.OutboxEvent) bool {
+		return len(events) == 1 && events[0].Type == constant.EventRegionRetired &&
+			events[0].Payload == `{"gadm_id":"CUSTOM.west-java-sales","parent_gadm_id":"IDN","name":"West Java Sales","type":"Custom","eng_type":"CUSTOM","level":2}`
+	})
+
+	testCases := []struct {
+		name     string
+		mockFunc func(mock *geospatialMock)
+		wantErr  error
+	}{
+		{
+			name: "Happy flow",
+			mockFunc: func(deleteMock *geospatialMock) {
+				deleteMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{10}}).Return([]model.Geospatial{
+					{ID: 10, GadmID: "CUSTOM.west-java-sales", ParentGadmID: "IDN", Name: "West Java Sales", Type: "Custom", EngType: "CUSTOM", Level: 2},
+				}, nil)
+				deleteMock.geospatialRepo.On("Delete", mock.Anything, []uint{10}, retiredEvent).Return(nil)
+			},
+		},
+		{
+			name: "Not found",
+			mockFunc: func(deleteMock *geospatialMock) {
+				deleteMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{10}}).Return([]model.Geospatial{}, nil)
+			},
+			wantErr: service.ErrGeospatialNotFound,
+		},
+		{
+			name: "Imported region",
+			mockFunc: func(deleteMock *geospatialMock) {
+				deleteMock.geospatialRepo.On("Get", mock.Anything, model.GeospatialFilter{IDs: []uint{10}}).Return([]model.Geospatial{
+					{ID: 10, GadmID: "IDN.7_1", Name: "Jawa Barat", EngType: "PROVINCE", Level: 2},
+				}, nil)
+			},
+			wantErr: service.ErrNotCustomArea,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			deleteMock := geospatialMock{
+				geospatialRepo: repoMocks.GeospatialRepository{},
+			}
+			tc.mockFunc(&deleteMock)
+
+			svc := service.NewGeospatialService(&deleteMock.geospatialRepo, &deleteMock.eventRepo)
+			err := svc.DeleteCustom(context.TODO(), 10)
+
+			assert.Equal(t, tc.wantErr, err)
+			deleteMock.geospatialRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateFromFeatureCollectionUnchanged(t *testing.T) {
+	ctx := context.TODO()
+	eventRepo := repository.NewEventMemoryRepository()
+	geospatialRepo, err := repository.NewGeospatialMemoryRepository("", eventRepo)
+	assert.Equal(t, err, nil)
+	svc := service.NewGeospatialService(geospatialRepo, eventRepo)
+
+	importFeature := func(name string) {
+		gadmGeoJSON := `{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"GID_0":"IDN","COUNTRY":"` + name + `"},"geometry":{"type":"MultiPolygon","coordinates":[[[[106,-7],[107,-7],[107,-6],[106,-7]]]]}}]}`
+		var features geojson.FeatureCollection
+		assert.Equal(t, json.Unmarshal([]byte(gadmGeoJSON), &features), nil)
+		assert.Equal(t, svc.CreateFromFeatureCollection(ctx, &features), nil)
+	}
+	eventTypes := func() []string {
+		events, err := eventRepo.GetEvents(ctx, []uint{1, 2, 3, 4, 5, 6, 7})
+		assert.Equal(t, err, nil)
+		var types []string
+		for _, e := range events {
+			types = append(types, e.Type)
+		}
+		return types
+	}
+
+	// Importing the same region again changes nothing, only the name of the last import does.
+	importFeature("Indonesia")
+	importFeature("Indonesia")
+	importFeature("Republic of Indonesia")
+	assert.Equal(t, eventTypes(), []string{
+		constant.EventRegionCreated, constant.EventImportCompleted,
+		constant.EventImportCompleted,
+		constant.EventRegionUpdated, constant.EventImportCompleted,
+	})
+
+	events, err := eventRepo.GetEvents(ctx, []uint{3})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, events[0].Payload, `{"features":1,"created":0,"updated":0}`)
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+)
+
+const zonesFixture = `{"type":"FeatureCollection","features":[
+{"type":"Feature","id":1,"properties":{"name":"North","courier":"A","capacity":20},"geometry":{"type":"Polygon","coordinates":[[[106,-6.5],[107,-6.5],[107,-6],[106,-6],[106,-6.5]]]}},
+{"type":"Feature","id":2,"properties":{"name":"South"},"geometry":{"type":"Polygon","coordinates":[[[106,-7],[107,-7],[107,-6.5],[106,-6.5],[106,-7]]]}},
+{"type":"Feature","id":2,"properties":{"name":"South West"},"geometry":{"type":"Polygon","coordinates":[[[106,-7],[106.5,-7],[106.5,-6.5],[106,-6.5],[106,-7]]]}}
+]}`
+
+func featureCollection(t *testing.T, data string) *geojson.FeatureCollection {
+	var fc geojson.FeatureCollection
+	if err := json.Unmarshal([]byte(data), &fc); err != nil {
+		t.Fatal(err)
+	}
+	return &fc
+}
+
+func TestLayerImport(t *testing.T) {
+	ctx := context.TODO()
+	svc := service.NewLayerService(repository.NewLayerMemoryRepository())
+
+	layer, err := svc.CreateFromFeatureCollection(ctx, "zones", featureCollection(t, zonesFixture))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, layer.Name, "zones")
+	// The second feature with id 2 replaces the first.
+	assert.Equal(t, layer.FeatureCount, int64(2))
+
+	features, meta, err := svc.Features(ctx, "zones", model.LayerFeatureFilter{Point: &model.LatLng{Lat: -6.2, Lng: 106.5}, WithGeometry: true}, pagination.Param{Limit: 10})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, meta.TotalRows, int64(1))
+	assert.Equal(t, *features[0].FeatureID, "1")
+	assert.Equal(t, features[0].Name, "North")
+	assert.Equal(t, features[0].Properties, `{"capacity":20,"courier":"A","name":"North"}`)
+	assert.Equal(t, string(features[0].Shape), `{"type":"MultiPolygon","coordinates":[[[[106,-6.5],[107,-6.5],[107,-6],[106,-6],[106,-6.5]]]]}`)
+
+	line := geom.NewLineStringFlat(geom.XY, []float64{106.8, -7, 106.8, -6})
+	features, _, err = svc.Intersecting(ctx, "zones", line, model.LayerFeatureFilter{}, pagination.Param{Limit: 10})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(features), 1)
+	assert.Equal(t, features[0].Name, "North")
+
+	_, _, err = svc.Features(ctx, "unknown", model.LayerFeatureFilter{}, pagination.Param{})
+	assert.Equal(t, err, service.ErrLayerNotFound)
+
+	points := `{"type":"FeatureCollection","features":[{"type":"Feature","properties":{},"geometry":{"type":"Point","coordinates":[106,-6]}}]}`
+	_, err = svc.CreateFromFeatureCollection(ctx, "stores", featureCollection(t, points))
+	assert.Equal(t, errors.Is(err, service.ErrInvalidLayerFeature), true)
+	// Nothing of a rejected import is written.
+	_, err = svc.Get(ctx, "stores")
+	assert.Equal(t, err, service.ErrLayerNotFound)
+
+	assert.Equal(t, svc.Delete(ctx, "zones"), nil)
+	layers, err := svc.List(ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(layers), 0)
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/si-bas/go-rest-geospatial/domain/model"
+	"github.com/si-bas/go-rest-geospatial/domain/repository"
+	"github.com/si-bas/go-rest-geospatial/service"
+	"github.com/si-bas/go-rest-geospatial/shared/constant"
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+	"github.com/twpayne/go-geom/encoding/geojson"
+)
+
+// webhookReceiver records the events it receives whose signature checks out.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	received []string
+	invalid  int
+}
+
+func (rc *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	signature := "sha256=" + service.SignWebhook(rc.secret, r.Header.Get(constant.WebhookTimestampHeader), body)
+	if r.Header.Get(constant.WebhookSignatureHeader) != signature {
+		rc.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload model.WebhookPayload
+	json.Unmarshal(body, &payload)
+	if payload.Type != r.Header.Get(constant.WebhookEventHeader) {
+		rc.invalid++
+	}
+	rc.received = append(rc.received, payload.Type+" "+string(payload.Data))
+	w.WriteHeader(rc.status)
+}
+
+func TestWebhookDispatch(t *testing.T) {
+	ctx := context.TODO()
+	eventRepo := repository.NewEventMemoryRepository()
+	geospatialRepo, err := repository.NewGeospatialMemoryRepository("", eventRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhookSvc := service.NewWebhookService(eventRepo, &http.Client{Timeout: 5 * time.Second}, 3)
+
+	ok := &webhookReceiver{status: http.StatusNoContent}
+	okServer := httptest.NewServer(ok)
+	defer okServer.Close()
+	down := &webhookReceiver{status: http.StatusServiceUnavailable}
+	downServer := httptest.NewServer(down)
+	defer downServer.Close()
+
+	all, err := webhookSvc.Create(ctx, model.WebhookRequest{URL: okServer.URL})
+	assert.Equal(t, err, nil)
+	ok.secret = all.Secret
+	failures, err := webhookSvc.Create(ctx, model.WebhookRequest{URL: okServer.URL, Events: []string{constant.EventImportFailed}})
+	assert.Equal(t, err, nil)
+	imports, err := webhookSvc.Create(ctx, model.WebhookRequest{URL: downServer.URL, Events: []string{constant.EventImportCompleted}})
+	assert.Equal(t, err, nil)
+	down.secret = imports.Secret
+
+	var features geojson.FeatureCollection
+	json.Unmarshal([]byte(`{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"GID_0":"IDN","COUNTRY":"Indonesia"},"geometry":{"type":"MultiPolygon","coordinates":[[[[106,-7],[107,-7],[107,-6],[106,-6],[106,-7]]]]}}]}`), &features)
+	err = service.NewGeospatialService(geospatialRepo, eventRepo).CreateFromFeatureCollection(ctx, &features)
+	assert.Equal(t, err, nil)
+
+	now := time.Now()
+	attempted, err := webhookSvc.Dispatch(ctx, now)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, attempted, 3)
+	assert.Equal(t, ok.invalid, 0)
+	assert.Equal(t, ok.received, []string{
+		`region.created {"gadm_id":"IDN","parent_gadm_id":"","name":"Indonesia","type":"Country","eng_type":"COUNTRY","level":1}`,
+		`import.completed {"features":1,"created":1,"updated":0}`,
+	})
+	assert.Equal(t, down.received, []string{`import.completed {"features":1,"created":1,"updated":0}`})
+
+	deliveries, _, err := webhookSvc.Deliveries(ctx, failures.ID, pagination.Param{Limit: 10})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(deliveries), 0)
+
+	deliveries, _, err = webhookSvc.Deliveries(ctx, imports.ID, pagination.Param{Limit: 10})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(deliveries), 1)
+	assert.Equal(t, deliveries[0].Status, constant.WebhookDeliveryPending)
+	assert.Equal(t, deliveries[0].Attempts, uint(1))
+	assert.Equal(t, *deliveries[0].StatusCode, http.StatusServiceUnavailable)
+	assert.Equal(t, strings.Contains(deliveries[0].Error, "503"), true)
+
+	// The failed delivery is retried once its backoff ran out, until it failed max attempts.
+	for _, tc := range []struct {
+		at        time.Duration
+		attempted int
+	}{
+		{at: 10 * time.Second, attempted: 0},
+		{at: 31 * time.Second, attempted: 1},
+		{at: 40 * time.Second, attempted: 0},
+		{at: 2 * time.Minute, attempted: 1},
+		{at: time.Hour, attempted: 0},
+	} {
+		attempted, err := webhookSvc.Dispatch(ctx, now.Add(tc.at))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, attempted, tc.attempted)
+	}
+
+	deliveries, _, err = webhookSvc.Deliveries(ctx, imports.ID, pagination.Param{Limit: 10})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, deliveries[0].Status, constant.WebhookDeliveryFailed)
+	assert.Equal(t, deliveries[0].Attempts, uint(3))
+	assert.Equal(t, len(down.received), 3)
+	assert.Equal(t, len(ok.received), 2)
+
+	deliveries, _, err = webhookSvc.Deliveries(ctx, all.ID, pagination.Param{Limit: 10})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, deliveries[0].Status, constant.WebhookDeliveryDelivered)
+	assert.Equal(t, *deliveries[0].StatusCode, http.StatusNoContent)
+
+	webhooks, err := webhookSvc.List(ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, webhooks[0].Secret, "")
+	assert.Equal(t, webhooks[1].EventTypes, []string{constant.EventImportFailed})
+
+	assert.Equal(t, webhookSvc.Delete(ctx, 42), service.ErrWebhookNotFound)
+}
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/si-bas/go-rest-geospatial/shared/helper/pagination"
+)
+
+func TestKeysetWhere(t *testing.T) {
+	columns := []pagination.KeysetColumn{
+		{Column: "level", Desc: false},
+		{Column: "area_km2", Desc: true, Nullable: true},
+		{Column: "id", Desc: true},
+	}
+
+	testCases := []struct {
+		name     string
+		values   []interface{}
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "values",
+			values:   []interface{}{2.0, 100.0, 7.0},
+			wantSQL:  "((level > ?) OR (level = ? AND (area_km2 < ? OR area_km2 IS NULL)) OR (level = ? AND area_km2 = ? AND id < ?))",
+			wantArgs: []interface{}{2.0, 2.0, 100.0, 2.0, 100.0, 7.0},
+		},
+		{
+			name:     "null sorts last when descending",
+			values:   []interface{}{2.0, nil, 7.0},
+			wantSQL:  "((level > ?) OR (level = ? AND area_km2 IS NULL AND id < ?))",
+			wantArgs: []interface{}{2.0, 2.0, 7.0},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args := pagination.KeysetWhere(columns, tc.values)
+			if sql != tc.wantSQL {
+				t.Errorf("got %q, want %q", sql, tc.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("got %v, want %v", args, tc.wantArgs)
+			}
+		})
+	}
+
+	if order := pagination.KeysetOrder(columns); order != "level ASC, area_km2 IS NULL ASC, area_km2 DESC, id DESC" {
+		t.Errorf("unexpected order %q", order)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	columns := []pagination.KeysetColumn{{Column: "name"}, {Column: "id", Desc: true}}
+
+	cursor := pagination.EncodeCursor(columns, []interface{}{"Banten", 3.0})
+	values, err := pagination.DecodeCursor(cursor, columns)
+	if err != nil || !reflect.DeepEqual(values, []interface{}{"Banten", 3.0}) {
+		t.Errorf("got %v, %v", values, err)
+	}
+
+	if _, err := pagination.DecodeCursor(cursor, columns[1:]); err != pagination.ErrInvalidCursor {
+		t.Errorf("expected a cursor of another sort to be rejected, got %v", err)
+	}
+}
+
+func TestParamMarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		param pagination.Param
+		want  string
+	}{
+		{
+			name:  "page mode",
+			param: pagination.Param{Limit: 10, Page: 2, TotalRows: 25, TotalPages: 3},
+			want:  `{"limit":10,"page":2,"total_rows":25,"total_pages":3}`,
+		},
+		{
+			name:  "cursor mode without totals",
+			param: pagination.Param{Limit: 10, UseCursor: true, SkipTotal: true, NextCursor: "abc"},
+			want:  `{"limit":10,"next_cursor":"abc"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.param)
+			if err != nil || string(data) != tc.want {
+				t.Errorf("got %s, %v, want %s", data, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	fields := map[string]string{"level": "level", "name": "name", "area": "area_km2", "area_km2": "area_km2"}
+
+	sorts, err := pagination.ParseSort("level, -name,+area", fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []pagination.ParamSort{
+		{Column: "level", Order: pagination.OrderAsc},
+		{Column: "name", Order: pagination.OrderDesc},
+		{Column: "area_km2", Order: pagination.OrderAsc},
+	}
+	if !reflect.DeepEqual(sorts, want) {
+		t.Errorf("got %v, want %v", sorts, want)
+	}
+
+	if sorts, err := pagination.ParseSort("", fields); err != nil || sorts != nil {
+		t.Errorf("got %v, %v, want no sort", sorts, err)
+	}
+
+	for _, value := range []string{"geometry", "level,", "-area,area_km2", "level;DROP TABLE geospatial"} {
+		if _, err := pagination.ParseSort(value, fields); err == nil {
+			t.Errorf("ParseSort(%q) returned no error", value)
+		}
+	}
+}